@@ -0,0 +1,522 @@
+package pinecone
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const defaultBackupPollInterval = 5 * time.Second
+
+var (
+	// ErrBackupNotFound is returned when a backup is not found.
+	ErrBackupNotFound = errors.New("backup not found")
+	// ErrBackupFailed is returned by WaitForBackup when the backup failed.
+	ErrBackupFailed = errors.New("backup failed")
+	// ErrRestoreJobNotFound is returned when a restore job is not found.
+	ErrRestoreJobNotFound = errors.New("restore job not found")
+	// ErrRestoreJobFailed is returned by WaitForRestoreJob when the restore job failed.
+	ErrRestoreJobFailed = errors.New("restore job failed")
+)
+
+// BackupStatus is the status of a backup.
+type BackupStatus string
+
+const (
+	BackupStatusInitializing BackupStatus = "Initializing"
+	BackupStatusPending      BackupStatus = "Pending"
+	BackupStatusReady        BackupStatus = "Ready"
+	BackupStatusFailed       BackupStatus = "Failed"
+)
+
+// Terminal reports whether the backup has stopped changing, whether it
+// succeeded or not.
+func (s BackupStatus) Terminal() bool {
+	return s == BackupStatusReady || s == BackupStatusFailed
+}
+
+// RestoreJobStatus is the status of a restore job.
+type RestoreJobStatus string
+
+const (
+	RestoreJobStatusPending    RestoreJobStatus = "Pending"
+	RestoreJobStatusInProgress RestoreJobStatus = "InProgress"
+	RestoreJobStatusCompleted  RestoreJobStatus = "Completed"
+	RestoreJobStatusFailed     RestoreJobStatus = "Failed"
+)
+
+// Terminal reports whether the restore job has stopped, whether it
+// succeeded or not.
+func (s RestoreJobStatus) Terminal() bool {
+	return s == RestoreJobStatusCompleted || s == RestoreJobStatusFailed
+}
+
+// Backup represents a backup of a serverless index.
+type Backup struct {
+	ID              string            `json:"backup_id"`
+	SourceIndexName string            `json:"source_index_name"`
+	SourceIndexID   string            `json:"source_index_id"`
+	Name            string            `json:"name,omitempty"`
+	Description     string            `json:"description,omitempty"`
+	Status          BackupStatus      `json:"status"`
+	Cloud           string            `json:"cloud"`
+	Region          string            `json:"region"`
+	Dimension       int               `json:"dimension,omitempty"`
+	Metric          CreateIndexMetric `json:"metric,omitempty"`
+	RecordCount     int64             `json:"record_count,omitempty"`
+	NamespaceCount  int64             `json:"namespace_count,omitempty"`
+	SizeBytes       int64             `json:"size_bytes,omitempty"`
+	Tags            map[string]string `json:"tags,omitempty"`
+	CreatedAt       *time.Time        `json:"created_at,omitempty"`
+}
+
+// RestoreJob represents the restoration of a backup into a new index.
+type RestoreJob struct {
+	ID              string           `json:"restore_job_id"`
+	BackupID        string           `json:"backup_id"`
+	TargetIndexName string           `json:"target_index_name"`
+	TargetIndexID   string           `json:"target_index_id"`
+	Status          RestoreJobStatus `json:"status"`
+	// The percentage of the restore job completed, from 0 to 100.
+	PercentComplete float64    `json:"percent_complete"`
+	CreatedAt       *time.Time `json:"created_at,omitempty"`
+	CompletedAt     *time.Time `json:"completed_at,omitempty"`
+}
+
+type createBackupBodyParams struct {
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// CreateBackup creates a backup of a serverless index. The name and the
+// description are optional. The backup is created asynchronously, use
+// WaitForBackup to wait until it is ready.
+//
+// API Reference: https://docs.pinecone.io/reference/api/2025-04/control-plane/create_backup
+func (c *Client) CreateBackup(ctx context.Context, indexName, name, description string) (*Backup, error) {
+	if indexName == "" {
+		return nil, fmt.Errorf("%w: index name is required", ErrInvalidParams)
+	}
+
+	var respBody Backup
+	resp, err := c.controlPlane.
+		R().
+		SetContentType("application/json").
+		SetBody(createBackupBodyParams{Name: name, Description: description}).
+		SetSuccessResult(&respBody).
+		SetContext(ctx).
+		Post("/indexes/" + url.PathEscape(indexName) + "/backups")
+	if err != nil {
+		return nil, err
+	}
+	if !resp.IsSuccessState() && resp.StatusCode == http.StatusNotFound {
+		return nil, ErrIndexNotFound
+	} else if !resp.IsSuccessState() {
+		buffer := new(bytes.Buffer)
+		_, err := buffer.ReadFrom(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, &RequestError{StatusCode: resp.StatusCode, Body: buffer.String()}
+	}
+
+	return &respBody, nil
+}
+
+// ListBackupsParams represents the parameters for a list backups request.
+type ListBackupsParams struct {
+	// The name of the index to list the backups of. Lists the backups of
+	// every index of the project when empty.
+	IndexName string
+	// The number of backups to return per page, at most 100. Defaults to 10.
+	Limit int
+	// The token of the page to list, as returned by the previous page.
+	PaginationToken string
+}
+
+// ListBackupsResponse represents the response from a list backups request.
+type ListBackupsResponse struct {
+	Backups []*Backup
+	// The token of the next page, empty on the last page.
+	NextPaginationToken string
+}
+
+type listBackupsResponseBody struct {
+	Data       []*Backup `json:"data"`
+	Pagination *struct {
+		Next string `json:"next"`
+	} `json:"pagination"`
+}
+
+// ListBackups lists a page of the backups of an index, or of the whole
+// project when no index name is given.
+//
+// API Reference: https://docs.pinecone.io/reference/api/2025-04/control-plane/list_index_backups
+func (c *Client) ListBackups(ctx context.Context, params ListBackupsParams) (*ListBackupsResponse, error) {
+	if params.Limit < 0 || params.Limit > 100 {
+		return nil, fmt.Errorf("%w: limit must be between 1 and 100", ErrInvalidParams)
+	}
+
+	path := "/backups"
+	if params.IndexName != "" {
+		path = "/indexes/" + url.PathEscape(params.IndexName) + "/backups"
+	}
+	if pathParams := buildPaginationPathParams(params.Limit, params.PaginationToken); pathParams != "" {
+		path += "?" + pathParams
+	}
+
+	var respBody listBackupsResponseBody
+	resp, err := c.controlPlane.
+		R().
+		SetContentType("application/json").
+		SetSuccessResult(&respBody).
+		SetContext(ctx).
+		Get(path)
+	if err != nil {
+		return nil, err
+	}
+	if !resp.IsSuccessState() && resp.StatusCode == http.StatusNotFound && params.IndexName != "" {
+		return nil, ErrIndexNotFound
+	} else if !resp.IsSuccessState() {
+		buffer := new(bytes.Buffer)
+		_, err := buffer.ReadFrom(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, &RequestError{StatusCode: resp.StatusCode, Body: buffer.String()}
+	}
+
+	listResp := &ListBackupsResponse{Backups: respBody.Data}
+	if listResp.Backups == nil {
+		listResp.Backups = make([]*Backup, 0)
+	}
+	if respBody.Pagination != nil {
+		listResp.NextPaginationToken = respBody.Pagination.Next
+	}
+
+	return listResp, nil
+}
+
+// DescribeBackup gets a description of a backup.
+//
+// API Reference: https://docs.pinecone.io/reference/api/2025-04/control-plane/describe_backup
+func (c *Client) DescribeBackup(ctx context.Context, backupID string) (*Backup, error) {
+	if backupID == "" {
+		return nil, fmt.Errorf("%w: backup id is required", ErrInvalidParams)
+	}
+
+	var respBody Backup
+	resp, err := c.controlPlane.
+		R().
+		SetContentType("application/json").
+		SetSuccessResult(&respBody).
+		SetContext(ctx).
+		Get("/backups/" + url.PathEscape(backupID))
+	if err != nil {
+		return nil, err
+	}
+	if !resp.IsSuccessState() && resp.StatusCode == http.StatusNotFound {
+		return nil, ErrBackupNotFound
+	} else if !resp.IsSuccessState() {
+		buffer := new(bytes.Buffer)
+		_, err := buffer.ReadFrom(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, &RequestError{StatusCode: resp.StatusCode, Body: buffer.String()}
+	}
+
+	return &respBody, nil
+}
+
+// DeleteBackup deletes a backup.
+//
+// API Reference: https://docs.pinecone.io/reference/api/2025-04/control-plane/delete_backup
+func (c *Client) DeleteBackup(ctx context.Context, backupID string) error {
+	if backupID == "" {
+		return fmt.Errorf("%w: backup id is required", ErrInvalidParams)
+	}
+
+	resp, err := c.controlPlane.
+		R().
+		SetContext(ctx).
+		Delete("/backups/" + url.PathEscape(backupID))
+	if err != nil {
+		return err
+	}
+	if !resp.IsSuccessState() && resp.StatusCode == http.StatusNotFound {
+		return ErrBackupNotFound
+	} else if !resp.IsSuccessState() {
+		buffer := new(bytes.Buffer)
+		_, err := buffer.ReadFrom(resp.Body)
+		if err != nil {
+			return err
+		}
+
+		return &RequestError{StatusCode: resp.StatusCode, Body: buffer.String()}
+	}
+
+	return nil
+}
+
+// CreateIndexFromBackupParams represents the parameters for a create index
+// from backup request.
+type CreateIndexFromBackupParams struct {
+	// Required. The ID of the backup to restore.
+	BackupID string
+	// Required. The name of the index to be created.
+	Name string
+	// The tags of the new index.
+	Tags map[string]string
+	// Whether the new index can be deleted, "enabled" or "disabled".
+	// Defaults to "disabled".
+	DeletionProtection string
+}
+
+type createIndexFromBackupBodyParams struct {
+	Name               string            `json:"name"`
+	Tags               map[string]string `json:"tags,omitempty"`
+	DeletionProtection string            `json:"deletion_protection,omitempty"`
+}
+
+// CreateIndexFromBackupResponse represents the response from a create
+// index from backup request.
+type CreateIndexFromBackupResponse struct {
+	RestoreJobID string `json:"restore_job_id"`
+	IndexID      string `json:"index_id"`
+}
+
+// CreateIndexFromBackup creates a new serverless index from a backup. The
+// index is restored asynchronously by a restore job, use WaitForRestoreJob
+// to wait until it completes.
+//
+// API Reference: https://docs.pinecone.io/reference/api/2025-04/control-plane/create_index_from_backup
+func (c *Client) CreateIndexFromBackup(ctx context.Context, params CreateIndexFromBackupParams) (*CreateIndexFromBackupResponse, error) {
+	if params.BackupID == "" {
+		return nil, fmt.Errorf("%w: backup id is required", ErrInvalidParams)
+	}
+	if params.Name == "" {
+		return nil, fmt.Errorf("%w: name is required", ErrInvalidParams)
+	}
+	if params.DeletionProtection != "" && params.DeletionProtection != "enabled" && params.DeletionProtection != "disabled" {
+		return nil, fmt.Errorf("%w: deletion protection must be enabled or disabled", ErrInvalidParams)
+	}
+
+	var respBody CreateIndexFromBackupResponse
+	resp, err := c.controlPlane.
+		R().
+		SetContentType("application/json").
+		SetBody(createIndexFromBackupBodyParams{
+			Name:               params.Name,
+			Tags:               params.Tags,
+			DeletionProtection: params.DeletionProtection,
+		}).
+		SetSuccessResult(&respBody).
+		SetContext(ctx).
+		Post("/backups/" + url.PathEscape(params.BackupID) + "/create-index")
+	if err != nil {
+		return nil, err
+	}
+	if !resp.IsSuccessState() && resp.StatusCode == http.StatusNotFound {
+		return nil, ErrBackupNotFound
+	} else if !resp.IsSuccessState() {
+		buffer := new(bytes.Buffer)
+		_, err := buffer.ReadFrom(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, &RequestError{StatusCode: resp.StatusCode, Body: buffer.String()}
+	}
+
+	return &respBody, nil
+}
+
+// ListRestoreJobsParams represents the parameters for a list restore jobs
+// request.
+type ListRestoreJobsParams struct {
+	// The number of restore jobs to return per page, at most 100. Defaults to 10.
+	Limit int
+	// The token of the page to list, as returned by the previous page.
+	PaginationToken string
+}
+
+// ListRestoreJobsResponse represents the response from a list restore jobs
+// request.
+type ListRestoreJobsResponse struct {
+	RestoreJobs []*RestoreJob
+	// The token of the next page, empty on the last page.
+	NextPaginationToken string
+}
+
+type listRestoreJobsResponseBody struct {
+	Data       []*RestoreJob `json:"data"`
+	Pagination *struct {
+		Next string `json:"next"`
+	} `json:"pagination"`
+}
+
+// ListRestoreJobs lists a page of the restore jobs of the project.
+//
+// API Reference: https://docs.pinecone.io/reference/api/2025-04/control-plane/list_restore_jobs
+func (c *Client) ListRestoreJobs(ctx context.Context, params ListRestoreJobsParams) (*ListRestoreJobsResponse, error) {
+	if params.Limit < 0 || params.Limit > 100 {
+		return nil, fmt.Errorf("%w: limit must be between 1 and 100", ErrInvalidParams)
+	}
+
+	path := "/restore-jobs"
+	if pathParams := buildPaginationPathParams(params.Limit, params.PaginationToken); pathParams != "" {
+		path += "?" + pathParams
+	}
+
+	var respBody listRestoreJobsResponseBody
+	resp, err := c.controlPlane.
+		R().
+		SetContentType("application/json").
+		SetSuccessResult(&respBody).
+		SetContext(ctx).
+		Get(path)
+	if err != nil {
+		return nil, err
+	}
+	if !resp.IsSuccessState() {
+		buffer := new(bytes.Buffer)
+		_, err := buffer.ReadFrom(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, &RequestError{StatusCode: resp.StatusCode, Body: buffer.String()}
+	}
+
+	listResp := &ListRestoreJobsResponse{RestoreJobs: respBody.Data}
+	if listResp.RestoreJobs == nil {
+		listResp.RestoreJobs = make([]*RestoreJob, 0)
+	}
+	if respBody.Pagination != nil {
+		listResp.NextPaginationToken = respBody.Pagination.Next
+	}
+
+	return listResp, nil
+}
+
+// DescribeRestoreJob gets a description of a restore job.
+//
+// API Reference: https://docs.pinecone.io/reference/api/2025-04/control-plane/describe_restore_job
+func (c *Client) DescribeRestoreJob(ctx context.Context, restoreJobID string) (*RestoreJob, error) {
+	if restoreJobID == "" {
+		return nil, fmt.Errorf("%w: restore job id is required", ErrInvalidParams)
+	}
+
+	var respBody RestoreJob
+	resp, err := c.controlPlane.
+		R().
+		SetContentType("application/json").
+		SetSuccessResult(&respBody).
+		SetContext(ctx).
+		Get("/restore-jobs/" + url.PathEscape(restoreJobID))
+	if err != nil {
+		return nil, err
+	}
+	if !resp.IsSuccessState() && resp.StatusCode == http.StatusNotFound {
+		return nil, ErrRestoreJobNotFound
+	} else if !resp.IsSuccessState() {
+		buffer := new(bytes.Buffer)
+		_, err := buffer.ReadFrom(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, &RequestError{StatusCode: resp.StatusCode, Body: buffer.String()}
+	}
+
+	return &respBody, nil
+}
+
+// WaitForBackupParams represents the parameters of WaitForBackup.
+type WaitForBackupParams struct {
+	// The interval between two polls. Defaults to 5 seconds.
+	PollInterval time.Duration
+	// Called with the backup after every poll.
+	OnProgress func(backup *Backup)
+}
+
+// WaitForBackup polls a backup until it is ready or failed, and returns its
+// last description. Failed backups are returned along with ErrBackupFailed.
+func (c *Client) WaitForBackup(ctx context.Context, backupID string, params WaitForBackupParams) (*Backup, error) {
+	interval := params.PollInterval
+	if interval <= 0 {
+		interval = defaultBackupPollInterval
+	}
+
+	var backup *Backup
+	err := poll(ctx, interval, func() (bool, error) {
+		var err error
+		backup, err = c.DescribeBackup(ctx, backupID)
+		if err != nil {
+			return false, err
+		}
+
+		if params.OnProgress != nil {
+			params.OnProgress(backup)
+		}
+
+		return backup.Status.Terminal(), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if backup.Status == BackupStatusFailed {
+		return backup, ErrBackupFailed
+	}
+
+	return backup, nil
+}
+
+// WaitForRestoreJobParams represents the parameters of WaitForRestoreJob.
+type WaitForRestoreJobParams struct {
+	// The interval between two polls. Defaults to 5 seconds.
+	PollInterval time.Duration
+	// Called with the restore job after every poll.
+	OnProgress func(restoreJob *RestoreJob)
+}
+
+// WaitForRestoreJob polls a restore job until it completes or fails, and
+// returns its last description. Failed restore jobs are returned along with
+// ErrRestoreJobFailed.
+func (c *Client) WaitForRestoreJob(ctx context.Context, restoreJobID string, params WaitForRestoreJobParams) (*RestoreJob, error) {
+	interval := params.PollInterval
+	if interval <= 0 {
+		interval = defaultBackupPollInterval
+	}
+
+	var restoreJob *RestoreJob
+	err := poll(ctx, interval, func() (bool, error) {
+		var err error
+		restoreJob, err = c.DescribeRestoreJob(ctx, restoreJobID)
+		if err != nil {
+			return false, err
+		}
+
+		if params.OnProgress != nil {
+			params.OnProgress(restoreJob)
+		}
+
+		return restoreJob.Status.Terminal(), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if restoreJob.Status == RestoreJobStatusFailed {
+		return restoreJob, ErrRestoreJobFailed
+	}
+
+	return restoreJob, nil
+}
@@ -0,0 +1,229 @@
+package pinecone
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_Backups(t *testing.T) {
+	var backupPolls, restorePolls atomic.Int32
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/indexes/my-index/backups", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, apiVersion, r.Header.Get("X-Pinecone-API-Version"))
+		assert.Equal(t, "key", r.Header.Get("Api-Key"))
+
+		switch r.Method {
+		case http.MethodPost:
+			var body map[string]any
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, map[string]any{"name": "nightly", "description": "nightly backup"}, body)
+
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"backup_id":"b1","source_index_name":"my-index","name":"nightly","status":"Initializing","cloud":"aws","region":"us-east-1"}`))
+		case http.MethodGet:
+			assert.Equal(t, "1", r.URL.Query().Get("limit"))
+			_, _ = w.Write([]byte(`{"data":[{"backup_id":"b1","status":"Ready"}],"pagination":{"next":"token"}}`))
+		}
+	})
+	mux.HandleFunc("/indexes/missing/backups", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	mux.HandleFunc("/backups", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "token", r.URL.Query().Get("paginationToken"))
+		_, _ = w.Write([]byte(`{"data":[{"backup_id":"b1","status":"Ready"},{"backup_id":"b2","status":"Failed"}]}`))
+	})
+	mux.HandleFunc("/backups/b1", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			switch backupPolls.Add(1) {
+			case 1:
+				_, _ = w.Write([]byte(`{"backup_id":"b1","status":"Initializing"}`))
+			case 2:
+				_, _ = w.Write([]byte(`{"backup_id":"b1","status":"Pending"}`))
+			default:
+				_, _ = w.Write([]byte(`{"backup_id":"b1","status":"Ready","dimension":3,"metric":"cosine","record_count":1000,"namespace_count":2,"created_at":"2025-01-01T00:00:00Z"}`))
+			}
+		case http.MethodDelete:
+			w.WriteHeader(http.StatusAccepted)
+		}
+	})
+	mux.HandleFunc("/backups/b2", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"backup_id":"b2","status":"Failed"}`))
+	})
+	mux.HandleFunc("/backups/b3", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	mux.HandleFunc("/backups/b3/create-index", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	mux.HandleFunc("/backups/b1/create-index", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, map[string]any{
+			"name":                "restored-index",
+			"tags":                map[string]any{"env": "dr"},
+			"deletion_protection": "enabled",
+		}, body)
+
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = w.Write([]byte(`{"restore_job_id":"r1","index_id":"i1"}`))
+	})
+	mux.HandleFunc("/restore-jobs", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "2", r.URL.Query().Get("limit"))
+		_, _ = w.Write([]byte(`{"data":[{"restore_job_id":"r1","backup_id":"b1","status":"Completed"}]}`))
+	})
+	mux.HandleFunc("/restore-jobs/r1", func(w http.ResponseWriter, r *http.Request) {
+		switch restorePolls.Add(1) {
+		case 1:
+			_, _ = w.Write([]byte(`{"restore_job_id":"r1","status":"Pending"}`))
+		case 2:
+			_, _ = w.Write([]byte(`{"restore_job_id":"r1","status":"InProgress","percent_complete":42.5}`))
+		default:
+			_, _ = w.Write([]byte(`{"restore_job_id":"r1","backup_id":"b1","target_index_name":"restored-index","status":"Completed","percent_complete":100,"completed_at":"2025-01-01T01:00:00Z"}`))
+		}
+	})
+	mux.HandleFunc("/restore-jobs/r2", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"restore_job_id":"r2","status":"Failed"}`))
+	})
+	mux.HandleFunc("/restore-jobs/r3", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	c, err := New(WithAPIKey("key"), WithControllerBaseURL(server.URL))
+	require.NoError(t, err)
+
+	t.Run("CreateBackup", func(t *testing.T) {
+		assert := assert.New(t)
+		require := require.New(t)
+
+		backup, err := c.CreateBackup(context.Background(), "my-index", "nightly", "nightly backup")
+		require.NoError(err)
+		assert.Equal("b1", backup.ID)
+		assert.Equal("my-index", backup.SourceIndexName)
+		assert.Equal(BackupStatusInitializing, backup.Status)
+
+		_, err = c.CreateBackup(context.Background(), "", "", "")
+		require.ErrorIs(err, ErrInvalidParams)
+	})
+
+	t.Run("ListBackups", func(t *testing.T) {
+		assert := assert.New(t)
+		require := require.New(t)
+
+		resp, err := c.ListBackups(context.Background(), ListBackupsParams{IndexName: "my-index", Limit: 1})
+		require.NoError(err)
+		require.Len(resp.Backups, 1)
+		assert.Equal("token", resp.NextPaginationToken)
+
+		resp, err = c.ListBackups(context.Background(), ListBackupsParams{PaginationToken: resp.NextPaginationToken})
+		require.NoError(err)
+		require.Len(resp.Backups, 2)
+		assert.Equal(BackupStatusFailed, resp.Backups[1].Status)
+		assert.Empty(resp.NextPaginationToken)
+
+		_, err = c.ListBackups(context.Background(), ListBackupsParams{IndexName: "missing"})
+		require.ErrorIs(err, ErrIndexNotFound)
+
+		_, err = c.ListBackups(context.Background(), ListBackupsParams{Limit: 101})
+		require.ErrorIs(err, ErrInvalidParams)
+	})
+
+	t.Run("WaitForBackup", func(t *testing.T) {
+		assert := assert.New(t)
+		require := require.New(t)
+
+		var statuses []BackupStatus
+		backup, err := c.WaitForBackup(context.Background(), "b1", WaitForBackupParams{
+			PollInterval: time.Millisecond,
+			OnProgress: func(backup *Backup) {
+				statuses = append(statuses, backup.Status)
+			},
+		})
+		require.NoError(err)
+		assert.Equal(BackupStatusReady, backup.Status)
+		assert.Equal(3, backup.Dimension)
+		assert.Equal(CreateIndexMetricCosine, backup.Metric)
+		assert.Equal(int64(1000), backup.RecordCount)
+		assert.Equal([]BackupStatus{BackupStatusInitializing, BackupStatusPending, BackupStatusReady}, statuses)
+
+		backup, err = c.WaitForBackup(context.Background(), "b2", WaitForBackupParams{PollInterval: time.Millisecond})
+		require.ErrorIs(err, ErrBackupFailed)
+		assert.Equal(BackupStatusFailed, backup.Status)
+
+		_, err = c.WaitForBackup(context.Background(), "b3", WaitForBackupParams{PollInterval: time.Millisecond})
+		require.ErrorIs(err, ErrBackupNotFound)
+	})
+
+	t.Run("CreateIndexFromBackup", func(t *testing.T) {
+		assert := assert.New(t)
+		require := require.New(t)
+
+		resp, err := c.CreateIndexFromBackup(context.Background(), CreateIndexFromBackupParams{
+			BackupID:           "b1",
+			Name:               "restored-index",
+			Tags:               map[string]string{"env": "dr"},
+			DeletionProtection: "enabled",
+		})
+		require.NoError(err)
+		assert.Equal("r1", resp.RestoreJobID)
+		assert.Equal("i1", resp.IndexID)
+
+		_, err = c.CreateIndexFromBackup(context.Background(), CreateIndexFromBackupParams{BackupID: "b3", Name: "restored-index"})
+		require.ErrorIs(err, ErrBackupNotFound)
+
+		_, err = c.CreateIndexFromBackup(context.Background(), CreateIndexFromBackupParams{BackupID: "b1"})
+		require.ErrorIs(err, ErrInvalidParams)
+
+		_, err = c.CreateIndexFromBackup(context.Background(), CreateIndexFromBackupParams{BackupID: "b1", Name: "restored-index", DeletionProtection: "on"})
+		require.ErrorIs(err, ErrInvalidParams)
+	})
+
+	t.Run("RestoreJobs", func(t *testing.T) {
+		assert := assert.New(t)
+		require := require.New(t)
+
+		resp, err := c.ListRestoreJobs(context.Background(), ListRestoreJobsParams{Limit: 2})
+		require.NoError(err)
+		require.Len(resp.RestoreJobs, 1)
+		assert.Equal(RestoreJobStatusCompleted, resp.RestoreJobs[0].Status)
+
+		var percentages []float64
+		restoreJob, err := c.WaitForRestoreJob(context.Background(), "r1", WaitForRestoreJobParams{
+			PollInterval: time.Millisecond,
+			OnProgress: func(restoreJob *RestoreJob) {
+				percentages = append(percentages, restoreJob.PercentComplete)
+			},
+		})
+		require.NoError(err)
+		assert.Equal(RestoreJobStatusCompleted, restoreJob.Status)
+		assert.Equal("restored-index", restoreJob.TargetIndexName)
+		require.NotNil(restoreJob.CompletedAt)
+		assert.Equal([]float64{0, 42.5, 100}, percentages)
+
+		_, err = c.WaitForRestoreJob(context.Background(), "r2", WaitForRestoreJobParams{PollInterval: time.Millisecond})
+		require.ErrorIs(err, ErrRestoreJobFailed)
+
+		_, err = c.DescribeRestoreJob(context.Background(), "r3")
+		require.ErrorIs(err, ErrRestoreJobNotFound)
+	})
+
+	t.Run("DeleteBackup", func(t *testing.T) {
+		require.NoError(t, c.DeleteBackup(context.Background(), "b1"))
+		require.ErrorIs(t, c.DeleteBackup(context.Background(), "b3"), ErrBackupNotFound)
+		require.ErrorIs(t, c.DeleteBackup(context.Background(), ""), ErrInvalidParams)
+	})
+}
@@ -0,0 +1,310 @@
+package pinecone
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultImportPollInterval = 5 * time.Second
+	bulkImportsPath           = "/bulk/imports"
+)
+
+var (
+	// ErrImportNotFound is returned when a bulk import is not found.
+	ErrImportNotFound = errors.New("import not found")
+	// ErrImportFailed is returned by WaitForImport when the import failed.
+	ErrImportFailed = errors.New("import failed")
+	// ErrImportCancelled is returned by WaitForImport when the import was cancelled.
+	ErrImportCancelled = errors.New("import cancelled")
+)
+
+// ImportErrorMode is the behavior of a bulk import when a record fails to import.
+type ImportErrorMode string
+
+const (
+	// ImportErrorModeAbort stops the import at the first failure.
+	ImportErrorModeAbort ImportErrorMode = "abort"
+	// ImportErrorModeContinue skips the records that fail to import.
+	ImportErrorModeContinue ImportErrorMode = "continue"
+)
+
+// ImportStatus is the status of a bulk import.
+type ImportStatus string
+
+const (
+	ImportStatusPending    ImportStatus = "Pending"
+	ImportStatusInProgress ImportStatus = "InProgress"
+	ImportStatusFailed     ImportStatus = "Failed"
+	ImportStatusCompleted  ImportStatus = "Completed"
+	ImportStatusCancelled  ImportStatus = "Cancelled"
+)
+
+// Terminal reports whether the import has stopped, whether it succeeded or not.
+func (s ImportStatus) Terminal() bool {
+	return s == ImportStatusFailed || s == ImportStatusCompleted || s == ImportStatusCancelled
+}
+
+type startImportBodyParams struct {
+	URI           string `json:"uri"`
+	IntegrationID string `json:"integrationId,omitempty"`
+	ErrorMode     *struct {
+		OnError ImportErrorMode `json:"onError"`
+	} `json:"errorMode,omitempty"`
+}
+
+// StartImportResponse represents the response from a start import request.
+type StartImportResponse struct {
+	ID string `json:"id"`
+}
+
+// StartImport starts an asynchronous bulk import of the Parquet files under
+// an object storage URI, e.g. s3://bucket/path/. The integration ID names
+// the storage integration granting access to private buckets, and may be
+// empty for public ones. An empty error mode uses the server default.
+//
+// API Reference: https://docs.pinecone.io/reference/api/2025-04/data-plane/start_import
+func (ic *IndexClient) StartImport(ctx context.Context, uri, integrationID string, errorMode ImportErrorMode) (*StartImportResponse, error) {
+	if uri == "" {
+		return nil, fmt.Errorf("%w: uri is required", ErrInvalidParams)
+	}
+	if errorMode != "" && errorMode != ImportErrorModeAbort && errorMode != ImportErrorModeContinue {
+		return nil, fmt.Errorf("%w: unknown error mode %q", ErrInvalidParams, errorMode)
+	}
+
+	body := startImportBodyParams{URI: uri, IntegrationID: integrationID}
+	if errorMode != "" {
+		body.ErrorMode = &struct {
+			OnError ImportErrorMode `json:"onError"`
+		}{OnError: errorMode}
+	}
+
+	var respBody StartImportResponse
+	resp, err := ic.reqClient.
+		R().
+		SetHeader("X-Pinecone-API-Version", apiVersion).
+		SetContentType("application/json").
+		SetBody(body).
+		SetSuccessResult(&respBody).
+		SetContext(ctx).
+		Post(bulkImportsPath)
+	if err != nil {
+		return nil, err
+	}
+	if !resp.IsSuccessState() {
+		buffer := new(bytes.Buffer)
+		_, err := buffer.ReadFrom(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, &RequestError{StatusCode: resp.StatusCode, Body: buffer.String()}
+	}
+
+	return &respBody, nil
+}
+
+// ImportDescription represents a bulk import.
+type ImportDescription struct {
+	ID     string       `json:"id"`
+	URI    string       `json:"uri"`
+	Status ImportStatus `json:"status"`
+	// The percentage of the import completed, from 0 to 100.
+	PercentComplete float64    `json:"percentComplete"`
+	RecordsImported int64      `json:"recordsImported"`
+	CreatedAt       *time.Time `json:"createdAt,omitempty"`
+	FinishedAt      *time.Time `json:"finishedAt,omitempty"`
+	// The reason of the failure of a failed import.
+	Error string `json:"error,omitempty"`
+}
+
+// DescribeImport gets a description of a bulk import.
+//
+// API Reference: https://docs.pinecone.io/reference/api/2025-04/data-plane/describe_import
+func (ic *IndexClient) DescribeImport(ctx context.Context, id string) (*ImportDescription, error) {
+	if id == "" {
+		return nil, fmt.Errorf("%w: id is required", ErrInvalidParams)
+	}
+
+	var respBody ImportDescription
+	resp, err := ic.reqClient.
+		R().
+		SetHeader("X-Pinecone-API-Version", apiVersion).
+		SetContentType("application/json").
+		SetSuccessResult(&respBody).
+		SetContext(ctx).
+		Get(bulkImportsPath + "/" + url.PathEscape(id))
+	if err != nil {
+		return nil, err
+	}
+	if !resp.IsSuccessState() && resp.StatusCode == http.StatusNotFound {
+		return nil, ErrImportNotFound
+	} else if !resp.IsSuccessState() {
+		buffer := new(bytes.Buffer)
+		_, err := buffer.ReadFrom(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, &RequestError{StatusCode: resp.StatusCode, Body: buffer.String()}
+	}
+
+	return &respBody, nil
+}
+
+// ListImportsParams represents the parameters for a list imports request.
+type ListImportsParams struct {
+	// The number of imports to return per page, at most 100. Defaults to 100.
+	Limit int
+	// The token of the page to list, as returned by the previous page.
+	PaginationToken string
+}
+
+// ListImportsResponse represents the response from a list imports request.
+type ListImportsResponse struct {
+	Imports []*ImportDescription
+	// The token of the next page, empty on the last page.
+	NextPaginationToken string
+}
+
+type listImportsResponseBody struct {
+	Data       []*ImportDescription `json:"data"`
+	Pagination *struct {
+		Next string `json:"next"`
+	} `json:"pagination"`
+}
+
+// ListImports lists a page of the bulk imports of the index, most recent first.
+//
+// API Reference: https://docs.pinecone.io/reference/api/2025-04/data-plane/list_imports
+func (ic *IndexClient) ListImports(ctx context.Context, params ListImportsParams) (*ListImportsResponse, error) {
+	if params.Limit < 0 || params.Limit > 100 {
+		return nil, fmt.Errorf("%w: limit must be between 1 and 100", ErrInvalidParams)
+	}
+
+	query := make(url.Values)
+	if params.Limit > 0 {
+		query.Set("limit", strconv.Itoa(params.Limit))
+	}
+	if params.PaginationToken != "" {
+		query.Set("paginationToken", params.PaginationToken)
+	}
+
+	path := bulkImportsPath
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	var respBody listImportsResponseBody
+	resp, err := ic.reqClient.
+		R().
+		SetHeader("X-Pinecone-API-Version", apiVersion).
+		SetContentType("application/json").
+		SetSuccessResult(&respBody).
+		SetContext(ctx).
+		Get(path)
+	if err != nil {
+		return nil, err
+	}
+	if !resp.IsSuccessState() {
+		buffer := new(bytes.Buffer)
+		_, err := buffer.ReadFrom(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, &RequestError{StatusCode: resp.StatusCode, Body: buffer.String()}
+	}
+
+	listResp := &ListImportsResponse{Imports: respBody.Data}
+	if listResp.Imports == nil {
+		listResp.Imports = make([]*ImportDescription, 0)
+	}
+	if respBody.Pagination != nil {
+		listResp.NextPaginationToken = respBody.Pagination.Next
+	}
+
+	return listResp, nil
+}
+
+// CancelImport cancels a bulk import that has not finished yet.
+//
+// API Reference: https://docs.pinecone.io/reference/api/2025-04/data-plane/cancel_import
+func (ic *IndexClient) CancelImport(ctx context.Context, id string) error {
+	if id == "" {
+		return fmt.Errorf("%w: id is required", ErrInvalidParams)
+	}
+
+	resp, err := ic.reqClient.
+		R().
+		SetHeader("X-Pinecone-API-Version", apiVersion).
+		SetContext(ctx).
+		Delete(bulkImportsPath + "/" + url.PathEscape(id))
+	if err != nil {
+		return err
+	}
+	if !resp.IsSuccessState() && resp.StatusCode == http.StatusNotFound {
+		return ErrImportNotFound
+	} else if !resp.IsSuccessState() {
+		buffer := new(bytes.Buffer)
+		_, err := buffer.ReadFrom(resp.Body)
+		if err != nil {
+			return err
+		}
+
+		return &RequestError{StatusCode: resp.StatusCode, Body: buffer.String()}
+	}
+
+	return nil
+}
+
+// WaitForImportParams represents the parameters of WaitForImport.
+type WaitForImportParams struct {
+	// The interval between two polls. Defaults to 5 seconds.
+	PollInterval time.Duration
+	// Called with the description of the import after every poll.
+	OnProgress func(description *ImportDescription)
+}
+
+// WaitForImport polls a bulk import until it reaches a terminal state, and
+// returns its last description. Failed and cancelled imports are returned
+// along with ErrImportFailed and ErrImportCancelled respectively.
+func (ic *IndexClient) WaitForImport(ctx context.Context, id string, params WaitForImportParams) (*ImportDescription, error) {
+	interval := params.PollInterval
+	if interval <= 0 {
+		interval = defaultImportPollInterval
+	}
+
+	var description *ImportDescription
+	err := poll(ctx, interval, func() (bool, error) {
+		var err error
+		description, err = ic.DescribeImport(ctx, id)
+		if err != nil {
+			return false, err
+		}
+
+		if params.OnProgress != nil {
+			params.OnProgress(description)
+		}
+
+		return description.Status.Terminal(), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	switch description.Status {
+	case ImportStatusFailed:
+		return description, fmt.Errorf("%w: %s", ErrImportFailed, description.Error)
+	case ImportStatusCancelled:
+		return description, ErrImportCancelled
+	default:
+		return description, nil
+	}
+}
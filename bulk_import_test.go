@@ -0,0 +1,128 @@
+package pinecone
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndexClient_BulkImport(t *testing.T) {
+	var polls atomic.Int32
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/bulk/imports", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, apiVersion, r.Header.Get("X-Pinecone-API-Version"))
+
+		switch r.Method {
+		case http.MethodPost:
+			var body map[string]any
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, map[string]any{
+				"uri":           "s3://bucket/path/",
+				"integrationId": "integration",
+				"errorMode":     map[string]any{"onError": "continue"},
+			}, body)
+
+			_, _ = w.Write([]byte(`{"id":"1"}`))
+		case http.MethodGet:
+			if r.URL.Query().Get("paginationToken") == "" {
+				assert.Equal(t, "1", r.URL.Query().Get("limit"))
+				_, _ = w.Write([]byte(`{"data":[{"id":"2","status":"Completed"}],"pagination":{"next":"token"}}`))
+				return
+			}
+
+			_, _ = w.Write([]byte(`{"data":[{"id":"1","status":"InProgress"}]}`))
+		}
+	})
+	mux.HandleFunc("/bulk/imports/1", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			switch polls.Add(1) {
+			case 1:
+				_, _ = w.Write([]byte(`{"id":"1","status":"Pending"}`))
+			case 2:
+				_, _ = w.Write([]byte(`{"id":"1","status":"InProgress","percentComplete":50,"recordsImported":500}`))
+			default:
+				_, _ = w.Write([]byte(`{"id":"1","status":"Completed","percentComplete":100,"recordsImported":1000,"createdAt":"2024-01-01T00:00:00Z"}`))
+			}
+		case http.MethodDelete:
+			_, _ = w.Write([]byte(`{}`))
+		}
+	})
+	mux.HandleFunc("/bulk/imports/2", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"id":"2","status":"Failed","error":"invalid parquet file"}`))
+	})
+	mux.HandleFunc("/bulk/imports/3", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	ic := newTestIndexClient(server.URL)
+
+	t.Run("StartImport", func(t *testing.T) {
+		resp, err := ic.StartImport(context.Background(), "s3://bucket/path/", "integration", ImportErrorModeContinue)
+		require.NoError(t, err)
+		assert.Equal(t, "1", resp.ID)
+
+		_, err = ic.StartImport(context.Background(), "", "", "")
+		require.ErrorIs(t, err, ErrInvalidParams)
+
+		_, err = ic.StartImport(context.Background(), "s3://bucket/path/", "", "ignore")
+		require.ErrorIs(t, err, ErrInvalidParams)
+	})
+
+	t.Run("ListImports", func(t *testing.T) {
+		assert := assert.New(t)
+		require := require.New(t)
+
+		resp, err := ic.ListImports(context.Background(), ListImportsParams{Limit: 1})
+		require.NoError(err)
+		require.Len(resp.Imports, 1)
+		assert.Equal("2", resp.Imports[0].ID)
+		assert.Equal("token", resp.NextPaginationToken)
+
+		resp, err = ic.ListImports(context.Background(), ListImportsParams{PaginationToken: resp.NextPaginationToken})
+		require.NoError(err)
+		require.Len(resp.Imports, 1)
+		assert.Empty(resp.NextPaginationToken)
+	})
+
+	t.Run("WaitForImport", func(t *testing.T) {
+		assert := assert.New(t)
+		require := require.New(t)
+
+		var statuses []ImportStatus
+		description, err := ic.WaitForImport(context.Background(), "1", WaitForImportParams{
+			PollInterval: time.Millisecond,
+			OnProgress: func(description *ImportDescription) {
+				statuses = append(statuses, description.Status)
+			},
+		})
+		require.NoError(err)
+		assert.Equal(ImportStatusCompleted, description.Status)
+		assert.Equal(int64(1000), description.RecordsImported)
+		assert.Equal(float64(100), description.PercentComplete)
+		assert.Equal([]ImportStatus{ImportStatusPending, ImportStatusInProgress, ImportStatusCompleted}, statuses)
+
+		description, err = ic.WaitForImport(context.Background(), "2", WaitForImportParams{PollInterval: time.Millisecond})
+		require.ErrorIs(err, ErrImportFailed)
+		assert.Equal("invalid parquet file", description.Error)
+	})
+
+	t.Run("CancelImport", func(t *testing.T) {
+		require.NoError(t, ic.CancelImport(context.Background(), "1"))
+		require.ErrorIs(t, ic.CancelImport(context.Background(), "3"), ErrImportNotFound)
+
+		_, err := ic.DescribeImport(context.Background(), "3")
+		require.ErrorIs(t, err, ErrImportNotFound)
+	})
+}
@@ -0,0 +1,335 @@
+package pinecone
+
+import (
+	"container/list"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+)
+
+// CacheBackend stores cached query and fetch responses. Implement it to
+// plug in an external cache such as Redis or memcached.
+type CacheBackend interface {
+	// Get returns the value stored under the key, and whether it was found.
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	// Set stores the value under the key for the given time to live.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+}
+
+// WithQueryCache enables caching of Query and FetchVectors responses on
+// IndexClient. Cached entries expire after ttl, and the entries of a
+// namespace are invalidated whenever the namespace is written with
+// UpsertVectors, UpdateVector or DeleteVectors through any client sharing
+// the backend. Writes made by other means are only picked up once entries
+// expire.
+//
+// Failures of the backend are never returned to the caller, they are
+// treated as cache misses. A failure to record an invalidation leaves the
+// entries of the namespace in place until they expire.
+func WithQueryCache(backend CacheBackend, ttl time.Duration) CallOptions {
+	return CallOptions{
+		applyFunc: func(o *options) {
+			o.cache = &queryCache{
+				backend: backend,
+				ttl:     ttl,
+			}
+		},
+	}
+}
+
+// queryCache caches query and fetch responses per index and namespace.
+// Every key holds the generation of its namespace, a random token stored in
+// the backend and replaced on invalidation, so stale entries become
+// unreachable for every client sharing the backend, across restarts, and
+// are evicted by the backend in time.
+type queryCache struct {
+	backend CacheBackend
+	ttl     time.Duration
+	// index identifies the index in the keys, set by NewIndexClient.
+	index string
+}
+
+// generationKey returns the key under which the generation of a namespace
+// is stored.
+func (c *queryCache) generationKey(namespace string) string {
+	return "pinecone:generation:" + hashParts([]byte(c.index), []byte(namespace))
+}
+
+// generation returns the generation of a namespace, creating it when the
+// backend holds none, and whether it is known.
+func (c *queryCache) generation(ctx context.Context, namespace string) (string, bool) {
+	value, ok, err := c.backend.Get(ctx, c.generationKey(namespace))
+	if err != nil {
+		return "", false
+	}
+	if ok {
+		return string(value), true
+	}
+
+	return c.newGeneration(ctx, namespace)
+}
+
+// newGeneration stores a new generation for a namespace. The generation
+// expires with the entries, which only costs cache misses.
+func (c *queryCache) newGeneration(ctx context.Context, namespace string) (string, bool) {
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return "", false
+	}
+
+	generation := hex.EncodeToString(token)
+	if err := c.backend.Set(ctx, c.generationKey(namespace), []byte(generation), c.ttl); err != nil {
+		return "", false
+	}
+
+	return generation, true
+}
+
+func (c *queryCache) invalidate(ctx context.Context, namespace string) {
+	c.newGeneration(ctx, namespace)
+}
+
+func (c *queryCache) key(ctx context.Context, kind, namespace string, params any) (string, bool) {
+	body, err := json.Marshal(params)
+	if err != nil {
+		return "", false
+	}
+
+	generation, ok := c.generation(ctx, namespace)
+	if !ok {
+		return "", false
+	}
+
+	return "pinecone:" + kind + ":" + hashParts([]byte(kind), []byte(c.index), []byte(namespace), []byte(generation), body), true
+}
+
+// hashParts returns the hex SHA-256 hash of the parts separated by zero
+// bytes.
+func hashParts(parts ...[]byte) string {
+	hash := sha256.New()
+	for i, part := range parts {
+		if i > 0 {
+			hash.Write([]byte{0})
+		}
+		hash.Write(part)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func (c *queryCache) get(ctx context.Context, key string, v any) bool {
+	value, ok, err := c.backend.Get(ctx, key)
+	if err != nil || !ok {
+		return false
+	}
+
+	return json.Unmarshal(value, v) == nil
+}
+
+func (c *queryCache) set(ctx context.Context, key string, v any) {
+	value, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+
+	_ = c.backend.Set(ctx, key, value, c.ttl)
+}
+
+// queryCacheKey returns the cache key of the normalized query parameters.
+func (c *queryCache) queryCacheKey(ctx context.Context, params QueryParams) (string, bool) {
+	if len(params.Filter) == 0 {
+		params.Filter = nil
+	}
+	if len(params.Vector) == 0 {
+		params.Vector = nil
+	}
+	if params.SparseVector != nil {
+		params.SparseVector = sortedSparseVector(params.SparseVector)
+	}
+
+	return c.key(ctx, "query", params.Namespace, params)
+}
+
+// fetchCacheKey returns the cache key of the normalized fetch parameters.
+func (c *queryCache) fetchCacheKey(ctx context.Context, params FetchVectorsParams) (string, bool) {
+	ids := make([]string, 0, len(params.IDs))
+	seen := make(map[string]struct{}, len(params.IDs))
+	for _, id := range params.IDs {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+
+		seen[id] = struct{}{}
+		ids = append(ids, id)
+	}
+
+	sort.Strings(ids)
+
+	return c.key(ctx, "fetch", params.Namespace, ids)
+}
+
+// sortedSparseVector returns a copy of the sparse vector ordered by index.
+func sortedSparseVector(sparse *SparseVector) *SparseVector {
+	order := make([]int, len(sparse.Indices))
+	for i := range order {
+		order[i] = i
+	}
+
+	sort.SliceStable(order, func(i, j int) bool {
+		return sparse.Indices[order[i]] < sparse.Indices[order[j]]
+	})
+
+	sorted := &SparseVector{
+		Indices: make([]int32, len(order)),
+		Values:  make([]float32, len(order)),
+	}
+	for i, j := range order {
+		sorted.Indices[i] = sparse.Indices[j]
+		if j < len(sparse.Values) {
+			sorted.Values[i] = sparse.Values[j]
+		}
+	}
+
+	return sorted
+}
+
+// LRUCache is an in-memory CacheBackend that evicts the least recently used
+// entries once it holds more than a maximum number of entries or bytes.
+type LRUCache struct {
+	maxEntries int
+	maxBytes   int64
+
+	mu      sync.Mutex
+	bytes   int64
+	entries map[string]*list.Element
+	order   *list.List
+	now     func() time.Time
+}
+
+type lruEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+}
+
+var _ CacheBackend = (*LRUCache)(nil)
+
+// NewLRUCache creates an in-memory cache bounded by maxEntries entries and
+// maxBytes bytes of keys and values. Zero or less means unbounded.
+func NewLRUCache(maxEntries int, maxBytes int64) *LRUCache {
+	return &LRUCache{
+		maxEntries: maxEntries,
+		maxBytes:   maxBytes,
+		entries:    make(map[string]*list.Element),
+		order:      list.New(),
+		now:        time.Now,
+	}
+}
+
+// Get returns the value stored under the key, and whether it was found.
+func (c *LRUCache) Get(_ context.Context, key string) ([]byte, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+
+	entry := element.Value.(*lruEntry)
+	if !entry.expiresAt.IsZero() && !c.now().Before(entry.expiresAt) {
+		c.remove(element)
+		return nil, false, nil
+	}
+
+	c.order.MoveToFront(element)
+
+	return entry.value, true, nil
+}
+
+// Set stores the value under the key for the given time to live. A ttl of
+// zero or less never expires.
+func (c *LRUCache) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[key]; ok {
+		c.remove(element)
+	}
+
+	entry := &lruEntry{key: key, value: value}
+	if ttl > 0 {
+		entry.expiresAt = c.now().Add(ttl)
+	}
+
+	size := entrySize(entry)
+	if c.maxBytes > 0 && size > c.maxBytes {
+		return nil
+	}
+
+	c.entries[key] = c.order.PushFront(entry)
+	c.bytes += size
+
+	for (c.maxEntries > 0 && c.order.Len() > c.maxEntries) || (c.maxBytes > 0 && c.bytes > c.maxBytes) {
+		c.remove(c.order.Back())
+	}
+
+	return nil
+}
+
+// Len returns the number of entries in the cache, including expired entries
+// that have not been evicted yet.
+func (c *LRUCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len()
+}
+
+func (c *LRUCache) remove(element *list.Element) {
+	entry := element.Value.(*lruEntry)
+	c.order.Remove(element)
+	delete(c.entries, entry.key)
+	c.bytes -= entrySize(entry)
+}
+
+func entrySize(entry *lruEntry) int64 {
+	return int64(len(entry.key) + len(entry.value))
+}
+
+// queryCacheKey returns the cache key of a query, and whether the query is
+// cacheable at all.
+func (ic *IndexClient) queryCacheKey(ctx context.Context, params QueryParams) (string, bool) {
+	if ic.options == nil || ic.options.cache == nil {
+		return "", false
+	}
+
+	return ic.options.cache.queryCacheKey(ctx, params)
+}
+
+// fetchCacheKey returns the cache key of a fetch, and whether the fetch is
+// cacheable at all.
+func (ic *IndexClient) fetchCacheKey(ctx context.Context, params FetchVectorsParams) (string, bool) {
+	if ic.options == nil || ic.options.cache == nil {
+		return "", false
+	}
+
+	return ic.options.cache.fetchCacheKey(ctx, params)
+}
+
+// invalidateCache invalidates the cached entries of a namespace. It is
+// called after every write, whether it succeeded or not, since a failed
+// write may still have been partially applied, and even when the context of
+// the write is canceled.
+func (ic *IndexClient) invalidateCache(ctx context.Context, namespace string) {
+	if ic.options == nil || ic.options.cache == nil {
+		return
+	}
+
+	ic.options.cache.invalidate(context.WithoutCancel(ctx), namespace)
+}
@@ -0,0 +1,218 @@
+package pinecone
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLRUCache(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("MaxEntries", func(t *testing.T) {
+		assert := assert.New(t)
+
+		cache := NewLRUCache(2, 0)
+		_ = cache.Set(ctx, "a", []byte("1"), 0)
+		_ = cache.Set(ctx, "b", []byte("2"), 0)
+		_, _, _ = cache.Get(ctx, "a")
+		_ = cache.Set(ctx, "c", []byte("3"), 0)
+
+		_, ok, _ := cache.Get(ctx, "b")
+		assert.False(ok, "least recently used entry must be evicted")
+		value, ok, _ := cache.Get(ctx, "a")
+		assert.True(ok)
+		assert.Equal([]byte("1"), value)
+		assert.Equal(2, cache.Len())
+	})
+
+	t.Run("MaxBytes", func(t *testing.T) {
+		assert := assert.New(t)
+
+		cache := NewLRUCache(0, 10)
+		_ = cache.Set(ctx, "a", []byte("1234"), 0)
+		_ = cache.Set(ctx, "b", []byte("1234"), 0)
+		assert.Equal(2, cache.Len())
+
+		_ = cache.Set(ctx, "c", []byte("1234"), 0)
+		assert.Equal(2, cache.Len())
+		_, ok, _ := cache.Get(ctx, "a")
+		assert.False(ok)
+
+		_ = cache.Set(ctx, "too-large", []byte("123456789"), 0)
+		_, ok, _ = cache.Get(ctx, "too-large")
+		assert.False(ok)
+	})
+
+	t.Run("TTL", func(t *testing.T) {
+		now := time.Now()
+		cache := NewLRUCache(0, 0)
+		cache.now = func() time.Time { return now }
+
+		_ = cache.Set(ctx, "a", []byte("1"), time.Minute)
+		_, ok, _ := cache.Get(ctx, "a")
+		assert.True(t, ok)
+
+		now = now.Add(time.Minute)
+		_, ok, _ = cache.Get(ctx, "a")
+		assert.False(t, ok)
+		assert.Zero(t, cache.Len())
+	})
+}
+
+func TestQueryCache(t *testing.T) {
+	var queries, fetches int32
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/query", func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&queries, 1)
+		_ = json.NewEncoder(w).Encode(QueryResponse{Matches: []*QueryVector{{Vector: Vector{ID: "a"}, Score: float32(n)}}})
+	})
+	mux.HandleFunc("/vectors/fetch", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&fetches, 1)
+		_ = json.NewEncoder(w).Encode(FetchVectorsResponse{Vectors: map[string]*Vector{"a": {ID: "a"}}})
+	})
+	mux.HandleFunc("/vectors/upsert", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(UpsertVectorsResponse{UpsertedCount: 1})
+	})
+	mux.HandleFunc("/vectors/delete", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("{}"))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	ctx := context.Background()
+	ic := newTestIndexClient(server.URL, WithQueryCache(NewLRUCache(100, 0), time.Minute))
+
+	t.Run("Query", func(t *testing.T) {
+		assert := assert.New(t)
+		require := require.New(t)
+
+		params := QueryParams{TopK: 1, Vector: []float32{1, 2}, Namespace: "a", Filter: map[string]any{}}
+		first, err := ic.Query(ctx, params)
+		require.NoError(err)
+
+		params.Filter = nil
+		second, err := ic.Query(ctx, params)
+		require.NoError(err)
+		assert.Equal(int32(1), atomic.LoadInt32(&queries))
+		assert.Equal(first, second)
+
+		_, err = ic.Query(ctx, QueryParams{TopK: 2, Vector: []float32{1, 2}, Namespace: "a"})
+		require.NoError(err)
+		assert.Equal(int32(2), atomic.LoadInt32(&queries))
+	})
+
+	t.Run("SparseVectorOrder", func(t *testing.T) {
+		atomic.StoreInt32(&queries, 0)
+
+		_, err := ic.Query(ctx, QueryParams{TopK: 1, ID: "x", SparseVector: &SparseVector{Indices: []int32{1, 2}, Values: []float32{3, 4}}})
+		require.NoError(t, err)
+		_, err = ic.Query(ctx, QueryParams{TopK: 1, ID: "x", SparseVector: &SparseVector{Indices: []int32{2, 1}, Values: []float32{4, 3}}})
+		require.NoError(t, err)
+		assert.Equal(t, int32(1), atomic.LoadInt32(&queries))
+	})
+
+	t.Run("FetchVectors", func(t *testing.T) {
+		_, err := ic.FetchVectors(ctx, FetchVectorsParams{IDs: []string{"a", "b"}, Namespace: "a"})
+		require.NoError(t, err)
+		_, err = ic.FetchVectors(ctx, FetchVectorsParams{IDs: []string{"b", "a", "a"}, Namespace: "a"})
+		require.NoError(t, err)
+		assert.Equal(t, int32(1), atomic.LoadInt32(&fetches))
+	})
+
+	t.Run("Invalidation", func(t *testing.T) {
+		assert := assert.New(t)
+		require := require.New(t)
+
+		atomic.StoreInt32(&queries, 0)
+		atomic.StoreInt32(&fetches, 0)
+
+		queryA := QueryParams{TopK: 1, Vector: []float32{9}, Namespace: "a"}
+		queryB := QueryParams{TopK: 1, Vector: []float32{9}, Namespace: "b"}
+		_, err := ic.Query(ctx, queryA)
+		require.NoError(err)
+		_, err = ic.Query(ctx, queryB)
+		require.NoError(err)
+
+		_, err = ic.UpsertVectors(ctx, UpsertVectorsParams{Vectors: []*Vector{{ID: "a", Values: []float32{9}}}, Namespace: "a"})
+		require.NoError(err)
+
+		_, err = ic.Query(ctx, queryA)
+		require.NoError(err)
+		_, err = ic.Query(ctx, queryB)
+		require.NoError(err)
+		assert.Equal(int32(3), atomic.LoadInt32(&queries), "only namespace a must be invalidated")
+
+		_, err = ic.FetchVectors(ctx, FetchVectorsParams{IDs: []string{"a"}, Namespace: "b"})
+		require.NoError(err)
+		require.NoError(ic.DeleteVectors(ctx, DeleteVectorsParams{DeleteAll: true, Namespace: "b"}))
+		_, err = ic.FetchVectors(ctx, FetchVectorsParams{IDs: []string{"a"}, Namespace: "b"})
+		require.NoError(err)
+		assert.Equal(int32(2), atomic.LoadInt32(&fetches))
+	})
+}
+
+func TestQueryCache_SharedBackend(t *testing.T) {
+	newServer := func(queries *int32) *httptest.Server {
+		mux := http.NewServeMux()
+		mux.HandleFunc("/query", func(w http.ResponseWriter, r *http.Request) {
+			n := atomic.AddInt32(queries, 1)
+			_ = json.NewEncoder(w).Encode(QueryResponse{Matches: []*QueryVector{{Vector: Vector{ID: "a"}, Score: float32(n)}}})
+		})
+		mux.HandleFunc("/vectors/upsert", func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewEncoder(w).Encode(UpsertVectorsResponse{UpsertedCount: 1})
+		})
+
+		return httptest.NewServer(mux)
+	}
+
+	var queriesA, queriesB int32
+	serverA := newServer(&queriesA)
+	defer serverA.Close()
+	serverB := newServer(&queriesB)
+	defer serverB.Close()
+
+	assert := assert.New(t)
+	require := require.New(t)
+
+	ctx := context.Background()
+	backend := NewLRUCache(100, 0)
+	params := QueryParams{TopK: 1, Vector: []float32{1, 2}, Namespace: "ns"}
+
+	// clients of different indexes never read each other's entries
+	indexA := newTestIndexClient(serverA.URL, WithQueryCache(backend, time.Minute))
+	indexB := newTestIndexClient(serverB.URL, WithQueryCache(backend, time.Minute))
+	_, err := indexA.Query(ctx, params)
+	require.NoError(err)
+	_, err = indexB.Query(ctx, params)
+	require.NoError(err)
+	assert.Equal(int32(1), atomic.LoadInt32(&queriesA))
+	assert.Equal(int32(1), atomic.LoadInt32(&queriesB))
+
+	// a second client of the same index, e.g. in another process or after a
+	// restart, shares the entries and invalidates them for the first one
+	otherA := newTestIndexClient(serverA.URL, WithQueryCache(backend, time.Minute))
+	_, err = otherA.Query(ctx, params)
+	require.NoError(err)
+	assert.Equal(int32(1), atomic.LoadInt32(&queriesA))
+
+	_, err = otherA.UpsertVectors(ctx, UpsertVectorsParams{Vectors: []*Vector{{ID: "a", Values: []float32{1, 2}}}, Namespace: "ns"})
+	require.NoError(err)
+
+	resp, err := indexA.Query(ctx, params)
+	require.NoError(err)
+	assert.Equal(int32(2), atomic.LoadInt32(&queriesA))
+	assert.Equal(float32(2), resp.Matches[0].Score)
+
+	_, err = indexB.Query(ctx, params)
+	require.NoError(err)
+	assert.Equal(int32(1), atomic.LoadInt32(&queriesB))
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/nekomeowww/go-pinecone"
+)
+
+// config is the connection and output configuration shared by every
+// command.
+type config struct {
+	apiKey         string
+	environment    string
+	projectName    string
+	indexName      string
+	indexHost      string
+	controllerHost string
+	output         string
+}
+
+// newFlagSet returns the flag set of a command, with the flags of the
+// configuration defaulting to the environment.
+func newFlagSet(e *env, name string) (*flag.FlagSet, *config) {
+	fs := flag.NewFlagSet("pinecone "+name, flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+
+	c := new(config)
+	fs.StringVar(&c.apiKey, "api-key", e.getenv("PINECONE_API_KEY"), "the API key, defaults to $PINECONE_API_KEY")
+	fs.StringVar(&c.environment, "environment", e.getenv("PINECONE_ENVIRONMENT"), "the environment, defaults to $PINECONE_ENVIRONMENT")
+	fs.StringVar(&c.projectName, "project", e.getenv("PINECONE_PROJECT_NAME"), "the project name, defaults to $PINECONE_PROJECT_NAME")
+	fs.StringVar(&c.indexName, "index", e.getenv("PINECONE_INDEX_NAME"), "the index name, defaults to $PINECONE_INDEX_NAME")
+	fs.StringVar(&c.indexHost, "host", e.getenv("PINECONE_INDEX_HOST"), "the URL of the index, overriding the one built from the index, project and environment, defaults to $PINECONE_INDEX_HOST")
+	fs.StringVar(&c.controllerHost, "controller-host", e.getenv("PINECONE_CONTROLLER_HOST"), "the URL of the controller, defaults to $PINECONE_CONTROLLER_HOST")
+
+	output := e.getenv("PINECONE_OUTPUT")
+	if output == "" {
+		output = outputTable
+	}
+	fs.StringVar(&c.output, "output", output, "the output format, table or json, defaults to $PINECONE_OUTPUT")
+
+	return fs, c
+}
+
+// parse parses the flags of a command, which may be placed before, after or
+// between the positional arguments, and returns the positional arguments.
+func parse(fs *flag.FlagSet, c *config, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			if err == flag.ErrHelp {
+				return nil, err
+			}
+			return nil, fmt.Errorf("%w: %v", errUsage, err)
+		}
+
+		args = fs.Args()
+		if len(args) == 0 {
+			break
+		}
+
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+
+	if c.output != outputTable && c.output != outputJSON {
+		return nil, fmt.Errorf("%w: unknown output %q, expected table or json", errUsage, c.output)
+	}
+
+	return positional, nil
+}
+
+func (c *config) options() []pinecone.CallOptions {
+	opts := []pinecone.CallOptions{
+		pinecone.WithAPIKey(c.apiKey),
+		pinecone.WithEnvironment(c.environment),
+		pinecone.WithProjectName(c.projectName),
+		pinecone.WithIndexName(c.indexName),
+	}
+	if c.indexHost != "" {
+		opts = append(opts, pinecone.WithBaseURL(c.indexHost))
+	}
+	if c.controllerHost != "" {
+		opts = append(opts, pinecone.WithControllerBaseURL(c.controllerHost))
+	}
+
+	return opts
+}
+
+func (c *config) client() (*pinecone.Client, error) {
+	if c.apiKey == "" {
+		return nil, fmt.Errorf("%w: --api-key or $PINECONE_API_KEY is required", errUsage)
+	}
+	if c.environment == "" && c.controllerHost == "" {
+		return nil, fmt.Errorf("%w: --environment or $PINECONE_ENVIRONMENT is required", errUsage)
+	}
+
+	// the index host is not the controller, so it is left out
+	controller := *c
+	controller.indexHost = ""
+
+	return pinecone.New(controller.options()...)
+}
+
+func (c *config) indexClient() (*pinecone.IndexClient, error) {
+	if c.apiKey == "" {
+		return nil, fmt.Errorf("%w: --api-key or $PINECONE_API_KEY is required", errUsage)
+	}
+	if c.indexHost == "" && (c.indexName == "" || c.projectName == "" || c.environment == "") {
+		return nil, fmt.Errorf("%w: --host, or --index, --project and --environment are required", errUsage)
+	}
+
+	return pinecone.NewIndexClient(c.options()...)
+}
+
+func (c *config) printer(w io.Writer) *printer {
+	return &printer{w: w, json: c.output == outputJSON}
+}
+
+// parseValues parses a vector, either as a JSON array or as comma-separated
+// numbers.
+func parseValues(s string) ([]float32, error) {
+	s = strings.TrimSpace(s)
+	s = strings.TrimPrefix(s, "[")
+	s = strings.TrimSuffix(s, "]")
+
+	fields := strings.Split(s, ",")
+	values := make([]float32, 0, len(fields))
+	for _, field := range fields {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+
+		v, err := strconv.ParseFloat(field, 32)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid vector value %q", errUsage, field)
+		}
+		values = append(values, float32(v))
+	}
+	if len(values) == 0 {
+		return nil, fmt.Errorf("%w: vector is empty", errUsage)
+	}
+
+	return values, nil
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strconv"
+
+	"github.com/nekomeowww/go-pinecone"
+	"github.com/samber/mo"
+)
+
+func indexList(ctx context.Context, e *env, args []string) error {
+	fs, c := newFlagSet(e, "index list")
+	if _, err := parse(fs, c, args); err != nil {
+		return err
+	}
+
+	client, err := c.client()
+	if err != nil {
+		return err
+	}
+
+	indexes, err := client.ListIndexes()
+	if err != nil {
+		return err
+	}
+
+	rows := make([][]string, 0, len(indexes))
+	for _, name := range indexes {
+		rows = append(rows, []string{name})
+	}
+
+	return c.printer(e.stdout).print(indexes, []string{"NAME"}, rows)
+}
+
+func indexDescribe(ctx context.Context, e *env, args []string) error {
+	fs, c := newFlagSet(e, "index describe")
+	name, err := parseIndexName(fs, c, args)
+	if err != nil {
+		return err
+	}
+
+	client, err := c.client()
+	if err != nil {
+		return err
+	}
+
+	description, err := client.DescribeIndex(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	return c.printer(e.stdout).print(description,
+		[]string{"NAME", "METRIC", "DIMENSION", "REPLICAS", "STATE", "READY", "HOST"},
+		[][]string{{
+			description.Database.Name,
+			description.Database.Metric,
+			strconv.Itoa(description.Database.Dimension),
+			strconv.Itoa(description.Database.Replicas),
+			description.Status.State,
+			strconv.FormatBool(description.Status.Ready),
+			description.Status.Host,
+		}},
+	)
+}
+
+func indexCreate(ctx context.Context, e *env, args []string) error {
+	fs, c := newFlagSet(e, "index create")
+	dimension := fs.Int("dimension", 0, "the dimension of the vectors, required")
+	metric := fs.String("metric", "", "the distance metric, euclidean, cosine or dotproduct")
+	pods := fs.Int("pods", 0, "the number of pods, including replicas")
+	replicas := fs.Int("replicas", 0, "the number of replicas")
+	podType := fs.String("pod-type", "", "the pod type, s1, p1 or p2")
+	podSize := fs.String("pod-size", "", "the pod size, 1, 2, 4 or 8")
+	sourceCollection := fs.String("source-collection", "", "the collection to create the index from")
+	name, err := parseIndexName(fs, c, args)
+	if err != nil {
+		return err
+	}
+	if *dimension <= 0 {
+		return fmt.Errorf("%w: --dimension is required", errUsage)
+	}
+
+	client, err := c.client()
+	if err != nil {
+		return err
+	}
+
+	params := pinecone.CreateIndexParams{Name: name, Dimension: *dimension}
+	if *metric != "" {
+		params.Metric = mo.Some(pinecone.CreateIndexMetric(*metric))
+	}
+	if *pods > 0 {
+		params.Pods = mo.Some(*pods)
+	}
+	if *replicas > 0 {
+		params.Replicas = mo.Some(*replicas)
+	}
+	if *podType != "" {
+		params.PodType = mo.Some(pinecone.CreateIndexPodType(*podType))
+	}
+	if *podSize != "" {
+		params.PodSize = mo.Some(pinecone.CreateIndexPodSize(*podSize))
+	}
+	if *sourceCollection != "" {
+		params.SourceCollection = mo.Some(*sourceCollection)
+	}
+
+	if err := client.CreateIndex(ctx, params); err != nil {
+		return err
+	}
+
+	return c.printer(e.stdout).message(map[string]string{"created": name}, "created index %s", name)
+}
+
+func indexConfigure(ctx context.Context, e *env, args []string) error {
+	fs, c := newFlagSet(e, "index configure")
+	replicas := fs.Int("replicas", 0, "the number of replicas")
+	podType := fs.String("pod-type", "", "the pod type, s1, p1 or p2")
+	podSize := fs.String("pod-size", "", "the pod size, 1, 2, 4 or 8")
+	name, err := parseIndexName(fs, c, args)
+	if err != nil {
+		return err
+	}
+
+	client, err := c.client()
+	if err != nil {
+		return err
+	}
+
+	params := pinecone.ConfigureIndexParams{IndexName: name}
+	if *replicas > 0 {
+		params.Replicas = mo.Some(*replicas)
+	}
+	if *podType != "" {
+		params.PodType = mo.Some(pinecone.CreateIndexPodType(*podType))
+	}
+	if *podSize != "" {
+		params.PodSize = mo.Some(pinecone.CreateIndexPodSize(*podSize))
+	}
+
+	if err := client.ConfigureIndex(ctx, params); err != nil {
+		return err
+	}
+
+	return c.printer(e.stdout).message(map[string]string{"configured": name}, "configured index %s", name)
+}
+
+func indexDelete(ctx context.Context, e *env, args []string) error {
+	fs, c := newFlagSet(e, "index delete")
+	name, err := parseExplicitIndexName(fs, c, args)
+	if err != nil {
+		return err
+	}
+
+	client, err := c.client()
+	if err != nil {
+		return err
+	}
+
+	if err := client.DeleteIndex(ctx, name); err != nil {
+		return err
+	}
+
+	return c.printer(e.stdout).message(map[string]string{"deleted": name}, "deleted index %s", name)
+}
+
+// parseIndexName parses the flags of an index command, and returns its
+// only positional argument, or the configured index name.
+func parseIndexName(fs *flag.FlagSet, c *config, args []string) (string, error) {
+	positional, err := parse(fs, c, args)
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case len(positional) == 1:
+		return positional[0], nil
+	case len(positional) == 0 && c.indexName != "":
+		return c.indexName, nil
+	case len(positional) == 0:
+		return "", fmt.Errorf("%w: an index name is required", errUsage)
+	default:
+		return "", fmt.Errorf("%w: expected a single index name, got %d arguments", errUsage, len(positional))
+	}
+}
+
+// parseExplicitIndexName parses the flags of a destructive index command, and
+// returns its only positional argument, never falling back to the configured
+// index name.
+func parseExplicitIndexName(fs *flag.FlagSet, c *config, args []string) (string, error) {
+	positional, err := parse(fs, c, args)
+	if err != nil {
+		return "", err
+	}
+
+	if len(positional) != 1 {
+		return "", fmt.Errorf("%w: expected the name of the index as the only argument, got %d arguments", errUsage, len(positional))
+	}
+
+	return positional[0], nil
+}
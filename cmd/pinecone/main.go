@@ -0,0 +1,133 @@
+// Command pinecone inspects and edits Pinecone indexes from the command line.
+//
+// Usage:
+//
+//	pinecone index list|describe|create|configure|delete
+//	pinecone stats
+//	pinecone vectors fetch|upsert|delete|query
+//	pinecone namespaces list
+//
+// The connection is configured with flags, or with the environment
+// variables PINECONE_API_KEY, PINECONE_ENVIRONMENT, PINECONE_PROJECT_NAME,
+// PINECONE_INDEX_NAME, PINECONE_INDEX_HOST, PINECONE_CONTROLLER_HOST and
+// PINECONE_OUTPUT. Flags take precedence over the environment.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+)
+
+const usage = `Usage: pinecone <command> [subcommand] [flags] [args]
+
+Commands:
+  index list                      List the indexes
+  index describe <name>           Describe an index
+  index create <name>             Create an index
+  index configure <name>          Change the replicas or pod type of an index
+  index delete <name>             Delete an index
+  stats                           Show the stats of an index
+  vectors fetch <id>...           Fetch vectors by ID
+  vectors upsert --file <path>    Upsert vectors from a JSON or NDJSON file
+  vectors delete <id>...          Delete vectors by ID, or all of them
+  vectors query                   Query vectors by vector or ID
+  namespaces list                 List the namespaces of an index
+
+Run "pinecone <command> [subcommand] -h" for the flags of a command.
+`
+
+// errUsage is returned for invalid command lines.
+var errUsage = errors.New("invalid usage")
+
+// env is the environment of a command.
+type env struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+	getenv func(key string) string
+}
+
+type command func(ctx context.Context, e *env, args []string) error
+
+var commands = map[string]map[string]command{
+	"index": {
+		"list":      indexList,
+		"describe":  indexDescribe,
+		"create":    indexCreate,
+		"configure": indexConfigure,
+		"delete":    indexDelete,
+	},
+	"stats": {
+		"": stats,
+	},
+	"vectors": {
+		"fetch":  vectorsFetch,
+		"upsert": vectorsUpsert,
+		"delete": vectorsDelete,
+		"query":  vectorsQuery,
+	},
+	"namespaces": {
+		"list": namespacesList,
+	},
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	e := &env{stdin: os.Stdin, stdout: os.Stdout, stderr: os.Stderr, getenv: os.Getenv}
+
+	err := run(ctx, e, os.Args[1:])
+	switch {
+	case err == nil, errors.Is(err, flag.ErrHelp):
+	case errors.Is(err, errUsage):
+		fmt.Fprintf(os.Stderr, "pinecone: %v\n\n%s", err, usage)
+		os.Exit(2)
+	default:
+		fmt.Fprintf(os.Stderr, "pinecone: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// run runs the command of the command line.
+func run(ctx context.Context, e *env, args []string) error {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "-help" || args[0] == "--help" || args[0] == "help" {
+		fmt.Fprint(e.stdout, usage)
+		return nil
+	}
+
+	subcommands, ok := commands[args[0]]
+	if !ok {
+		return fmt.Errorf("%w: unknown command %q", errUsage, args[0])
+	}
+	if cmd, ok := subcommands[""]; ok {
+		return cmd(ctx, e, args[1:])
+	}
+	if len(args) < 2 {
+		return fmt.Errorf("%w: %s requires a subcommand: %s", errUsage, args[0], strings.Join(names(subcommands), ", "))
+	}
+
+	cmd, ok := subcommands[args[1]]
+	if !ok {
+		return fmt.Errorf("%w: unknown subcommand %q of %s", errUsage, args[1], args[0])
+	}
+
+	return cmd(ctx, e, args[2:])
+}
+
+func names(subcommands map[string]command) []string {
+	names := make([]string, 0, len(subcommands))
+	for name := range subcommands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
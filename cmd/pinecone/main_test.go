@@ -0,0 +1,330 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/nekomeowww/go-pinecone"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stub is a local server standing in for both the controller and the index.
+type stub struct {
+	mu     sync.Mutex
+	bodies map[string][]map[string]any
+}
+
+func newStub(t *testing.T) (*stub, *httptest.Server) {
+	s := &stub{bodies: make(map[string][]map[string]any)}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/databases", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			_, _ = w.Write([]byte(`["movies","products"]`))
+		case http.MethodPost:
+			w.WriteHeader(http.StatusCreated)
+		}
+	})
+	mux.HandleFunc("/databases/movies", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			_, _ = w.Write([]byte(`{"database":{"name":"movies","metric":"cosine","dimension":3,"replicas":1},"status":{"state":"Ready","ready":true,"host":"movies.svc"}}`))
+		case http.MethodPatch:
+			w.WriteHeader(http.StatusAccepted)
+		case http.MethodDelete:
+			s.record("delete", nil)
+			w.WriteHeader(http.StatusAccepted)
+		}
+	})
+	mux.HandleFunc("/databases/missing", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	mux.HandleFunc("/describe_index_stats", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"namespaces":{"":{"vectorCount":1},"b":{"vectorCount":20},"a":{"vectorCount":3}},"dimensions":3,"indexFullness":0.5,"totalVectorCount":24}`))
+	})
+	mux.HandleFunc("/vectors/fetch", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, []string{"1", "2", "3"}, r.URL.Query()["ids"])
+		assert.Equal(t, "a", r.URL.Query().Get("namespace"))
+		_, _ = w.Write([]byte(`{"vectors":{"1":{"id":"1","values":[1,2,3,4,5,6],"metadata":{"genre":"drama"}},"2":{"id":"2","values":[0.5]}},"namespace":"a"}`))
+	})
+	mux.HandleFunc("/vectors/upsert", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		s.record("upsert", body)
+
+		_ = json.NewEncoder(w).Encode(pinecone.UpsertVectorsResponse{UpsertedCount: len(body["vectors"].([]any))})
+	})
+	mux.HandleFunc("/vectors/delete", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		s.record("delete", body)
+
+		_, _ = w.Write([]byte(`{}`))
+	})
+	mux.HandleFunc("/query", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		s.record("query", body)
+
+		_, _ = w.Write([]byte(`{"matches":[{"id":"1","score":0.91,"values":[1,2],"metadata":{"genre":"drama"}},{"id":"2","score":0.5}],"namespace":"a"}`))
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return s, server
+}
+
+func (s *stub) record(path string, body map[string]any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.bodies[path] = append(s.bodies[path], body)
+}
+
+func (s *stub) last(path string) map[string]any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	bodies := s.bodies[path]
+	if len(bodies) == 0 {
+		return nil
+	}
+
+	return bodies[len(bodies)-1]
+}
+
+// runCLI runs a command line against the server, configured through the
+// environment, and returns its output.
+func runCLI(t *testing.T, server *httptest.Server, stdin string, args ...string) (string, error) {
+	environment := map[string]string{
+		"PINECONE_API_KEY":         "key",
+		"PINECONE_INDEX_HOST":      server.URL,
+		"PINECONE_CONTROLLER_HOST": server.URL,
+	}
+
+	stdout := new(bytes.Buffer)
+	e := &env{
+		stdin:  strings.NewReader(stdin),
+		stdout: stdout,
+		stderr: new(bytes.Buffer),
+		getenv: func(key string) string { return environment[key] },
+	}
+
+	err := run(context.Background(), e, args)
+
+	return stdout.String(), err
+}
+
+func TestIndexCommands(t *testing.T) {
+	s, server := newStub(t)
+
+	t.Run("List", func(t *testing.T) {
+		out, err := runCLI(t, server, "", "index", "list")
+		require.NoError(t, err)
+		assert.Equal(t, "NAME\nmovies\nproducts\n", out)
+
+		out, err = runCLI(t, server, "", "index", "list", "--output", "json")
+		require.NoError(t, err)
+		assert.JSONEq(t, `["movies","products"]`, out)
+	})
+
+	t.Run("Describe", func(t *testing.T) {
+		out, err := runCLI(t, server, "", "index", "describe", "movies")
+		require.NoError(t, err)
+		assert.Equal(t, strings.Join([]string{
+			"NAME    METRIC  DIMENSION  REPLICAS  STATE  READY  HOST",
+			"movies  cosine  3          1         Ready  true   movies.svc",
+			"",
+		}, "\n"), out)
+
+		_, err = runCLI(t, server, "", "index", "describe", "missing")
+		require.ErrorIs(t, err, pinecone.ErrIndexNotFound)
+
+		_, err = runCLI(t, server, "", "index", "describe")
+		require.ErrorIs(t, err, errUsage)
+	})
+
+	t.Run("Create", func(t *testing.T) {
+		out, err := runCLI(t, server, "", "index", "create", "movies", "--dimension", "3", "--metric", "cosine", "--pod-type", "p1", "--pod-size", "1")
+		require.NoError(t, err)
+		assert.Equal(t, "created index movies\n", out)
+
+		_, err = runCLI(t, server, "", "index", "create", "movies")
+		require.ErrorIs(t, err, errUsage)
+	})
+
+	t.Run("ConfigureAndDelete", func(t *testing.T) {
+		out, err := runCLI(t, server, "", "index", "configure", "--replicas", "2", "movies")
+		require.NoError(t, err)
+		assert.Equal(t, "configured index movies\n", out)
+
+		// the configured index is never deleted implicitly
+		_, err = runCLI(t, server, "", "index", "delete", "--index", "movies")
+		require.ErrorIs(t, err, errUsage)
+		assert.Empty(t, s.bodies["delete"])
+
+		out, err = runCLI(t, server, "", "index", "delete", "movies", "--output", "json")
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"deleted":"movies"}`, out)
+		assert.Len(t, s.bodies["delete"], 1)
+	})
+}
+
+func TestStatsAndNamespaces(t *testing.T) {
+	_, server := newStub(t)
+
+	out, err := runCLI(t, server, "", "stats")
+	require.NoError(t, err)
+	assert.Equal(t, "DIMENSION  VECTORS  NAMESPACES  FULLNESS\n3          24       3           0.5\n", out)
+
+	out, err = runCLI(t, server, "", "namespaces", "list")
+	require.NoError(t, err)
+	assert.Equal(t, "NAMESPACE  VECTORS\n\"\"         1\na          3\nb          20\n", out)
+
+	out, err = runCLI(t, server, "", "namespaces", "list", "--output", "json")
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"name":"","vectorCount":1},{"name":"a","vectorCount":3},{"name":"b","vectorCount":20}]`, out)
+}
+
+func TestVectorsCommands(t *testing.T) {
+	s, server := newStub(t)
+
+	t.Run("Fetch", func(t *testing.T) {
+		out, err := runCLI(t, server, "", "vectors", "fetch", "--namespace", "a", "1", "2", "3")
+		require.NoError(t, err)
+		assert.Equal(t, strings.Join([]string{
+			"ID  VALUES             METADATA",
+			`1   [1,2,3,4,... (6)]  {"genre":"drama"}`,
+			"2   [0.5]              ",
+			"",
+		}, "\n"), out)
+	})
+
+	t.Run("UpsertFile", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "vectors.ndjson")
+		require.NoError(t, os.WriteFile(path, []byte("{\"id\":\"1\",\"values\":[1,2,3]}\n{\"id\":\"2\",\"values\":[1,2,3]}\n\n{\"id\":\"3\",\"values\":[1,2,3]}\n"), 0o644))
+
+		out, err := runCLI(t, server, "", "vectors", "upsert", "--file", path, "--namespace", "a", "--batch-size", "2")
+		require.NoError(t, err)
+		assert.Equal(t, "upserted 3 vectors\n", out)
+		assert.Len(t, s.bodies["upsert"], 2)
+		assert.Equal(t, "a", s.last("upsert")["namespace"])
+	})
+
+	t.Run("UpsertStdin", func(t *testing.T) {
+		out, err := runCLI(t, server, ` [{"id":"1","values":[1,2,3]},{"id":"2","values":[4,5,6]}]`, "vectors", "upsert", "--file", "-", "--output", "json")
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"upsertedCount":2}`, out)
+
+		_, err = runCLI(t, server, `[{"id":"1","values":[1,2,3]},{"id":`, "vectors", "upsert", "--file", "-")
+		require.Error(t, err)
+
+		_, err = runCLI(t, server, "", "vectors", "upsert")
+		require.ErrorIs(t, err, errUsage)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		out, err := runCLI(t, server, "", "vectors", "delete", "1", "2", "--namespace", "a")
+		require.NoError(t, err)
+		assert.Equal(t, "deleted 2 vectors\n", out)
+		assert.Equal(t, []any{"1", "2"}, s.last("delete")["ids"])
+
+		_, err = runCLI(t, server, "", "vectors", "delete", "--namespace", "a", "--all")
+		require.NoError(t, err)
+		assert.Equal(t, true, s.last("delete")["deleteAll"])
+
+		_, err = runCLI(t, server, "", "vectors", "delete", "1", "--all")
+		require.ErrorIs(t, err, errUsage)
+	})
+
+	t.Run("Query", func(t *testing.T) {
+		out, err := runCLI(t, server, "", "vectors", "query", "--vector", "[0.1, 0.2, 0.3]", "--filter", `{"genre":{"$eq":"drama"}}`, "--top-k", "2", "--namespace", "a")
+		require.NoError(t, err)
+		assert.Equal(t, strings.Join([]string{
+			"ID  SCORE   METADATA",
+			`1   0.9100  {"genre":"drama"}`,
+			"2   0.5000  ",
+			"",
+		}, "\n"), out)
+
+		body := s.last("query")
+		assert.InDeltaSlice(t, []any{0.1, 0.2, 0.3}, body["vector"], 1e-6)
+		assert.Equal(t, map[string]any{"genre": map[string]any{"$eq": "drama"}}, body["filter"])
+		assert.Equal(t, float64(2), body["topK"])
+
+		out, err = runCLI(t, server, "", "vectors", "query", "--id", "1", "--include-values", "--output", "json")
+		require.NoError(t, err)
+		assert.Equal(t, "1", s.last("query")["id"])
+
+		var resp pinecone.QueryResponse
+		require.NoError(t, json.Unmarshal([]byte(out), &resp))
+		require.Len(t, resp.Matches, 2)
+		assert.Equal(t, []float32{1, 2}, resp.Matches[0].Values)
+
+		_, err = runCLI(t, server, "", "vectors", "query", "--id", "1", "--vector", "1,2,3")
+		require.ErrorIs(t, err, errUsage)
+
+		_, err = runCLI(t, server, "", "vectors", "query", "--vector", "1,x")
+		require.ErrorIs(t, err, errUsage)
+
+		_, err = runCLI(t, server, "", "vectors", "query", "--id", "1", "--filter", "{")
+		require.ErrorIs(t, err, errUsage)
+	})
+}
+
+func TestConfig(t *testing.T) {
+	_, server := newStub(t)
+
+	t.Run("Usage", func(t *testing.T) {
+		out, err := runCLI(t, server, "")
+		require.NoError(t, err)
+		assert.Contains(t, out, "Usage: pinecone")
+
+		_, err = runCLI(t, server, "", "collections")
+		require.ErrorIs(t, err, errUsage)
+
+		_, err = runCLI(t, server, "", "index")
+		require.ErrorIs(t, err, errUsage)
+
+		_, err = runCLI(t, server, "", "index", "list", "--output", "yaml")
+		require.ErrorIs(t, err, errUsage)
+	})
+
+	t.Run("FlagsOverrideEnvironment", func(t *testing.T) {
+		_, err := runCLI(t, server, "", "index", "list", "--api-key", "")
+		require.ErrorIs(t, err, errUsage)
+
+		_, err = runCLI(t, server, "", "stats", "--host", "")
+		require.ErrorIs(t, err, errUsage)
+	})
+
+	t.Run("OutputFromEnvironment", func(t *testing.T) {
+		stdout := new(bytes.Buffer)
+		e := &env{
+			stdin:  strings.NewReader(""),
+			stdout: stdout,
+			stderr: new(bytes.Buffer),
+			getenv: func(key string) string {
+				return map[string]string{
+					"PINECONE_API_KEY":         "key",
+					"PINECONE_CONTROLLER_HOST": server.URL,
+					"PINECONE_OUTPUT":          "json",
+				}[key]
+			},
+		}
+
+		require.NoError(t, run(context.Background(), e, []string{"index", "list"}))
+		assert.JSONEq(t, `["movies","products"]`, stdout.String())
+	})
+}
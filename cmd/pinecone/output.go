@@ -0,0 +1,82 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+)
+
+// printer prints the result of a command, either as an indented JSON
+// document or as a table.
+type printer struct {
+	w    io.Writer
+	json bool
+}
+
+// print prints v as JSON, or the rows under the header as a table.
+func (p *printer) print(v any, header []string, rows [][]string) error {
+	if p.json {
+		encoder := json.NewEncoder(p.w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(v)
+	}
+
+	tw := tabwriter.NewWriter(p.w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+
+	return tw.Flush()
+}
+
+// message prints a confirmation, or v as JSON.
+func (p *printer) message(v any, format string, args ...any) error {
+	if p.json {
+		return p.print(v, nil, nil)
+	}
+
+	_, err := fmt.Fprintf(p.w, format+"\n", args...)
+	return err
+}
+
+// compact formats v as single-line JSON for a table cell.
+func compact(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+
+	return string(data)
+}
+
+// metadata formats the metadata of a vector for a table cell.
+func metadata(m map[string]any) string {
+	if len(m) == 0 {
+		return ""
+	}
+
+	return compact(m)
+}
+
+// truncate shortens the values of a vector for a table cell.
+func truncate(values []float32, n int) string {
+	if len(values) <= n {
+		return compact(values)
+	}
+
+	parts := make([]string, 0, n+1)
+	for _, v := range values[:n] {
+		parts = append(parts, fmt.Sprint(v))
+	}
+	parts = append(parts, fmt.Sprintf("... (%d)", len(values)))
+
+	return "[" + strings.Join(parts, ",") + "]"
+}
@@ -0,0 +1,341 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/nekomeowww/go-pinecone"
+)
+
+func stats(ctx context.Context, e *env, args []string) error {
+	fs, c := newFlagSet(e, "stats")
+	filter := fs.String("filter", "", "a metadata filter as JSON, counting only the matching vectors")
+	if _, err := parse(fs, c, args); err != nil {
+		return err
+	}
+
+	params := pinecone.DescribeIndexStatsParams{}
+	if err := parseFilter(*filter, &params.Filter); err != nil {
+		return err
+	}
+
+	ic, err := c.indexClient()
+	if err != nil {
+		return err
+	}
+
+	resp, err := ic.DescribeIndexStats(ctx, params)
+	if err != nil {
+		return err
+	}
+
+	return c.printer(e.stdout).print(resp,
+		[]string{"DIMENSION", "VECTORS", "NAMESPACES", "FULLNESS"},
+		[][]string{{
+			strconv.FormatInt(resp.Dimensions, 10),
+			strconv.FormatInt(resp.TotalVectorCount, 10),
+			strconv.Itoa(len(resp.Namespaces)),
+			strconv.FormatFloat(float64(resp.IndexFullness), 'f', -1, 32),
+		}},
+	)
+}
+
+func namespacesList(ctx context.Context, e *env, args []string) error {
+	fs, c := newFlagSet(e, "namespaces list")
+	if _, err := parse(fs, c, args); err != nil {
+		return err
+	}
+
+	ic, err := c.indexClient()
+	if err != nil {
+		return err
+	}
+
+	resp, err := ic.DescribeIndexStats(ctx, pinecone.DescribeIndexStatsParams{})
+	if err != nil {
+		return err
+	}
+
+	type namespace struct {
+		Name        string `json:"name"`
+		VectorCount int64  `json:"vectorCount"`
+	}
+
+	namespaces := make([]namespace, 0, len(resp.Namespaces))
+	for name, count := range resp.Namespaces {
+		ns := namespace{Name: name}
+		if count != nil {
+			ns.VectorCount = count.VectorCount
+		}
+		namespaces = append(namespaces, ns)
+	}
+	sort.Slice(namespaces, func(i, j int) bool { return namespaces[i].Name < namespaces[j].Name })
+
+	rows := make([][]string, 0, len(namespaces))
+	for _, ns := range namespaces {
+		name := ns.Name
+		if name == "" {
+			name = `""`
+		}
+		rows = append(rows, []string{name, strconv.FormatInt(ns.VectorCount, 10)})
+	}
+
+	return c.printer(e.stdout).print(namespaces, []string{"NAMESPACE", "VECTORS"}, rows)
+}
+
+func vectorsFetch(ctx context.Context, e *env, args []string) error {
+	fs, c := newFlagSet(e, "vectors fetch")
+	namespace := fs.String("namespace", "", "the namespace")
+	ids, err := parse(fs, c, args)
+	if err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return fmt.Errorf("%w: at least one id is required", errUsage)
+	}
+
+	ic, err := c.indexClient()
+	if err != nil {
+		return err
+	}
+
+	resp, err := ic.FetchVectors(ctx, pinecone.FetchVectorsParams{IDs: ids, Namespace: *namespace})
+	if err != nil {
+		return err
+	}
+
+	rows := make([][]string, 0, len(resp.Vectors))
+	for _, id := range ids {
+		v, ok := resp.Vectors[id]
+		if !ok {
+			continue
+		}
+
+		rows = append(rows, []string{v.ID, truncate(v.Values, 4), metadata(v.Metadata)})
+	}
+
+	return c.printer(e.stdout).print(resp, []string{"ID", "VALUES", "METADATA"}, rows)
+}
+
+func vectorsUpsert(ctx context.Context, e *env, args []string) error {
+	fs, c := newFlagSet(e, "vectors upsert")
+	namespace := fs.String("namespace", "", "the namespace")
+	file := fs.String("file", "", `the file of vectors, as a JSON array or one JSON object per line, or "-" for stdin`)
+	batchSize := fs.Int("batch-size", 100, "the number of vectors per upsert request")
+	if _, err := parse(fs, c, args); err != nil {
+		return err
+	}
+	if *file == "" {
+		return fmt.Errorf("%w: --file is required", errUsage)
+	}
+	if *batchSize <= 0 {
+		return fmt.Errorf("%w: --batch-size must be positive", errUsage)
+	}
+
+	ic, err := c.indexClient()
+	if err != nil {
+		return err
+	}
+
+	var r io.Reader = e.stdin
+	if *file != "-" {
+		f, err := os.Open(*file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	var upserted int
+	batch := make([]*pinecone.Vector, 0, *batchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+
+		resp, err := ic.UpsertVectors(ctx, pinecone.UpsertVectorsParams{Vectors: batch, Namespace: *namespace})
+		if err != nil {
+			return err
+		}
+
+		upserted += resp.UpsertedCount
+		batch = make([]*pinecone.Vector, 0, *batchSize)
+
+		return nil
+	}
+
+	err = readVectors(r, func(v *pinecone.Vector) error {
+		batch = append(batch, v)
+		if len(batch) >= *batchSize {
+			return flush()
+		}
+		return nil
+	})
+	if err == nil {
+		err = flush()
+	}
+	if err != nil {
+		return fmt.Errorf("upserted %d vectors before failing: %w", upserted, err)
+	}
+
+	return c.printer(e.stdout).message(map[string]int{"upsertedCount": upserted}, "upserted %d vectors", upserted)
+}
+
+// readVectors decodes a JSON array of vectors, or a stream of JSON vectors
+// such as NDJSON.
+func readVectors(r io.Reader, fn func(v *pinecone.Vector) error) error {
+	reader := bufio.NewReader(r)
+	for {
+		b, err := reader.Peek(1)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if b[0] != ' ' && b[0] != '\t' && b[0] != '\r' && b[0] != '\n' {
+			break
+		}
+		_, _ = reader.ReadByte()
+	}
+
+	decoder := json.NewDecoder(reader)
+	array, err := reader.Peek(1)
+	if err != nil {
+		return err
+	}
+	if array[0] == '[' {
+		if _, err := decoder.Token(); err != nil {
+			return err
+		}
+	}
+
+	for n := 0; ; n++ {
+		if array[0] == '[' && !decoder.More() {
+			_, err := decoder.Token()
+			return err
+		}
+
+		var v pinecone.Vector
+		err := decoder.Decode(&v)
+		if errors.Is(err, io.EOF) && array[0] != '[' {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("invalid vector %d: %w", n, err)
+		}
+
+		if err := fn(&v); err != nil {
+			return err
+		}
+	}
+}
+
+func vectorsDelete(ctx context.Context, e *env, args []string) error {
+	fs, c := newFlagSet(e, "vectors delete")
+	namespace := fs.String("namespace", "", "the namespace")
+	all := fs.Bool("all", false, "delete every vector of the namespace")
+	ids, err := parse(fs, c, args)
+	if err != nil {
+		return err
+	}
+	if (len(ids) == 0) == !*all {
+		return fmt.Errorf("%w: either ids or --all is required", errUsage)
+	}
+
+	ic, err := c.indexClient()
+	if err != nil {
+		return err
+	}
+
+	if err := ic.DeleteVectors(ctx, pinecone.DeleteVectorsParams{IDs: ids, Namespace: *namespace, DeleteAll: *all}); err != nil {
+		return err
+	}
+
+	if *all {
+		return c.printer(e.stdout).message(map[string]bool{"deletedAll": true}, "deleted every vector of the namespace")
+	}
+
+	return c.printer(e.stdout).message(map[string]int{"deletedCount": len(ids)}, "deleted %d vectors", len(ids))
+}
+
+func vectorsQuery(ctx context.Context, e *env, args []string) error {
+	fs, c := newFlagSet(e, "vectors query")
+	namespace := fs.String("namespace", "", "the namespace")
+	vector := fs.String("vector", "", "the query vector, as a JSON array or comma-separated numbers")
+	id := fs.String("id", "", "the ID of the vector to query with")
+	topK := fs.Int64("top-k", 10, "the number of matches")
+	filter := fs.String("filter", "", "a metadata filter as JSON")
+	includeValues := fs.Bool("include-values", false, "include the values of the matches")
+	includeMetadata := fs.Bool("include-metadata", true, "include the metadata of the matches")
+	if _, err := parse(fs, c, args); err != nil {
+		return err
+	}
+	if (*vector == "") == (*id == "") {
+		return fmt.Errorf("%w: exactly one of --vector and --id is required", errUsage)
+	}
+
+	params := pinecone.QueryParams{
+		Namespace:       *namespace,
+		ID:              *id,
+		TopK:            *topK,
+		IncludeValues:   *includeValues,
+		IncludeMetadata: *includeMetadata,
+	}
+	if *vector != "" {
+		values, err := parseValues(*vector)
+		if err != nil {
+			return err
+		}
+		params.Vector = values
+	}
+	if err := parseFilter(*filter, &params.Filter); err != nil {
+		return err
+	}
+
+	ic, err := c.indexClient()
+	if err != nil {
+		return err
+	}
+
+	resp, err := ic.Query(ctx, params)
+	if err != nil {
+		return err
+	}
+
+	header := []string{"ID", "SCORE", "METADATA"}
+	if *includeValues {
+		header = []string{"ID", "SCORE", "VALUES", "METADATA"}
+	}
+
+	rows := make([][]string, 0, len(resp.Matches))
+	for _, match := range resp.Matches {
+		score := strconv.FormatFloat(float64(match.Score), 'f', 4, 32)
+		if *includeValues {
+			rows = append(rows, []string{match.ID, score, truncate(match.Values, 4), metadata(match.Metadata)})
+		} else {
+			rows = append(rows, []string{match.ID, score, metadata(match.Metadata)})
+		}
+	}
+
+	return c.printer(e.stdout).print(resp, header, rows)
+}
+
+func parseFilter(s string, filter *map[string]any) error {
+	if s == "" {
+		return nil
+	}
+	if err := json.Unmarshal([]byte(s), filter); err != nil {
+		return fmt.Errorf("%w: invalid filter: %v", errUsage, err)
+	}
+
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"net/url"
 
 	"github.com/samber/lo"
 	"github.com/samber/mo"
@@ -30,7 +31,7 @@ func (c *Client) ListIndexes() ([]string, error) {
 			return make([]string, 0), err
 		}
 
-		return make([]string, 0), fmt.Errorf("%w: %s, status code: %d", ErrRequestFailed, buffer.String(), resp.StatusCode)
+		return make([]string, 0), &RequestError{StatusCode: resp.StatusCode, Body: buffer.String()}
 	}
 
 	return indexes, nil
@@ -167,7 +168,7 @@ func (c *Client) CreateIndex(ctx context.Context, params CreateIndexParams) erro
 			return err
 		}
 
-		return fmt.Errorf("%w: %s, status code: %d", ErrRequestFailed, buffer.String(), resp.StatusCode)
+		return &RequestError{StatusCode: resp.StatusCode, Body: buffer.String()}
 	}
 
 	return nil
@@ -178,6 +179,41 @@ type DescribeIndexResponse struct {
 	Status   Status   `json:"status"`
 }
 
+// IndexModel represents an index as described by the global control plane.
+type IndexModel struct {
+	Name               string            `json:"name"`
+	Dimension          int               `json:"dimension,omitempty"`
+	Metric             CreateIndexMetric `json:"metric,omitempty"`
+	Host               string            `json:"host"`
+	VectorType         string            `json:"vector_type,omitempty"`
+	DeletionProtection string            `json:"deletion_protection,omitempty"`
+	Tags               map[string]string `json:"tags,omitempty"`
+	Status             IndexModelStatus  `json:"status"`
+	// The integrated embedding configuration, only present for indexes
+	// created for a hosted embedding model.
+	Embed *IndexEmbed `json:"embed,omitempty"`
+}
+
+// IndexModelStatus represents the status of an index described by the
+// global control plane.
+type IndexModelStatus struct {
+	Ready bool   `json:"ready"`
+	State string `json:"state"`
+}
+
+// IndexEmbed represents the integrated embedding configuration of an index.
+type IndexEmbed struct {
+	Model      string `json:"model"`
+	Metric     string `json:"metric,omitempty"`
+	Dimension  int    `json:"dimension,omitempty"`
+	VectorType string `json:"vector_type,omitempty"`
+	// Maps the input of the model, e.g. "text", to the record field
+	// holding the text to embed, e.g. "chunk_text".
+	FieldMap        map[string]string `json:"field_map"`
+	ReadParameters  map[string]any    `json:"read_parameters,omitempty"`
+	WriteParameters map[string]any    `json:"write_parameters,omitempty"`
+}
+
 type Database struct {
 	Name      string `json:"name"`
 	Metric    string `json:"metric"`
@@ -224,7 +260,42 @@ func (c *Client) DescribeIndex(ctx context.Context, indexName string) (*Describe
 			return nil, err
 		}
 
-		return nil, fmt.Errorf("%w: %s, status code: %d", ErrRequestFailed, buffer.String(), resp.StatusCode)
+		return nil, &RequestError{StatusCode: resp.StatusCode, Body: buffer.String()}
+	}
+
+	return &respBody, nil
+}
+
+// DescribeIndexModel gets a description of an index from the global control
+// plane, which unlike DescribeIndex includes the integrated embedding
+// configuration of the index.
+//
+// API Reference: https://docs.pinecone.io/reference/api/2025-04/control-plane/describe_index
+func (c *Client) DescribeIndexModel(ctx context.Context, indexName string) (*IndexModel, error) {
+	if indexName == "" {
+		return nil, fmt.Errorf("%w: index name is required", ErrInvalidParams)
+	}
+
+	var respBody IndexModel
+	resp, err := c.controlPlane.
+		R().
+		SetContentType("application/json").
+		SetSuccessResult(&respBody).
+		SetContext(ctx).
+		Get("/indexes/" + url.PathEscape(indexName))
+	if err != nil {
+		return nil, err
+	}
+	if !resp.IsSuccessState() && resp.StatusCode == http.StatusNotFound {
+		return nil, ErrIndexNotFound
+	} else if !resp.IsSuccessState() {
+		buffer := new(bytes.Buffer)
+		_, err := buffer.ReadFrom(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, &RequestError{StatusCode: resp.StatusCode, Body: buffer.String()}
 	}
 
 	return &respBody, nil
@@ -254,7 +325,7 @@ func (c *Client) DeleteIndex(ctx context.Context, indexName string) error {
 			return err
 		}
 
-		return fmt.Errorf("%w: %s, status code: %d", ErrRequestFailed, buffer.String(), resp.StatusCode)
+		return &RequestError{StatusCode: resp.StatusCode, Body: buffer.String()}
 	}
 
 	return nil
@@ -311,7 +382,7 @@ func (c *Client) ConfigureIndex(ctx context.Context, params ConfigureIndexParams
 			return err
 		}
 
-		return fmt.Errorf("%w: %s, status code: %d", ErrRequestFailed, buffer.String(), resp.StatusCode)
+		return &RequestError{StatusCode: resp.StatusCode, Body: buffer.String()}
 	}
 
 	return nil
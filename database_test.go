@@ -13,6 +13,8 @@ import (
 )
 
 func TestIndexOperations(t *testing.T) {
+	skipWithoutAPIKey(t)
+
 	t.Run("CreateIndex", func(t *testing.T) {
 		t.Run("Error", func(t *testing.T) {
 			assert := assert.New(t)
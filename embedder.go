@@ -0,0 +1,143 @@
+package pinecone
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+const defaultTextBatchSize = 100
+
+// ErrEmbedderNotConfigured is returned when a text-level helper is called on
+// an IndexClient created without WithEmbedder.
+var ErrEmbedderNotConfigured = errors.New("embedder not configured")
+
+// Embedder turns texts into dense embeddings.
+type Embedder interface {
+	// Embed returns one embedding per text, in the same order as the texts.
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// TextRecord represents a text to be embedded and upserted as a vector.
+type TextRecord struct {
+	ID           string
+	Text         string
+	SparseValues *SparseVector
+	Metadata     map[string]any
+}
+
+// UpsertTextsParams represents the parameters for an upsert texts request.
+type UpsertTextsParams struct {
+	Records   []TextRecord
+	Namespace string
+	// The number of records embedded and upserted per request. Defaults to 100.
+	BatchSize int
+}
+
+// UpsertTexts embeds the texts of the records with the configured embedder,
+// and upserts them in batches. The upserted counts of all batches are summed.
+func (ic *IndexClient) UpsertTexts(ctx context.Context, params UpsertTextsParams) (*UpsertVectorsResponse, error) {
+	if err := validateUpsertTextsParams(params); err != nil {
+		return nil, err
+	}
+	if ic.options.embedder == nil {
+		return nil, ErrEmbedderNotConfigured
+	}
+
+	batchSize := params.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultTextBatchSize
+	}
+
+	respBody := &UpsertVectorsResponse{}
+	for start := 0; start < len(params.Records); start += batchSize {
+		end := start + batchSize
+		if end > len(params.Records) {
+			end = len(params.Records)
+		}
+
+		batch := params.Records[start:end]
+		texts := make([]string, len(batch))
+		for i, record := range batch {
+			texts[i] = record.Text
+		}
+
+		embeddings, err := ic.embed(ctx, texts)
+		if err != nil {
+			return nil, err
+		}
+
+		vectors := make([]*Vector, len(batch))
+		for i, record := range batch {
+			vectors[i] = &Vector{
+				ID:           record.ID,
+				Values:       embeddings[i],
+				SparseValues: record.SparseValues,
+				Metadata:     record.Metadata,
+			}
+		}
+
+		resp, err := ic.UpsertVectors(ctx, UpsertVectorsParams{
+			Vectors:   vectors,
+			Namespace: params.Namespace,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		respBody.UpsertedCount += resp.UpsertedCount
+	}
+
+	return respBody, nil
+}
+
+// QueryText embeds the text with the configured embedder and performs a
+// query request with the embedding. The Vector and ID fields of params are
+// overridden.
+func (ic *IndexClient) QueryText(ctx context.Context, text string, params QueryParams) (*QueryResponse, error) {
+	if text == "" {
+		return nil, fmt.Errorf("%w: text is required", ErrInvalidParams)
+	}
+	if ic.options.embedder == nil {
+		return nil, ErrEmbedderNotConfigured
+	}
+
+	embeddings, err := ic.embed(ctx, []string{text})
+	if err != nil {
+		return nil, err
+	}
+
+	params.ID = ""
+	params.Vector = embeddings[0]
+
+	return ic.Query(ctx, params)
+}
+
+// embed embeds the texts and checks that the embeddings match the dimension
+// of the index, see Spec.
+func (ic *IndexClient) embed(ctx context.Context, texts []string) ([][]float32, error) {
+	embeddings, err := ic.options.embedder.Embed(ctx, texts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed texts: %w", err)
+	}
+	if len(embeddings) != len(texts) {
+		return nil, fmt.Errorf("embedder returned %d embeddings for %d texts", len(embeddings), len(texts))
+	}
+
+	spec, err := ic.Spec(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to learn the index dimension: %w", err)
+	}
+	// the dimension of an empty index described by its stats is unknown
+	if spec.Dimension == 0 {
+		return embeddings, nil
+	}
+
+	for i, embedding := range embeddings {
+		if len(embedding) != spec.Dimension {
+			return nil, fmt.Errorf("%w: embedding %d has dimension %d, index has dimension %d", ErrInvalidParams, i, len(embedding), spec.Dimension)
+		}
+	}
+
+	return embeddings, nil
+}
@@ -0,0 +1,185 @@
+// Package openai provides an embedder for OpenAI-compatible embeddings APIs,
+// to be used with pinecone.WithEmbedder.
+package openai
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/imroc/req/v3"
+	pinecone "github.com/nekomeowww/go-pinecone"
+)
+
+const (
+	defaultBaseURL        = "https://api.openai.com/v1"
+	defaultMaxBatchItems  = 2048
+	defaultMaxBatchTokens = 300000
+)
+
+var _ pinecone.Embedder = (*Embedder)(nil)
+
+// Params represents the parameters of an OpenAI-compatible embedder.
+type Params struct {
+	// The base URL of the API. Defaults to https://api.openai.com/v1.
+	BaseURL string
+	// The API key, sent as a bearer token.
+	APIKey string
+	// Required. The embedding model, e.g. text-embedding-3-small.
+	Model string
+	// The number of dimensions of the embeddings, for models that support
+	// shortening them. Zero leaves it to the model.
+	Dimensions int
+	// The maximum number of texts per request. Defaults to 2048.
+	MaxBatchItems int
+	// The maximum number of tokens per request. Defaults to 300000.
+	MaxBatchTokens int
+	// Counts the tokens of a text. Defaults to EstimateTokens.
+	TokenCounter func(text string) int
+}
+
+// Embedder embeds texts with an OpenAI-compatible embeddings API. Texts are
+// split into requests that respect both the item and the token limits.
+type Embedder struct {
+	params    Params
+	reqClient *req.Client
+}
+
+// New creates a new OpenAI-compatible embedder.
+func New(params Params) (*Embedder, error) {
+	if params.Model == "" {
+		return nil, fmt.Errorf("%w: model is required", pinecone.ErrInvalidParams)
+	}
+	if params.BaseURL == "" {
+		params.BaseURL = defaultBaseURL
+	}
+	if params.MaxBatchItems <= 0 {
+		params.MaxBatchItems = defaultMaxBatchItems
+	}
+	if params.MaxBatchTokens <= 0 {
+		params.MaxBatchTokens = defaultMaxBatchTokens
+	}
+	if params.TokenCounter == nil {
+		params.TokenCounter = EstimateTokens
+	}
+
+	reqClient := req.
+		C().
+		SetBaseURL(params.BaseURL)
+	if params.APIKey != "" {
+		reqClient = reqClient.SetCommonBearerAuthToken(params.APIKey)
+	}
+
+	return &Embedder{
+		params:    params,
+		reqClient: reqClient,
+	}, nil
+}
+
+// Debug enables debug logging and http dump for the embedder.
+func (e *Embedder) Debug() *Embedder {
+	e.reqClient.DebugLog = true
+	e.reqClient = e.reqClient.EnableDumpAll()
+	return e
+}
+
+// EstimateTokens estimates the number of tokens of a text, at roughly four
+// bytes per token.
+func EstimateTokens(text string) int {
+	return (len(text) + 3) / 4
+}
+
+type embeddingsRequest struct {
+	Model          string   `json:"model"`
+	Input          []string `json:"input"`
+	Dimensions     int      `json:"dimensions,omitempty"`
+	EncodingFormat string   `json:"encoding_format"`
+}
+
+type embeddingsResponse struct {
+	Data []struct {
+		Index     int       `json:"index"`
+		Embedding []float32 `json:"embedding"`
+	} `json:"data"`
+}
+
+// Embed returns one embedding per text, in the same order as the texts.
+func (e *Embedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	embeddings := make([][]float32, 0, len(texts))
+	for _, batch := range e.batches(texts) {
+		batchEmbeddings, err := e.embedBatch(ctx, batch)
+		if err != nil {
+			return nil, err
+		}
+
+		embeddings = append(embeddings, batchEmbeddings...)
+	}
+
+	return embeddings, nil
+}
+
+// batches splits the texts into batches that respect the item and token
+// limits. A single text over the token limit is sent on its own, and left
+// for the API to reject.
+func (e *Embedder) batches(texts []string) [][]string {
+	batches := make([][]string, 0)
+	start, tokens := 0, 0
+	for i, text := range texts {
+		textTokens := e.params.TokenCounter(text)
+		if i > start && (i-start >= e.params.MaxBatchItems || tokens+textTokens > e.params.MaxBatchTokens) {
+			batches = append(batches, texts[start:i])
+			start, tokens = i, 0
+		}
+
+		tokens += textTokens
+	}
+	if start < len(texts) {
+		batches = append(batches, texts[start:])
+	}
+
+	return batches
+}
+
+func (e *Embedder) embedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	var respBody embeddingsResponse
+	resp, err := e.reqClient.
+		R().
+		SetContentType("application/json").
+		SetBody(embeddingsRequest{
+			Model:          e.params.Model,
+			Input:          texts,
+			Dimensions:     e.params.Dimensions,
+			EncodingFormat: "float",
+		}).
+		SetSuccessResult(&respBody).
+		SetContext(ctx).
+		Post("/embeddings")
+	if err != nil {
+		return nil, err
+	}
+	if !resp.IsSuccessState() {
+		buffer := new(bytes.Buffer)
+		_, err := buffer.ReadFrom(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, fmt.Errorf("%w: %s, status code: %d", pinecone.ErrRequestFailed, buffer.String(), resp.StatusCode)
+	}
+
+	if len(respBody.Data) != len(texts) {
+		return nil, fmt.Errorf("%w: got %d embeddings for %d texts", pinecone.ErrRequestFailed, len(respBody.Data), len(texts))
+	}
+
+	sort.Slice(respBody.Data, func(i, j int) bool {
+		return respBody.Data[i].Index < respBody.Data[j].Index
+	})
+
+	embeddings := make([][]float32, len(respBody.Data))
+	for i, data := range respBody.Data {
+		embeddings[i] = data.Embedding
+	}
+
+	return embeddings, nil
+}
@@ -0,0 +1,120 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	pinecone "github.com/nekomeowww/go-pinecone"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newStubServer(t *testing.T, requests *[][]string) *httptest.Server {
+	var mu sync.Mutex
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/embeddings", r.URL.Path)
+		assert.Equal(t, "Bearer sk-test", r.Header.Get("Authorization"))
+
+		var body embeddingsRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+		mu.Lock()
+		*requests = append(*requests, body.Input)
+		mu.Unlock()
+
+		if body.Model != "stub-model" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"unknown model"}`))
+			return
+		}
+
+		// answer in reverse order to check that the embeddings are re-ordered
+		data := make([]map[string]any, 0, len(body.Input))
+		for i := len(body.Input) - 1; i >= 0; i-- {
+			data = append(data, map[string]any{
+				"index":     i,
+				"embedding": []float32{float32(len(body.Input[i])), 1},
+			})
+		}
+
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": data})
+	}))
+}
+
+func TestEmbedder(t *testing.T) {
+	t.Run("Embed", func(t *testing.T) {
+		assert := assert.New(t)
+		require := require.New(t)
+
+		var requests [][]string
+		server := newStubServer(t, &requests)
+		defer server.Close()
+
+		embedder, err := New(Params{BaseURL: server.URL, APIKey: "sk-test", Model: "stub-model"})
+		require.NoError(err)
+
+		embeddings, err := embedder.Embed(context.Background(), []string{"a", "bbb", "cc"})
+		require.NoError(err)
+		assert.Equal([][]float32{{1, 1}, {3, 1}, {2, 1}}, embeddings)
+		assert.Len(requests, 1)
+	})
+
+	t.Run("BatchByItems", func(t *testing.T) {
+		require := require.New(t)
+
+		var requests [][]string
+		server := newStubServer(t, &requests)
+		defer server.Close()
+
+		embedder, err := New(Params{BaseURL: server.URL, APIKey: "sk-test", Model: "stub-model", MaxBatchItems: 2})
+		require.NoError(err)
+
+		embeddings, err := embedder.Embed(context.Background(), []string{"a", "b", "c", "d", "e"})
+		require.NoError(err)
+		require.Len(embeddings, 5)
+		require.Equal([][]string{{"a", "b"}, {"c", "d"}, {"e"}}, requests)
+	})
+
+	t.Run("BatchByTokens", func(t *testing.T) {
+		require := require.New(t)
+
+		var requests [][]string
+		server := newStubServer(t, &requests)
+		defer server.Close()
+
+		embedder, err := New(Params{
+			BaseURL:        server.URL,
+			APIKey:         "sk-test",
+			Model:          "stub-model",
+			MaxBatchTokens: 5,
+			TokenCounter:   func(text string) int { return len(text) },
+		})
+		require.NoError(err)
+
+		_, err = embedder.Embed(context.Background(), []string{"aaa", "bb", "c", "dddddd", "e"})
+		require.NoError(err)
+		require.Equal([][]string{{"aaa", "bb"}, {"c"}, {"dddddd"}, {"e"}}, requests)
+	})
+
+	t.Run("RequestFailed", func(t *testing.T) {
+		var requests [][]string
+		server := newStubServer(t, &requests)
+		defer server.Close()
+
+		embedder, err := New(Params{BaseURL: server.URL, APIKey: "sk-test", Model: "other-model"})
+		require.NoError(t, err)
+
+		_, err = embedder.Embed(context.Background(), []string{"a"})
+		require.ErrorIs(t, err, pinecone.ErrRequestFailed)
+	})
+
+	t.Run("InvalidParams", func(t *testing.T) {
+		_, err := New(Params{})
+		require.ErrorIs(t, err, pinecone.ErrInvalidParams)
+	})
+}
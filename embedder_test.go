@@ -0,0 +1,156 @@
+package pinecone
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeEmbedder struct {
+	dimension int
+	calls     [][]string
+}
+
+func (e *fakeEmbedder) Embed(_ context.Context, texts []string) ([][]float32, error) {
+	e.calls = append(e.calls, texts)
+
+	embeddings := make([][]float32, len(texts))
+	for i, text := range texts {
+		embeddings[i] = make([]float32, e.dimension)
+		embeddings[i][0] = float32(len(text))
+	}
+
+	return embeddings, nil
+}
+
+func newEmbedderTestServer(t *testing.T, upserts *[]UpsertVectorsParams, queries *[]QueryParams) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/databases/texts", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(DescribeIndexResponse{Database: Database{Name: "texts", Dimension: 3}})
+	})
+	mux.HandleFunc("/describe_index_stats", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(DescribeIndexStatsResponse{Dimensions: 3})
+	})
+	mux.HandleFunc("/vectors/upsert", func(w http.ResponseWriter, r *http.Request) {
+		var params UpsertVectorsParams
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&params))
+		*upserts = append(*upserts, params)
+		_ = json.NewEncoder(w).Encode(UpsertVectorsResponse{UpsertedCount: len(params.Vectors)})
+	})
+	mux.HandleFunc("/query", func(w http.ResponseWriter, r *http.Request) {
+		var params QueryParams
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&params))
+		*queries = append(*queries, params)
+		_ = json.NewEncoder(w).Encode(QueryResponse{Matches: []*QueryVector{{Vector: Vector{ID: "a"}, Score: 1}}})
+	})
+
+	return httptest.NewServer(mux)
+}
+
+func TestUpsertTexts(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	var upserts []UpsertVectorsParams
+	var queries []QueryParams
+	server := newEmbedderTestServer(t, &upserts, &queries)
+	defer server.Close()
+
+	embedder := &fakeEmbedder{dimension: 3}
+	ic := newTestIndexClient(server.URL, WithIndexName("texts"), WithEmbedder(embedder))
+
+	resp, err := ic.UpsertTexts(context.Background(), UpsertTextsParams{
+		Records: []TextRecord{
+			{ID: "a", Text: "a", Metadata: map[string]any{"lang": "en"}},
+			{ID: "b", Text: "bb"},
+			{ID: "c", Text: "ccc"},
+		},
+		Namespace: "docs",
+		BatchSize: 2,
+	})
+	require.NoError(err)
+	assert.Equal(3, resp.UpsertedCount)
+	assert.Equal([][]string{{"a", "bb"}, {"ccc"}}, embedder.calls)
+	require.Len(upserts, 2)
+	assert.Equal("docs", upserts[0].Namespace)
+	assert.Equal([]float32{2, 0, 0}, upserts[0].Vectors[1].Values)
+	assert.Equal("en", upserts[0].Vectors[0].Metadata["lang"])
+
+	_, err = ic.UpsertTexts(context.Background(), UpsertTextsParams{Records: []TextRecord{{ID: "empty"}}})
+	require.ErrorIs(err, ErrInvalidParams)
+}
+
+func TestQueryText(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		assert := assert.New(t)
+		require := require.New(t)
+
+		var upserts []UpsertVectorsParams
+		var queries []QueryParams
+		server := newEmbedderTestServer(t, &upserts, &queries)
+		defer server.Close()
+
+		ic := newTestIndexClient(server.URL, WithIndexName("texts"), WithEmbedder(&fakeEmbedder{dimension: 3}))
+
+		resp, err := ic.QueryText(context.Background(), "hello", QueryParams{TopK: 1})
+		require.NoError(err)
+		assert.Len(resp.Matches, 1)
+		require.Len(queries, 1)
+		assert.Equal([]float32{5, 0, 0}, queries[0].Vector)
+	})
+
+	t.Run("DimensionMismatch", func(t *testing.T) {
+		var upserts []UpsertVectorsParams
+		var queries []QueryParams
+		server := newEmbedderTestServer(t, &upserts, &queries)
+		defer server.Close()
+
+		ic := newTestIndexClient(server.URL, WithIndexName("texts"), WithEmbedder(&fakeEmbedder{dimension: 4}))
+
+		_, err := ic.QueryText(context.Background(), "hello", QueryParams{TopK: 1})
+		require.ErrorIs(t, err, ErrInvalidParams)
+		assert.Contains(t, err.Error(), "dimension 4")
+		assert.Empty(t, queries)
+	})
+
+	t.Run("HostOnly", func(t *testing.T) {
+		var upserts []UpsertVectorsParams
+		var queries []QueryParams
+		server := newEmbedderTestServer(t, &upserts, &queries)
+		defer server.Close()
+
+		// the dimension is learned from the stats of the index
+		ic := newTestIndexClient(server.URL, WithEmbedder(&fakeEmbedder{dimension: 3}))
+		_, err := ic.QueryText(context.Background(), "hello", QueryParams{TopK: 1})
+		require.NoError(t, err)
+		assert.Len(t, queries, 1)
+
+		ic = newTestIndexClient(server.URL, WithEmbedder(&fakeEmbedder{dimension: 4}))
+		_, err = ic.QueryText(context.Background(), "hello", QueryParams{TopK: 1})
+		require.ErrorIs(t, err, ErrInvalidParams)
+	})
+
+	t.Run("WithDimension", func(t *testing.T) {
+		var upserts []UpsertVectorsParams
+		var queries []QueryParams
+		server := newEmbedderTestServer(t, &upserts, &queries)
+		defer server.Close()
+
+		ic := newTestIndexClient(server.URL, WithIndexName("texts"), WithDimension(4), WithEmbedder(&fakeEmbedder{dimension: 4}))
+		_, err := ic.QueryText(context.Background(), "hello", QueryParams{TopK: 1})
+		require.NoError(t, err)
+		assert.Len(t, queries, 1)
+	})
+
+	t.Run("NotConfigured", func(t *testing.T) {
+		ic := newTestIndexClient("http://127.0.0.1:0")
+
+		_, err := ic.QueryText(context.Background(), "hello", QueryParams{TopK: 1})
+		require.ErrorIs(t, err, ErrEmbedderNotConfigured)
+	})
+}
@@ -0,0 +1,154 @@
+package sparse
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+
+	pinecone "github.com/nekomeowww/go-pinecone"
+)
+
+// ErrEmptyCorpus is returned when a BM25 encoder is fit on an empty corpus.
+var ErrEmptyCorpus = errors.New("corpus is empty")
+
+// BM25Params holds the parameters of a BM25 encoder, learned by fitting the
+// encoder on a corpus.
+type BM25Params struct {
+	// Term frequency saturation.
+	K1 float64 `json:"k1"`
+	// Document length normalization.
+	B float64 `json:"b"`
+	// The number of documents in the corpus.
+	NumDocs int `json:"n_docs"`
+	// The average number of tokens per document in the corpus.
+	AvgDocLen float64 `json:"avgdl"`
+	// The number of documents each token index appears in.
+	DocFreq map[int32]int `json:"doc_freq"`
+}
+
+// BM25 encodes text with the Okapi BM25 ranking function, split the same way
+// as Pinecone's pinecone-text library: documents carry the saturated and
+// length-normalized term frequencies, queries carry the normalized inverse
+// document frequencies, so the dot product of both is the BM25 score.
+type BM25 struct {
+	tokenizer Tokenizer
+	params    BM25Params
+}
+
+// NewBM25 creates a BM25 encoder with the default parameters k1 = 1.2 and
+// b = 0.75. The encoder must be fit on a corpus, or loaded from saved
+// parameters, before it is used. A nil tokenizer defaults to NewWordTokenizer.
+func NewBM25(tokenizer Tokenizer) *BM25 {
+	return NewBM25FromParams(tokenizer, BM25Params{K1: 1.2, B: 0.75})
+}
+
+// NewBM25FromParams creates a BM25 encoder from already learned parameters.
+// A nil tokenizer defaults to NewWordTokenizer.
+func NewBM25FromParams(tokenizer Tokenizer, params BM25Params) *BM25 {
+	if tokenizer == nil {
+		tokenizer = NewWordTokenizer()
+	}
+	if params.DocFreq == nil {
+		params.DocFreq = make(map[int32]int)
+	}
+
+	return &BM25{
+		tokenizer: tokenizer,
+		params:    params,
+	}
+}
+
+// LoadBM25 creates a BM25 encoder from parameters saved as JSON with Save.
+// A nil tokenizer defaults to NewWordTokenizer.
+func LoadBM25(r io.Reader, tokenizer Tokenizer) (*BM25, error) {
+	var params BM25Params
+	if err := json.NewDecoder(r).Decode(&params); err != nil {
+		return nil, err
+	}
+
+	return NewBM25FromParams(tokenizer, params), nil
+}
+
+// Params returns the parameters of the encoder.
+func (e *BM25) Params() BM25Params {
+	return e.params
+}
+
+// Save writes the parameters of the encoder as JSON.
+func (e *BM25) Save(w io.Writer) error {
+	return json.NewEncoder(w).Encode(e.params)
+}
+
+// Fit learns the document frequencies and the average document length from
+// the corpus. Any previously learned statistics are replaced.
+func (e *BM25) Fit(corpus []string) error {
+	if len(corpus) == 0 {
+		return ErrEmptyCorpus
+	}
+
+	docFreq := make(map[int32]int)
+	totalLen := 0
+	for _, doc := range corpus {
+		tokens := e.tokenizer.Tokenize(doc)
+		totalLen += len(tokens)
+
+		for index := range termFrequencies(tokens, TokenIndex) {
+			docFreq[index]++
+		}
+	}
+
+	e.params.NumDocs = len(corpus)
+	e.params.AvgDocLen = float64(totalLen) / float64(len(corpus))
+	e.params.DocFreq = docFreq
+
+	return nil
+}
+
+// EncodeDocument encodes a document to be upserted into the index. Each
+// token carries its BM25 term frequency component. Before the encoder is
+// fit, the document length is used as the average document length.
+func (e *BM25) EncodeDocument(text string) *pinecone.SparseVector {
+	tokens := e.tokenizer.Tokenize(text)
+	frequencies := termFrequencies(tokens, TokenIndex)
+
+	docLen := float64(len(tokens))
+	avgDocLen := e.params.AvgDocLen
+	if avgDocLen <= 0 {
+		avgDocLen = math.Max(docLen, 1)
+	}
+
+	values := make(map[int32]float32, len(frequencies))
+	for index, tf := range frequencies {
+		tf := float64(tf)
+		values[index] = float32(tf * (e.params.K1 + 1) / (tf + e.params.K1*(1-e.params.B+e.params.B*docLen/avgDocLen)))
+	}
+
+	return newSparseVector(values)
+}
+
+// EncodeQuery encodes a query to search the index with. Each distinct token
+// carries its inverse document frequency, normalized so that all values sum
+// up to 1.
+func (e *BM25) EncodeQuery(text string) *pinecone.SparseVector {
+	frequencies := termFrequencies(e.tokenizer.Tokenize(text), TokenIndex)
+
+	idfs := make(map[int32]float64, len(frequencies))
+	var sum float64
+	for index := range frequencies {
+		idf := math.Log((float64(e.params.NumDocs) + 1) / (float64(e.params.DocFreq[index]) + 0.5))
+		idfs[index] = idf
+		sum += idf
+	}
+
+	values := make(map[int32]float32, len(idfs))
+	for index, idf := range idfs {
+		if sum == 0 {
+			break
+		}
+
+		values[index] = float32(idf / sum)
+	}
+
+	return newSparseVector(values)
+}
@@ -0,0 +1,107 @@
+package sparse
+
+import (
+	"bytes"
+	"sort"
+	"testing"
+
+	pinecone "github.com/nekomeowww/go-pinecone"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var corpus = []string{
+	"The quick brown fox jumps over the lazy dog",
+	"A lazy dog sleeps all day",
+	"Foxes are quick and clever animals",
+	"Brown bears are not foxes",
+}
+
+func requireSortedUnique(t *testing.T, vector *pinecone.SparseVector) {
+	require.Len(t, vector.Values, len(vector.Indices))
+	require.True(t, sort.SliceIsSorted(vector.Indices, func(i, j int) bool {
+		return vector.Indices[i] < vector.Indices[j]
+	}))
+	for i := 1; i < len(vector.Indices); i++ {
+		require.NotEqual(t, vector.Indices[i-1], vector.Indices[i])
+	}
+}
+
+func dot(a, b *pinecone.SparseVector) float32 {
+	values := make(map[int32]float32, len(a.Indices))
+	for i, index := range a.Indices {
+		values[index] = a.Values[i]
+	}
+
+	var score float32
+	for i, index := range b.Indices {
+		score += values[index] * b.Values[i]
+	}
+
+	return score
+}
+
+func TestWordTokenizer(t *testing.T) {
+	tokens := NewWordTokenizer().Tokenize("The Quick, brown-fox! Jumps over 2 dogs.")
+	assert.Equal(t, []string{"quick", "brown", "fox", "jumps", "over", "2", "dogs"}, tokens)
+}
+
+func TestBM25(t *testing.T) {
+	t.Run("Fit", func(t *testing.T) {
+		assert := assert.New(t)
+		require := require.New(t)
+
+		encoder := NewBM25(nil)
+		require.ErrorIs(encoder.Fit(nil), ErrEmptyCorpus)
+		require.NoError(encoder.Fit(corpus))
+
+		params := encoder.Params()
+		assert.Equal(4, params.NumDocs)
+		assert.Equal(2, params.DocFreq[TokenIndex("lazy")])
+		assert.Equal(2, params.DocFreq[TokenIndex("brown")])
+		assert.Equal(1, params.DocFreq[TokenIndex("bears")])
+	})
+
+	t.Run("Encode", func(t *testing.T) {
+		assert := assert.New(t)
+		require := require.New(t)
+
+		encoder := NewBM25(nil)
+		require.NoError(encoder.Fit(corpus))
+
+		docs := EncodeDocuments(encoder, corpus)
+		for _, doc := range docs {
+			requireSortedUnique(t, doc)
+		}
+
+		query := encoder.EncodeQuery("lazy lazy dog")
+		requireSortedUnique(t, query)
+		assert.Len(query.Indices, 2)
+
+		var sum float32
+		for _, value := range query.Values {
+			sum += value
+		}
+		assert.InDelta(1, sum, 1e-6)
+
+		assert.Greater(dot(query, docs[1]), dot(query, docs[2]))
+		assert.Greater(dot(query, docs[1]), dot(query, docs[0]))
+	})
+
+	t.Run("SaveAndLoad", func(t *testing.T) {
+		assert := assert.New(t)
+		require := require.New(t)
+
+		encoder := NewBM25(nil)
+		require.NoError(encoder.Fit(corpus))
+
+		buffer := new(bytes.Buffer)
+		require.NoError(encoder.Save(buffer))
+
+		loaded, err := LoadBM25(buffer, nil)
+		require.NoError(err)
+		assert.Equal(encoder.Params(), loaded.Params())
+		assert.Equal(encoder.EncodeQuery("quick fox"), loaded.EncodeQuery("quick fox"))
+		assert.Equal(encoder.EncodeDocument(corpus[0]), loaded.EncodeDocument(corpus[0]))
+	})
+}
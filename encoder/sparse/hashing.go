@@ -0,0 +1,87 @@
+package sparse
+
+import (
+	"math"
+
+	pinecone "github.com/nekomeowww/go-pinecone"
+)
+
+// HashingEncoder encodes text with the hashing trick: every token is hashed
+// into a fixed number of features, and weighted by its term frequency. It
+// needs no fitting, and documents and queries are encoded the same way.
+type HashingEncoder struct {
+	tokenizer   Tokenizer
+	numFeatures int32
+	sublinearTF bool
+	normalize   bool
+}
+
+// HashingEncoderParams represents the parameters of a feature hashing encoder.
+type HashingEncoderParams struct {
+	// The tokenizer to split text with. Defaults to NewWordTokenizer.
+	Tokenizer Tokenizer
+	// The number of features tokens are hashed into. Zero or less uses the
+	// whole non-negative int32 range.
+	NumFeatures int32
+	// Weight tokens by 1 + log(tf) instead of the raw term frequency.
+	SublinearTF bool
+	// Scale the encoded vectors to unit L2 norm.
+	Normalize bool
+}
+
+// NewHashingEncoder creates a feature hashing encoder.
+func NewHashingEncoder(params HashingEncoderParams) *HashingEncoder {
+	if params.Tokenizer == nil {
+		params.Tokenizer = NewWordTokenizer()
+	}
+
+	return &HashingEncoder{
+		tokenizer:   params.Tokenizer,
+		numFeatures: params.NumFeatures,
+		sublinearTF: params.SublinearTF,
+		normalize:   params.Normalize,
+	}
+}
+
+// EncodeDocument encodes a document to be upserted into the index.
+func (e *HashingEncoder) EncodeDocument(text string) *pinecone.SparseVector {
+	return e.encode(text)
+}
+
+// EncodeQuery encodes a query to search the index with.
+func (e *HashingEncoder) EncodeQuery(text string) *pinecone.SparseVector {
+	return e.encode(text)
+}
+
+func (e *HashingEncoder) encode(text string) *pinecone.SparseVector {
+	values := termFrequencies(e.tokenizer.Tokenize(text), e.index)
+
+	if e.sublinearTF {
+		for index, tf := range values {
+			values[index] = float32(1 + math.Log(float64(tf)))
+		}
+	}
+
+	if e.normalize {
+		var norm float64
+		for _, value := range values {
+			norm += float64(value) * float64(value)
+		}
+
+		norm = math.Sqrt(norm)
+		for index, value := range values {
+			values[index] = float32(float64(value) / norm)
+		}
+	}
+
+	return newSparseVector(values)
+}
+
+func (e *HashingEncoder) index(token string) int32 {
+	index := TokenIndex(token)
+	if e.numFeatures > 0 {
+		index %= e.numFeatures
+	}
+
+	return index
+}
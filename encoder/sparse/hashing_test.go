@@ -0,0 +1,48 @@
+package sparse
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHashingEncoder(t *testing.T) {
+	t.Run("TermFrequencies", func(t *testing.T) {
+		assert := assert.New(t)
+
+		encoder := NewHashingEncoder(HashingEncoderParams{})
+		vector := encoder.EncodeDocument("fox fox dog")
+		requireSortedUnique(t, vector)
+		assert.Len(vector.Indices, 2)
+		assert.ElementsMatch([]float32{2, 1}, vector.Values)
+		assert.Equal(vector, encoder.EncodeQuery("fox fox dog"))
+	})
+
+	t.Run("NumFeatures", func(t *testing.T) {
+		encoder := NewHashingEncoder(HashingEncoderParams{NumFeatures: 4})
+		vector := encoder.EncodeDocument(corpus[0] + " " + corpus[2])
+		requireSortedUnique(t, vector)
+		for _, index := range vector.Indices {
+			assert.Less(t, index, int32(4))
+			assert.GreaterOrEqual(t, index, int32(0))
+		}
+	})
+
+	t.Run("Normalize", func(t *testing.T) {
+		encoder := NewHashingEncoder(HashingEncoderParams{SublinearTF: true, Normalize: true})
+		vector := encoder.EncodeDocument("fox fox fox dog")
+
+		var norm float64
+		for _, value := range vector.Values {
+			norm += float64(value) * float64(value)
+		}
+		assert.InDelta(t, 1, math.Sqrt(norm), 1e-6)
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		vector := NewHashingEncoder(HashingEncoderParams{Normalize: true}).EncodeDocument("the and of")
+		assert.Empty(t, vector.Indices)
+		assert.Empty(t, vector.Values)
+	})
+}
@@ -0,0 +1,89 @@
+// Package sparse provides encoders that turn text into sparse vectors, for
+// keyword-aware hybrid search with Pinecone.
+//
+// Documents and queries are encoded differently: documents are encoded when
+// they are upserted into the index, queries when the index is searched. The
+// emitted sparse vectors always have sorted and de-duplicated indices.
+package sparse
+
+import (
+	"hash/fnv"
+	"sort"
+
+	pinecone "github.com/nekomeowww/go-pinecone"
+)
+
+// Encoder encodes text into sparse vectors.
+type Encoder interface {
+	// EncodeDocument encodes a document to be upserted into the index.
+	EncodeDocument(text string) *pinecone.SparseVector
+	// EncodeQuery encodes a query to search the index with.
+	EncodeQuery(text string) *pinecone.SparseVector
+}
+
+// EncodeDocuments encodes each of the given documents with the encoder.
+func EncodeDocuments(encoder Encoder, texts []string) []*pinecone.SparseVector {
+	vectors := make([]*pinecone.SparseVector, len(texts))
+	for i, text := range texts {
+		vectors[i] = encoder.EncodeDocument(text)
+	}
+
+	return vectors
+}
+
+// EncodeQueries encodes each of the given queries with the encoder.
+func EncodeQueries(encoder Encoder, texts []string) []*pinecone.SparseVector {
+	vectors := make([]*pinecone.SparseVector, len(texts))
+	for i, text := range texts {
+		vectors[i] = encoder.EncodeQuery(text)
+	}
+
+	return vectors
+}
+
+// TokenIndex maps a token to its index in the sparse vector space. Tokens are
+// hashed with 32-bit FNV-1a, and the hash is folded into the non-negative
+// int32 range used by pinecone.SparseVector.
+func TokenIndex(token string) int32 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(token))
+
+	return int32(h.Sum32() & 0x7fffffff)
+}
+
+// newSparseVector builds a sparse vector from the given index to value map,
+// with sorted indices. Zero values are dropped.
+func newSparseVector(values map[int32]float32) *pinecone.SparseVector {
+	indices := make([]int32, 0, len(values))
+	for index, value := range values {
+		if value == 0 {
+			continue
+		}
+
+		indices = append(indices, index)
+	}
+
+	sort.Slice(indices, func(i, j int) bool {
+		return indices[i] < indices[j]
+	})
+
+	vector := &pinecone.SparseVector{
+		Indices: indices,
+		Values:  make([]float32, len(indices)),
+	}
+	for i, index := range indices {
+		vector.Values[i] = values[index]
+	}
+
+	return vector
+}
+
+// termFrequencies counts the occurrences of each token by its index.
+func termFrequencies(tokens []string, index func(token string) int32) map[int32]float32 {
+	frequencies := make(map[int32]float32, len(tokens))
+	for _, token := range tokens {
+		frequencies[index(token)]++
+	}
+
+	return frequencies
+}
@@ -0,0 +1,69 @@
+package sparse
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Tokenizer splits text into tokens.
+type Tokenizer interface {
+	Tokenize(text string) []string
+}
+
+// DefaultStopwords is a small list of common English words that carry little
+// meaning for keyword search.
+var DefaultStopwords = []string{
+	"a", "an", "and", "are", "as", "at", "be", "but", "by", "for", "if", "in",
+	"into", "is", "it", "no", "not", "of", "on", "or", "such", "that", "the",
+	"their", "then", "there", "these", "they", "this", "to", "was", "will", "with",
+}
+
+// WordTokenizer splits text on any character that is not a letter or a digit.
+type WordTokenizer struct {
+	// Lowercase tokens before they are filtered.
+	Lowercase bool
+	// Tokens that are dropped.
+	Stopwords map[string]struct{}
+	// Tokens shorter than MinLength runes are dropped.
+	MinLength int
+}
+
+// NewWordTokenizer creates a tokenizer that lowercases the text and drops the
+// DefaultStopwords.
+func NewWordTokenizer() *WordTokenizer {
+	stopwords := make(map[string]struct{}, len(DefaultStopwords))
+	for _, word := range DefaultStopwords {
+		stopwords[word] = struct{}{}
+	}
+
+	return &WordTokenizer{
+		Lowercase: true,
+		Stopwords: stopwords,
+		MinLength: 1,
+	}
+}
+
+// Tokenize splits the text into tokens.
+func (t *WordTokenizer) Tokenize(text string) []string {
+	if t.Lowercase {
+		text = strings.ToLower(text)
+	}
+
+	fields := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	tokens := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if len([]rune(field)) < t.MinLength {
+			continue
+		}
+		if _, ok := t.Stopwords[field]; ok {
+			continue
+		}
+
+		tokens = append(tokens, field)
+	}
+
+	return tokens
+}
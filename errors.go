@@ -1,6 +1,9 @@
 package pinecone
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var (
 	// ErrRequestFailed is returned and wrapped when a request to the Pinecone API fails.
@@ -10,3 +13,20 @@ var (
 	// ErrInvalidParams is returned when an invalid parameter is passed to a function.
 	ErrInvalidParams = errors.New("invalid params")
 )
+
+// RequestError is returned when the Pinecone API responds to a request with
+// an unsuccessful status code. It wraps ErrRequestFailed.
+type RequestError struct {
+	// The status code of the response.
+	StatusCode int
+	// The body of the response.
+	Body string
+}
+
+func (e *RequestError) Error() string {
+	return fmt.Sprintf("%s: %s, status code: %d", ErrRequestFailed, e.Body, e.StatusCode)
+}
+
+func (e *RequestError) Unwrap() error {
+	return ErrRequestFailed
+}
@@ -0,0 +1,207 @@
+// Package export dumps the vectors of a namespace to files on disk, as
+// NDJSON, Parquet, or a NumPy .npy matrix with a sidecar file of IDs, along
+// with a manifest recording the index and the checksums of the files.
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/nekomeowww/go-pinecone"
+)
+
+// ErrChecksumMismatch is returned by Manifest.Verify when a file does not
+// match its recorded checksum or size.
+var ErrChecksumMismatch = errors.New("checksum mismatch")
+
+// ManifestFileName is the name of the manifest written next to the files.
+const ManifestFileName = "manifest.json"
+
+// Format is the file format of an export.
+type Format string
+
+const (
+	// FormatNDJSON writes one JSON-encoded pinecone.Vector per line.
+	FormatNDJSON Format = "ndjson"
+	// FormatParquet writes a Parquet file with the columns id, values,
+	// sparse_indices, sparse_values and metadata, the latter as JSON.
+	FormatParquet Format = "parquet"
+	// FormatNPY writes the dense values as a float32 .npy matrix, and the IDs
+	// as JSON strings one per line in a sidecar file, in the same order.
+	// Metadata and sparse values are not exported.
+	FormatNPY Format = "npy"
+)
+
+// Source is the index to export vectors from, implemented by
+// *pinecone.IndexClient.
+type Source interface {
+	Spec(ctx context.Context) (*pinecone.IndexSpec, error)
+	Scan(ctx context.Context, params pinecone.ScanParams, fn func(vector *pinecone.Vector) error) error
+}
+
+var _ Source = (*pinecone.IndexClient)(nil)
+
+// Options represents the options of an export.
+type Options struct {
+	// Required. The directory to write the files to, created if missing.
+	Dir string
+	// The namespace to export.
+	Namespace string
+	// Only the vectors whose IDs start with the prefix are exported.
+	Prefix string
+	// The file format. Defaults to FormatNDJSON.
+	Format Format
+	// The compression of the files. Defaults to none.
+	Compression Compression
+	// The page size and concurrency of the scan, see pinecone.ScanParams.
+	PageSize    int
+	Concurrency int
+}
+
+// Manifest describes an export.
+type Manifest struct {
+	Namespace   string                     `json:"namespace"`
+	Dimension   int                        `json:"dimension"`
+	Metric      pinecone.CreateIndexMetric `json:"metric,omitempty"`
+	VectorCount int64                      `json:"vector_count"`
+	Format      Format                     `json:"format"`
+	Compression Compression                `json:"compression,omitempty"`
+	CreatedAt   time.Time                  `json:"created_at"`
+	Files       []ManifestFile             `json:"files"`
+}
+
+// ManifestFile describes a file of an export.
+type ManifestFile struct {
+	// The name of the file, relative to the directory of the manifest.
+	Name   string `json:"name"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// Namespace exports every vector of a namespace to files in opts.Dir, then
+// writes the manifest. The manifest is only written once every file is
+// complete, so a directory without one holds an interrupted export.
+func Namespace(ctx context.Context, source Source, opts Options) (*Manifest, error) {
+	if opts.Dir == "" {
+		return nil, fmt.Errorf("%w: dir is required", pinecone.ErrInvalidParams)
+	}
+	if opts.Format == "" {
+		opts.Format = FormatNDJSON
+	}
+	if err := opts.Compression.validate(); err != nil {
+		return nil, err
+	}
+
+	spec, err := source.Spec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(opts.Dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	manifest := &Manifest{
+		Namespace:   opts.Namespace,
+		Dimension:   spec.Dimension,
+		Metric:      spec.Metric,
+		Format:      opts.Format,
+		Compression: opts.Compression,
+		CreatedAt:   time.Now().UTC(),
+	}
+
+	var w vectorWriter
+	switch opts.Format {
+	case FormatNDJSON:
+		w, err = newNDJSONWriter(opts.Dir, opts.Compression)
+	case FormatParquet:
+		w, err = newParquetWriter(opts.Dir, opts.Compression)
+	case FormatNPY:
+		w, err = newNPYWriter(opts.Dir, opts.Compression, spec.Dimension)
+	default:
+		return nil, fmt.Errorf("%w: unknown format %q", pinecone.ErrInvalidParams, opts.Format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = source.Scan(ctx, pinecone.ScanParams{
+		Namespace:     opts.Namespace,
+		Prefix:        opts.Prefix,
+		IncludeValues: true,
+		PageSize:      opts.PageSize,
+		Concurrency:   opts.Concurrency,
+	}, func(vector *pinecone.Vector) error {
+		if manifest.Dimension == 0 {
+			manifest.Dimension = len(vector.Values)
+		}
+
+		if err := w.write(vector); err != nil {
+			return fmt.Errorf("failed to write vector %q: %w", vector.ID, err)
+		}
+
+		manifest.VectorCount++
+
+		return nil
+	})
+	if err != nil {
+		w.abort()
+		return nil, err
+	}
+
+	manifest.Files, err = w.close()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := manifest.write(opts.Dir); err != nil {
+		return nil, err
+	}
+
+	return manifest, nil
+}
+
+func (m *Manifest) write(dir string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(dir, ManifestFileName), append(data, '\n'), 0o644)
+}
+
+// ReadManifest reads the manifest of the export in dir.
+func ReadManifest(dir string) (*Manifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, ManifestFileName))
+	if err != nil {
+		return nil, err
+	}
+
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("invalid manifest: %w", err)
+	}
+
+	return &manifest, nil
+}
+
+// Verify checks the size and checksum of every file of the export in dir.
+func (m *Manifest) Verify(dir string) error {
+	for _, file := range m.Files {
+		actual, err := checksumFile(filepath.Join(dir, file.Name))
+		if err != nil {
+			return err
+		}
+
+		if actual.Size != file.Size || actual.SHA256 != file.SHA256 {
+			return fmt.Errorf("%w: %s", ErrChecksumMismatch, file.Name)
+		}
+	}
+
+	return nil
+}
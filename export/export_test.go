@@ -0,0 +1,189 @@
+package export
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nekomeowww/go-pinecone"
+	"github.com/nekomeowww/go-pinecone/internal/npy"
+	"github.com/parquet-go/parquet-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSource struct {
+	vectors []*pinecone.Vector
+}
+
+func (s *fakeSource) Spec(ctx context.Context) (*pinecone.IndexSpec, error) {
+	return &pinecone.IndexSpec{Dimension: 2, Metric: pinecone.CreateIndexMetricCosine}, nil
+}
+
+func (s *fakeSource) Scan(ctx context.Context, params pinecone.ScanParams, fn func(vector *pinecone.Vector) error) error {
+	for _, v := range s.vectors {
+		if !strings.HasPrefix(v.ID, params.Prefix) {
+			continue
+		}
+
+		copied := *v
+		if err := fn(&copied); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func newFakeSource() *fakeSource {
+	return &fakeSource{vectors: []*pinecone.Vector{
+		{ID: "a", Values: []float32{1, 2}, Metadata: map[string]any{"genre": "drama"}},
+		{ID: "b", Values: []float32{3, 4}, SparseValues: &pinecone.SparseVector{Indices: []int32{1, 5}, Values: []float32{0.5, 0.25}}},
+		{ID: "c", Values: []float32{5, 6}},
+	}}
+}
+
+func TestNamespace_NDJSON(t *testing.T) {
+	for _, compression := range []Compression{CompressionNone, CompressionGzip, CompressionZstd} {
+		t.Run(string(compression), func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			dir := t.TempDir()
+			manifest, err := Namespace(context.Background(), newFakeSource(), Options{Dir: dir, Namespace: "ns", Compression: compression})
+			require.NoError(err)
+			assert.Equal("ns", manifest.Namespace)
+			assert.Equal(2, manifest.Dimension)
+			assert.Equal(pinecone.CreateIndexMetricCosine, manifest.Metric)
+			assert.Equal(int64(3), manifest.VectorCount)
+			require.Len(manifest.Files, 1)
+			assert.Equal(NDJSONFileName+compression.Extension(), manifest.Files[0].Name)
+
+			read, err := ReadManifest(dir)
+			require.NoError(err)
+			assert.Equal(manifest.Files, read.Files)
+			require.NoError(read.Verify(dir))
+
+			file, err := Open(filepath.Join(dir, manifest.Files[0].Name))
+			require.NoError(err)
+			defer file.Close()
+
+			var vectors []*pinecone.Vector
+			scanner := bufio.NewScanner(file)
+			for scanner.Scan() {
+				var v pinecone.Vector
+				require.NoError(json.Unmarshal(scanner.Bytes(), &v))
+				vectors = append(vectors, &v)
+			}
+			require.NoError(scanner.Err())
+			assert.Equal(newFakeSource().vectors, vectors)
+		})
+	}
+}
+
+func TestNamespace_Parquet(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	dir := t.TempDir()
+	manifest, err := Namespace(context.Background(), newFakeSource(), Options{Dir: dir, Format: FormatParquet, Compression: CompressionZstd})
+	require.NoError(err)
+	require.Len(manifest.Files, 1)
+	assert.Equal(ParquetFileName, manifest.Files[0].Name)
+
+	file, err := os.Open(filepath.Join(dir, ParquetFileName))
+	require.NoError(err)
+	defer file.Close()
+
+	rows, err := parquet.Read[parquetRow](file, manifest.Files[0].Size)
+	require.NoError(err)
+	require.Len(rows, 3)
+
+	metadata := `{"genre":"drama"}`
+	assert.Equal(parquetRow{ID: "a", Values: []float32{1, 2}, SparseIndices: []int32{}, SparseValues: []float32{}, Metadata: &metadata}, rows[0])
+	assert.Equal(parquetRow{ID: "b", Values: []float32{3, 4}, SparseIndices: []int32{1, 5}, SparseValues: []float32{0.5, 0.25}}, rows[1])
+}
+
+func TestNamespace_NPY(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	// a newline in an ID must not break the alignment of the ids file
+	source := newFakeSource()
+	source.vectors[0].ID = "a\nfirst"
+
+	dir := t.TempDir()
+	manifest, err := Namespace(context.Background(), source, Options{Dir: dir, Format: FormatNPY, Compression: CompressionGzip})
+	require.NoError(err)
+	require.Len(manifest.Files, 2)
+	assert.Equal(NPYFileName+".gz", manifest.Files[0].Name)
+	assert.Equal(IDsFileName+".gz", manifest.Files[1].Name)
+	require.NoError(manifest.Verify(dir))
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(err)
+	assert.Len(entries, 3, "the temporary file must be removed")
+
+	matrix, err := Open(filepath.Join(dir, manifest.Files[0].Name))
+	require.NoError(err)
+	defer matrix.Close()
+
+	rows, columns, err := npy.ReadHeader(matrix)
+	require.NoError(err)
+	assert.Equal(3, rows)
+	assert.Equal(2, columns)
+
+	data, err := io.ReadAll(matrix)
+	require.NoError(err)
+	require.Len(data, 3*2*4)
+	assert.Equal(float32(6), math.Float32frombits(binary.LittleEndian.Uint32(data[20:])))
+
+	ids, err := Open(filepath.Join(dir, manifest.Files[1].Name))
+	require.NoError(err)
+	defer ids.Close()
+
+	idsData, err := io.ReadAll(ids)
+	require.NoError(err)
+	assert.Equal("\"a\\nfirst\"\n\"b\"\n\"c\"\n", string(idsData))
+}
+
+func TestNamespace_NPYDimensionMismatch(t *testing.T) {
+	source := newFakeSource()
+	source.vectors[1].Values = []float32{1}
+
+	dir := t.TempDir()
+	_, err := Namespace(context.Background(), source, Options{Dir: dir, Format: FormatNPY})
+	require.ErrorIs(t, err, pinecone.ErrInvalidParams)
+
+	_, err = os.Stat(filepath.Join(dir, ManifestFileName))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestManifest_Verify(t *testing.T) {
+	require := require.New(t)
+
+	dir := t.TempDir()
+	manifest, err := Namespace(context.Background(), newFakeSource(), Options{Dir: dir})
+	require.NoError(err)
+
+	require.NoError(os.WriteFile(filepath.Join(dir, NDJSONFileName), []byte("{}\n"), 0o644))
+	require.ErrorIs(manifest.Verify(dir), ErrChecksumMismatch)
+}
+
+func TestNamespace_InvalidOptions(t *testing.T) {
+	_, err := Namespace(context.Background(), newFakeSource(), Options{})
+	require.ErrorIs(t, err, pinecone.ErrInvalidParams)
+
+	_, err = Namespace(context.Background(), newFakeSource(), Options{Dir: t.TempDir(), Format: "csv"})
+	require.ErrorIs(t, err, pinecone.ErrInvalidParams)
+
+	_, err = Namespace(context.Background(), newFakeSource(), Options{Dir: t.TempDir(), Compression: "lz4"})
+	require.ErrorIs(t, err, pinecone.ErrInvalidParams)
+}
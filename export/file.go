@@ -0,0 +1,217 @@
+package export
+
+import (
+	"bufio"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/nekomeowww/go-pinecone"
+)
+
+// Compression is the compression of the files of an export.
+type Compression string
+
+const (
+	CompressionNone Compression = ""
+	CompressionGzip Compression = "gzip"
+	CompressionZstd Compression = "zstd"
+)
+
+func (c Compression) validate() error {
+	switch c {
+	case CompressionNone, CompressionGzip, CompressionZstd:
+		return nil
+	default:
+		return fmt.Errorf("%w: unknown compression %q", pinecone.ErrInvalidParams, c)
+	}
+}
+
+// Extension returns the file extension of the compression, e.g. ".gz".
+func (c Compression) Extension() string {
+	switch c {
+	case CompressionGzip:
+		return ".gz"
+	case CompressionZstd:
+		return ".zst"
+	default:
+		return ""
+	}
+}
+
+// CompressionOf returns the compression of a file from its extension.
+func CompressionOf(path string) Compression {
+	switch {
+	case strings.HasSuffix(path, ".gz"):
+		return CompressionGzip
+	case strings.HasSuffix(path, ".zst"):
+		return CompressionZstd
+	default:
+		return CompressionNone
+	}
+}
+
+// Open opens a file, decompressing it according to its extension.
+func Open(path string) (io.ReadCloser, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch CompressionOf(path) {
+	case CompressionGzip:
+		r, err := gzip.NewReader(bufio.NewReader(file))
+		if err != nil {
+			_ = file.Close()
+			return nil, err
+		}
+		return &readCloser{Reader: r, closers: []io.Closer{r, file}}, nil
+	case CompressionZstd:
+		r, err := zstd.NewReader(bufio.NewReader(file))
+		if err != nil {
+			_ = file.Close()
+			return nil, err
+		}
+		return &readCloser{Reader: r, closers: []io.Closer{zstdCloser{r}, file}}, nil
+	default:
+		return file, nil
+	}
+}
+
+type readCloser struct {
+	io.Reader
+	closers []io.Closer
+}
+
+func (r *readCloser) Close() error {
+	var firstErr error
+	for _, c := range r.closers {
+		if err := c.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return firstErr
+}
+
+type zstdCloser struct {
+	*zstd.Decoder
+}
+
+func (z zstdCloser) Close() error {
+	z.Decoder.Close()
+	return nil
+}
+
+// outputFile is a file being written, compressed and checksummed.
+type outputFile struct {
+	name       string
+	file       *os.File
+	hash       hash.Hash
+	size       int64
+	compressor io.WriteCloser
+	buffer     *bufio.Writer
+}
+
+// createFile creates a file in dir. The extension of the compression is
+// appended to the name.
+func createFile(dir, name string, compression Compression) (*outputFile, error) {
+	name += compression.Extension()
+
+	file, err := os.Create(filepath.Join(dir, name))
+	if err != nil {
+		return nil, err
+	}
+
+	f := &outputFile{name: name, file: file, hash: sha256.New()}
+
+	var w io.Writer = writerFunc(f.writeFile)
+	switch compression {
+	case CompressionGzip:
+		f.compressor = gzip.NewWriter(w)
+		w = f.compressor
+	case CompressionZstd:
+		f.compressor, err = zstd.NewWriter(w)
+		if err != nil {
+			_ = file.Close()
+			return nil, err
+		}
+		w = f.compressor
+	}
+
+	f.buffer = bufio.NewWriterSize(w, 1<<16)
+
+	return f, nil
+}
+
+type writerFunc func(p []byte) (int, error)
+
+func (fn writerFunc) Write(p []byte) (int, error) {
+	return fn(p)
+}
+
+func (f *outputFile) writeFile(p []byte) (int, error) {
+	n, err := f.file.Write(p)
+	f.hash.Write(p[:n])
+	f.size += int64(n)
+
+	return n, err
+}
+
+func (f *outputFile) Write(p []byte) (int, error) {
+	return f.buffer.Write(p)
+}
+
+// close flushes and closes the file, and returns its manifest entry.
+func (f *outputFile) close() (ManifestFile, error) {
+	if err := f.buffer.Flush(); err != nil {
+		_ = f.file.Close()
+		return ManifestFile{}, err
+	}
+	if f.compressor != nil {
+		if err := f.compressor.Close(); err != nil {
+			_ = f.file.Close()
+			return ManifestFile{}, err
+		}
+	}
+	if err := f.file.Sync(); err != nil {
+		_ = f.file.Close()
+		return ManifestFile{}, err
+	}
+	if err := f.file.Close(); err != nil {
+		return ManifestFile{}, err
+	}
+
+	return ManifestFile{Name: f.name, Size: f.size, SHA256: hex.EncodeToString(f.hash.Sum(nil))}, nil
+}
+
+func (f *outputFile) abort() {
+	if f.compressor != nil {
+		_ = f.compressor.Close()
+	}
+
+	_ = f.file.Close()
+}
+
+func checksumFile(path string) (ManifestFile, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return ManifestFile{}, err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	size, err := io.Copy(hash, file)
+	if err != nil {
+		return ManifestFile{}, err
+	}
+
+	return ManifestFile{Name: filepath.Base(path), Size: size, SHA256: hex.EncodeToString(hash.Sum(nil))}, nil
+}
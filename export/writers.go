@@ -0,0 +1,264 @@
+package export
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+
+	"github.com/nekomeowww/go-pinecone"
+	"github.com/nekomeowww/go-pinecone/internal/npy"
+	"github.com/parquet-go/parquet-go"
+)
+
+// File names of the exported files, before the extension of the compression.
+const (
+	NDJSONFileName  = "vectors.ndjson"
+	ParquetFileName = "vectors.parquet"
+	NPYFileName     = "vectors.npy"
+	IDsFileName     = "ids.ndjson"
+)
+
+// parquetRowGroupSize is the number of rows of the row groups of exported
+// Parquet files, which are held in memory until they are complete.
+const parquetRowGroupSize = 10000
+
+// parquetRow is a row of exported Parquet files, the metadata as JSON.
+type parquetRow struct {
+	ID            string    `parquet:"id"`
+	Values        []float32 `parquet:"values"`
+	SparseIndices []int32   `parquet:"sparse_indices"`
+	SparseValues  []float32 `parquet:"sparse_values"`
+	Metadata      *string   `parquet:"metadata,optional"`
+}
+
+type vectorWriter interface {
+	write(vector *pinecone.Vector) error
+	close() ([]ManifestFile, error)
+	abort()
+}
+
+type ndjsonWriter struct {
+	file    *outputFile
+	encoder *json.Encoder
+}
+
+func newNDJSONWriter(dir string, compression Compression) (*ndjsonWriter, error) {
+	file, err := createFile(dir, NDJSONFileName, compression)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ndjsonWriter{file: file, encoder: json.NewEncoder(file)}, nil
+}
+
+func (w *ndjsonWriter) write(vector *pinecone.Vector) error {
+	return w.encoder.Encode(vector)
+}
+
+func (w *ndjsonWriter) close() ([]ManifestFile, error) {
+	file, err := w.file.close()
+	if err != nil {
+		return nil, err
+	}
+
+	return []ManifestFile{file}, nil
+}
+
+func (w *ndjsonWriter) abort() {
+	w.file.abort()
+}
+
+type parquetWriter struct {
+	file   *outputFile
+	writer *parquet.GenericWriter[parquetRow]
+}
+
+func newParquetWriter(dir string, compression Compression) (*parquetWriter, error) {
+	var codec parquet.WriterOption
+	switch compression {
+	case CompressionGzip:
+		codec = parquet.Compression(&parquet.Gzip)
+	case CompressionZstd:
+		codec = parquet.Compression(&parquet.Zstd)
+	default:
+		codec = parquet.Compression(&parquet.Uncompressed)
+	}
+
+	// pages are compressed by the Parquet writer, the file itself is not
+	file, err := createFile(dir, ParquetFileName, CompressionNone)
+	if err != nil {
+		return nil, err
+	}
+
+	return &parquetWriter{file: file, writer: parquet.NewGenericWriter[parquetRow](file, codec, parquet.MaxRowsPerRowGroup(parquetRowGroupSize))}, nil
+}
+
+func (w *parquetWriter) write(vector *pinecone.Vector) error {
+	row := parquetRow{ID: vector.ID, Values: vector.Values}
+	if vector.SparseValues != nil {
+		row.SparseIndices = vector.SparseValues.Indices
+		row.SparseValues = vector.SparseValues.Values
+	}
+
+	if vector.Metadata != nil {
+		data, err := json.Marshal(vector.Metadata)
+		if err != nil {
+			return err
+		}
+
+		metadata := string(data)
+		row.Metadata = &metadata
+	}
+
+	_, err := w.writer.Write([]parquetRow{row})
+
+	return err
+}
+
+func (w *parquetWriter) close() ([]ManifestFile, error) {
+	if err := w.writer.Close(); err != nil {
+		w.file.abort()
+		return nil, err
+	}
+
+	file, err := w.file.close()
+	if err != nil {
+		return nil, err
+	}
+
+	return []ManifestFile{file}, nil
+}
+
+func (w *parquetWriter) abort() {
+	w.file.abort()
+}
+
+// npyWriter writes the values to a temporary file first, since the header
+// of a .npy file records the number of rows, which is only known once every
+// vector has been written.
+type npyWriter struct {
+	dir         string
+	compression Compression
+	dimension   int
+	rows        int
+
+	values *os.File
+	buffer *bufio.Writer
+	ids    *outputFile
+}
+
+func newNPYWriter(dir string, compression Compression, dimension int) (*npyWriter, error) {
+	values, err := os.CreateTemp(dir, "."+NPYFileName+".*.tmp")
+	if err != nil {
+		return nil, err
+	}
+
+	ids, err := createFile(dir, IDsFileName, compression)
+	if err != nil {
+		_ = values.Close()
+		_ = os.Remove(values.Name())
+		return nil, err
+	}
+
+	return &npyWriter{
+		dir:         dir,
+		compression: compression,
+		dimension:   dimension,
+		values:      values,
+		buffer:      bufio.NewWriterSize(values, 1<<16),
+		ids:         ids,
+	}, nil
+}
+
+func (w *npyWriter) write(vector *pinecone.Vector) error {
+	if w.dimension == 0 {
+		w.dimension = len(vector.Values)
+	}
+	if len(vector.Values) != w.dimension {
+		return fmt.Errorf("%w: vector has dimension %d, expected %d", pinecone.ErrInvalidParams, len(vector.Values), w.dimension)
+	}
+
+	var b [4]byte
+	for _, v := range vector.Values {
+		binary.LittleEndian.PutUint32(b[:], math.Float32bits(v))
+		if _, err := w.buffer.Write(b[:]); err != nil {
+			return err
+		}
+	}
+
+	// IDs are JSON strings, so that a newline in an ID cannot break the
+	// alignment of the lines with the rows
+	id, err := json.Marshal(vector.ID)
+	if err != nil {
+		return err
+	}
+	if _, err := w.ids.Write(append(id, '\n')); err != nil {
+		return err
+	}
+
+	w.rows++
+
+	return nil
+}
+
+func (w *npyWriter) close() ([]ManifestFile, error) {
+	defer os.Remove(w.values.Name())
+
+	if err := w.buffer.Flush(); err != nil {
+		w.abort()
+		return nil, err
+	}
+	if _, err := w.values.Seek(0, io.SeekStart); err != nil {
+		w.abort()
+		return nil, err
+	}
+
+	matrix, err := createFile(w.dir, NPYFileName, w.compression)
+	if err != nil {
+		w.abort()
+		return nil, err
+	}
+
+	if err := npy.WriteHeader(matrix, w.rows, w.dimension); err != nil {
+		matrix.abort()
+		w.abort()
+		return nil, err
+	}
+	if _, err := io.Copy(matrix, w.values); err != nil {
+		matrix.abort()
+		w.abort()
+		return nil, err
+	}
+
+	_ = w.values.Close()
+
+	matrixFile, err := matrix.close()
+	if err != nil {
+		w.ids.abort()
+		return nil, err
+	}
+
+	idsFile, err := w.ids.close()
+	if err != nil {
+		return nil, err
+	}
+
+	return []ManifestFile{matrixFile, idsFile}, nil
+}
+
+func (w *npyWriter) abort() {
+	_ = w.values.Close()
+	_ = os.Remove(w.values.Name())
+	w.ids.abort()
+}
+
+// Compile-time checks of the writers.
+var (
+	_ vectorWriter = (*ndjsonWriter)(nil)
+	_ vectorWriter = (*parquetWriter)(nil)
+	_ vectorWriter = (*npyWriter)(nil)
+)
@@ -1,7 +1,10 @@
 package pinecone
 
 import (
+	"context"
 	"net/url"
+	"strconv"
+	"time"
 )
 
 // buildFetchVectorPathParams builds the fetch vector path parameters.
@@ -17,3 +20,57 @@ func buildFetchVectorPathParams(params FetchVectorsParams) string {
 
 	return pathParams.Encode()
 }
+
+// buildListVectorIDsPathParams builds the list vector IDs path parameters.
+// Example: limit=100&namespace=foo&prefix=doc1%23
+func buildListVectorIDsPathParams(params ListVectorIDsParams) string {
+	pathParams := make(url.Values)
+	if params.Namespace != "" {
+		pathParams.Add("namespace", params.Namespace)
+	}
+	if params.Prefix != "" {
+		pathParams.Add("prefix", params.Prefix)
+	}
+	if params.Limit > 0 {
+		pathParams.Add("limit", strconv.Itoa(params.Limit))
+	}
+	if params.PaginationToken != "" {
+		pathParams.Add("paginationToken", params.PaginationToken)
+	}
+
+	return pathParams.Encode()
+}
+
+// buildPaginationPathParams builds the path parameters of a paginated list.
+// Example: limit=10&paginationToken=foo
+func buildPaginationPathParams(limit int, paginationToken string) string {
+	pathParams := make(url.Values)
+	if limit > 0 {
+		pathParams.Add("limit", strconv.Itoa(limit))
+	}
+	if paginationToken != "" {
+		pathParams.Add("paginationToken", paginationToken)
+	}
+
+	return pathParams.Encode()
+}
+
+// poll calls fn immediately and then every interval, until it reports done,
+// fails, or the context is done.
+func poll(ctx context.Context, interval time.Duration, fn func() (done bool, err error)) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		done, err := fn()
+		if err != nil || done {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package pinecone
+
+import (
+	"context"
+	"fmt"
+)
+
+// HybridScale applies the convex combination weighting of hybrid search:
+// the dense values are scaled by alpha and the sparse values by (1 - alpha).
+// An alpha of 1 is a pure dense search, an alpha of 0 a pure sparse search.
+// The given vectors are left untouched.
+func HybridScale(dense []float32, sparse *SparseVector, alpha float32) ([]float32, *SparseVector, error) {
+	if alpha < 0 || alpha > 1 {
+		return nil, nil, fmt.Errorf("%w: alpha must be between 0 and 1", ErrInvalidParams)
+	}
+
+	scaledDense := make([]float32, len(dense))
+	for i, v := range dense {
+		scaledDense[i] = v * alpha
+	}
+
+	if sparse == nil {
+		return scaledDense, nil, nil
+	}
+	if len(sparse.Values) != len(sparse.Indices) {
+		return nil, nil, fmt.Errorf("%w: sparse vector values and indices must be the same length", ErrInvalidParams)
+	}
+
+	scaledSparse := &SparseVector{
+		Indices: make([]int32, len(sparse.Indices)),
+		Values:  make([]float32, len(sparse.Values)),
+	}
+	copy(scaledSparse.Indices, sparse.Indices)
+	for i, v := range sparse.Values {
+		scaledSparse.Values[i] = v * (1 - alpha)
+	}
+
+	return scaledDense, scaledSparse, nil
+}
+
+// HybridQuery performs a hybrid search query. The dense values are scaled by
+// alpha and the sparse values by (1 - alpha) before the query is sent, the
+// Vector and SparseVector fields of params are overridden.
+//
+// Hybrid search is only supported by indexes with the dotproduct metric,
+// which is checked with DescribeIndex.
+func (ic *IndexClient) HybridQuery(ctx context.Context, dense []float32, sparse *SparseVector, alpha float32, params QueryParams) (*QueryResponse, error) {
+	if len(dense) == 0 {
+		return nil, fmt.Errorf("%w: dense vector is required", ErrInvalidParams)
+	}
+
+	scaledDense, scaledSparse, err := HybridScale(dense, sparse, alpha)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ic.validateHybridIndex(ctx); err != nil {
+		return nil, err
+	}
+
+	params.ID = ""
+	params.Vector = scaledDense
+	params.SparseVector = scaledSparse
+
+	return ic.Query(ctx, params)
+}
+
+// UpsertHybridVectors performs an upsert vectors request against a hybrid
+// index. Every vector must carry both dense values and sparse values, and
+// the index must use the dotproduct metric, which is checked with
+// DescribeIndex.
+func (ic *IndexClient) UpsertHybridVectors(ctx context.Context, params UpsertVectorsParams) (*UpsertVectorsResponse, error) {
+	if err := validateUpsertHybridVectorsParams(params); err != nil {
+		return nil, err
+	}
+
+	if err := ic.validateHybridIndex(ctx); err != nil {
+		return nil, err
+	}
+
+	return ic.UpsertVectors(ctx, params)
+}
+
+// validateHybridIndex checks that the index uses the dotproduct metric.
+func (ic *IndexClient) validateHybridIndex(ctx context.Context) error {
+	index, err := ic.DescribeIndex(ctx)
+	if err != nil {
+		return err
+	}
+
+	if CreateIndexMetric(index.Database.Metric) != CreateIndexMetricDotProduct {
+		return fmt.Errorf("%w: hybrid search requires the %s metric, index %q uses %s", ErrInvalidParams, CreateIndexMetricDotProduct, index.Database.Name, index.Database.Metric)
+	}
+
+	return nil
+}
@@ -0,0 +1,113 @@
+package pinecone
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newHybridTestServer(t *testing.T, metric CreateIndexMetric, describeCalls *int32, lastQuery *QueryParams) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/databases/hybrid", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(describeCalls, 1)
+		_ = json.NewEncoder(w).Encode(DescribeIndexResponse{
+			Database: Database{Name: "hybrid", Metric: string(metric), Dimension: 2},
+		})
+	})
+	mux.HandleFunc("/query", func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(lastQuery))
+		_ = json.NewEncoder(w).Encode(QueryResponse{Matches: []*QueryVector{}})
+	})
+	mux.HandleFunc("/vectors/upsert", func(w http.ResponseWriter, r *http.Request) {
+		var params UpsertVectorsParams
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&params))
+		_ = json.NewEncoder(w).Encode(UpsertVectorsResponse{UpsertedCount: len(params.Vectors)})
+	})
+
+	return httptest.NewServer(mux)
+}
+
+func TestHybridScale(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	sparse := &SparseVector{Indices: []int32{1, 7}, Values: []float32{1, 2}}
+
+	dense, scaledSparse, err := HybridScale([]float32{1, 2}, sparse, 0.75)
+	require.NoError(err)
+	assert.Equal([]float32{0.75, 1.5}, dense)
+	assert.Equal([]int32{1, 7}, scaledSparse.Indices)
+	assert.Equal([]float32{0.25, 0.5}, scaledSparse.Values)
+	assert.Equal([]float32{1, 2}, sparse.Values)
+
+	_, _, err = HybridScale([]float32{1, 2}, sparse, 1.5)
+	assert.ErrorIs(err, ErrInvalidParams)
+}
+
+func TestHybridQuery(t *testing.T) {
+	t.Run("DotProduct", func(t *testing.T) {
+		assert := assert.New(t)
+		require := require.New(t)
+
+		var describeCalls int32
+		var lastQuery QueryParams
+		server := newHybridTestServer(t, CreateIndexMetricDotProduct, &describeCalls, &lastQuery)
+		defer server.Close()
+
+		ic := newTestIndexClient(server.URL, WithIndexName("hybrid"))
+		for i := 0; i < 2; i++ {
+			_, err := ic.HybridQuery(context.Background(), []float32{1, 2}, &SparseVector{Indices: []int32{3}, Values: []float32{4}}, 0.5, QueryParams{TopK: 10})
+			require.NoError(err)
+		}
+
+		assert.Equal(int32(1), describeCalls)
+		assert.Equal([]float32{0.5, 1}, lastQuery.Vector)
+		require.NotNil(lastQuery.SparseVector)
+		assert.Equal([]float32{2}, lastQuery.SparseVector.Values)
+	})
+
+	t.Run("Cosine", func(t *testing.T) {
+		var describeCalls int32
+		var lastQuery QueryParams
+		server := newHybridTestServer(t, CreateIndexMetricCosine, &describeCalls, &lastQuery)
+		defer server.Close()
+
+		ic := newTestIndexClient(server.URL, WithIndexName("hybrid"))
+		_, err := ic.HybridQuery(context.Background(), []float32{1, 2}, nil, 0.5, QueryParams{TopK: 10})
+		require.ErrorIs(t, err, ErrInvalidParams)
+	})
+}
+
+func TestUpsertHybridVectors(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	var describeCalls int32
+	var lastQuery QueryParams
+	server := newHybridTestServer(t, CreateIndexMetricDotProduct, &describeCalls, &lastQuery)
+	defer server.Close()
+
+	ic := newTestIndexClient(server.URL, WithIndexName("hybrid"))
+
+	resp, err := ic.UpsertHybridVectors(context.Background(), UpsertVectorsParams{
+		Vectors: []*Vector{
+			{ID: "a", Values: []float32{1, 2}, SparseValues: &SparseVector{Indices: []int32{1}, Values: []float32{1}}},
+		},
+	})
+	require.NoError(err)
+	assert.Equal(1, resp.UpsertedCount)
+
+	_, err = ic.UpsertHybridVectors(context.Background(), UpsertVectorsParams{
+		Vectors: []*Vector{
+			{ID: "dense-only", Values: []float32{1, 2}},
+		},
+	})
+	require.ErrorIs(err, ErrInvalidParams)
+	assert.Contains(err.Error(), `"dense-only"`)
+}
@@ -0,0 +1,499 @@
+// Package importer loads vectors from files into an index: NDJSON, CSV,
+// Parquet, and NumPy .npy matrices with a sidecar file of IDs, as written by
+// the export package or by other tools.
+package importer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/nekomeowww/go-pinecone"
+	"github.com/nekomeowww/go-pinecone/export"
+)
+
+const (
+	defaultBatchSize   = 100
+	defaultConcurrency = 4
+)
+
+// Format is the file format of a source.
+type Format string
+
+const (
+	// FormatNDJSON reads one JSON-encoded pinecone.Vector per line.
+	FormatNDJSON Format = "ndjson"
+	// FormatCSV reads one vector per row, with the values as a JSON array.
+	FormatCSV Format = "csv"
+	// FormatParquet reads the columns id, values, sparse_indices,
+	// sparse_values and metadata, the latter as JSON. Only id is required.
+	// The lists may be repeated columns or nested lists, as written by
+	// pyarrow, of floats or doubles and of 32 or 64-bit integers.
+	FormatParquet Format = "parquet"
+	// FormatNPY reads the rows of a float32 .npy matrix, and their IDs as JSON
+	// strings one per line from a sidecar file.
+	FormatNPY Format = "npy"
+)
+
+// Source represents a file to import.
+type Source struct {
+	// Required. The path of the file. NDJSON, CSV and NPY files may be
+	// compressed with gzip or zstd, detected from the .gz or .zst extension.
+	Path string
+	// The format of the file. Detected from the extension when empty.
+	Format Format
+	// The path of the file of IDs of a .npy matrix. Defaults to ids.ndjson in
+	// the directory of the matrix, with the same compression, as written by
+	// the export package.
+	IDsPath string
+	// The columns of a CSV file.
+	CSV CSVOptions
+}
+
+// CSVOptions represents the columns of a CSV file. The first row of the file
+// must hold the names of the columns.
+type CSVOptions struct {
+	// The column of the IDs. Defaults to "id".
+	IDColumn string
+	// The column of the values, as a JSON array. Defaults to "values".
+	ValuesColumn string
+	// The column of the metadata, as a JSON object. Defaults to "metadata",
+	// and is optional.
+	MetadataColumn string
+	// Columns copied into the metadata as strings, under their own name.
+	MetadataColumns []string
+	// The field delimiter. Defaults to a comma.
+	Comma rune
+}
+
+func (o CSVOptions) idColumn() string {
+	if o.IDColumn == "" {
+		return "id"
+	}
+	return o.IDColumn
+}
+
+func (o CSVOptions) valuesColumn() string {
+	if o.ValuesColumn == "" {
+		return "values"
+	}
+	return o.ValuesColumn
+}
+
+func (o CSVOptions) metadataColumn() string {
+	if o.MetadataColumn == "" {
+		return "metadata"
+	}
+	return o.MetadataColumn
+}
+
+// Options represents the options of an import.
+type Options struct {
+	// The namespace to upsert into.
+	Namespace string
+	// The number of vectors per upsert request. Defaults to 100.
+	BatchSize int
+	// The number of upsert requests sent in parallel. Defaults to 4.
+	Concurrency int
+	// The path of the checkpoint file. When set, progress is saved after
+	// every batch, and an import finding a checkpoint of the same source
+	// resumes where it stopped. The checkpoint is removed once the import
+	// completes.
+	CheckpointPath string
+	// The path of the error file. When set, every rejected record is appended
+	// to it as a JSON line with its position, ID and error.
+	ErrorPath string
+	// Called with the progress of the import after every batch.
+	OnProgress func(progress Progress)
+}
+
+// Progress represents the progress of an import, including the records
+// handled by the runs it resumed.
+type Progress struct {
+	// The number of records handled, which is also the position the import
+	// resumes from.
+	Records int64 `json:"records"`
+	// The number of vectors upserted.
+	Upserted int64 `json:"upserted"`
+	// The number of records rejected.
+	Rejected int64 `json:"rejected"`
+}
+
+// RejectedRecord is a line of the error file.
+type RejectedRecord struct {
+	// The zero-based position of the record in the source.
+	Record int64  `json:"record"`
+	ID     string `json:"id,omitempty"`
+	Error  string `json:"error"`
+}
+
+type checkpoint struct {
+	Path     string   `json:"path"`
+	Progress Progress `json:"progress"`
+}
+
+type batch struct {
+	start     int64
+	end       int64
+	vectors   []*pinecone.Vector
+	positions []int64
+}
+
+// Import streams the records of a source into a namespace through batched
+// concurrent UpsertVectors requests. Records without an ID, with values not
+// matching the dimension of the index, with NaN or infinite values, or
+// rejected by the validation of UpsertVectors are skipped and reported to
+// the error file, any other failure stops the import.
+//
+// Resumed imports continue after the last record of which every previous
+// record was handled, so a few records may be upserted, or reported as
+// rejected, twice.
+func Import(ctx context.Context, ic *pinecone.IndexClient, source Source, opts Options) (*Progress, error) {
+	if source.Path == "" {
+		return nil, fmt.Errorf("%w: path is required", pinecone.ErrInvalidParams)
+	}
+	if opts.BatchSize < 0 || opts.Concurrency < 0 {
+		return nil, fmt.Errorf("%w: batch size and concurrency must not be negative", pinecone.ErrInvalidParams)
+	}
+	if opts.BatchSize == 0 {
+		opts.BatchSize = defaultBatchSize
+	}
+	if opts.Concurrency == 0 {
+		opts.Concurrency = defaultConcurrency
+	}
+
+	format := source.Format
+	if format == "" {
+		var err error
+		format, err = detectFormat(source.Path)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	spec, err := ic.Spec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	im := &importer{
+		ic:             ic,
+		opts:           opts,
+		dimension:      spec.Dimension,
+		sourcePath:     source.Path,
+		completed:      make(map[int64]batch),
+		sem:            make(chan struct{}, opts.Concurrency),
+		checkpointPath: opts.CheckpointPath,
+	}
+
+	if err := im.loadCheckpoint(); err != nil {
+		return nil, err
+	}
+	if opts.ErrorPath != "" {
+		im.errorFile, err = os.OpenFile(opts.ErrorPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, err
+		}
+		defer im.errorFile.Close()
+	}
+
+	ctx, im.cancel = context.WithCancel(ctx)
+	defer im.cancel()
+
+	switch format {
+	case FormatNDJSON:
+		err = readNDJSON(source.Path, im.read(ctx))
+	case FormatCSV:
+		err = readCSV(source.Path, source.CSV, im.read(ctx))
+	case FormatParquet:
+		err = readParquet(source.Path, im.read(ctx))
+	case FormatNPY:
+		idsPath := source.IDsPath
+		if idsPath == "" {
+			idsPath = filepath.Join(filepath.Dir(source.Path), export.IDsFileName+export.CompressionOf(source.Path).Extension())
+		}
+		err = readNPY(source.Path, idsPath, im.read(ctx))
+	default:
+		err = fmt.Errorf("%w: unknown format %q", pinecone.ErrInvalidParams, format)
+	}
+	if err == nil {
+		err = im.dispatch(ctx)
+	}
+
+	im.wg.Wait()
+
+	if im.err != nil {
+		return nil, im.err
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if im.checkpointPath != "" {
+		if err := os.Remove(im.checkpointPath); err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+
+	progress := im.progress
+
+	return &progress, nil
+}
+
+type importer struct {
+	ic         *pinecone.IndexClient
+	opts       Options
+	dimension  int
+	sourcePath string
+
+	// the position of the next record to read, and the records to skip
+	position int64
+	skip     int64
+	current  batch
+
+	sem    chan struct{}
+	wg     sync.WaitGroup
+	cancel context.CancelFunc
+
+	mu             sync.Mutex
+	err            error
+	progress       Progress
+	completed      map[int64]batch
+	checkpointPath string
+	errorFile      *os.File
+}
+
+func (im *importer) loadCheckpoint() error {
+	if im.checkpointPath == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(im.checkpointPath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var saved checkpoint
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return fmt.Errorf("invalid checkpoint: %w", err)
+	}
+	if saved.Path != im.sourcePath {
+		return fmt.Errorf("%w: checkpoint %q belongs to %q", pinecone.ErrInvalidParams, im.checkpointPath, saved.Path)
+	}
+
+	im.progress = saved.Progress
+	im.skip = saved.Progress.Records
+
+	return nil
+}
+
+// read returns the readFunc validating and batching the records.
+func (im *importer) read(ctx context.Context) readFunc {
+	return func(id string, vector *pinecone.Vector, parseErr error) error {
+		position := im.position
+		im.position++
+		if position < im.skip {
+			return nil
+		}
+
+		if im.current.end == im.current.start {
+			im.current.start = position
+		}
+		im.current.end = position + 1
+
+		if err := parseErr; err != nil {
+			im.reject(position, id, err)
+		} else if err := im.validate(vector); err != nil {
+			im.reject(position, id, err)
+		} else {
+			im.current.vectors = append(im.current.vectors, vector)
+			im.current.positions = append(im.current.positions, position)
+		}
+
+		if len(im.current.vectors) >= im.opts.BatchSize {
+			return im.dispatch(ctx)
+		}
+
+		return nil
+	}
+}
+
+func (im *importer) validate(vector *pinecone.Vector) error {
+	if vector.ID == "" {
+		return errors.New("id is required")
+	}
+	if len(vector.Values) == 0 && vector.SparseValues == nil {
+		return errors.New("values or sparse values are required")
+	}
+	if im.dimension > 0 && len(vector.Values) > 0 && len(vector.Values) != im.dimension {
+		return fmt.Errorf("vector has dimension %d, index has dimension %d", len(vector.Values), im.dimension)
+	}
+	for _, v := range vector.Values {
+		if math.IsNaN(float64(v)) || math.IsInf(float64(v), 0) {
+			return errors.New("vector has non-finite values")
+		}
+	}
+	if vector.SparseValues != nil {
+		for _, v := range vector.SparseValues.Values {
+			if math.IsNaN(float64(v)) || math.IsInf(float64(v), 0) {
+				return errors.New("vector has non-finite sparse values")
+			}
+		}
+	}
+
+	return nil
+}
+
+// dispatch upserts the current batch in the background, waiting for a free
+// slot first.
+func (im *importer) dispatch(ctx context.Context) error {
+	b := im.current
+	im.current = batch{start: im.position, end: im.position}
+	if b.end == b.start {
+		return nil
+	}
+
+	select {
+	case im.sem <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	im.wg.Add(1)
+	go func() {
+		defer im.wg.Done()
+		defer func() { <-im.sem }()
+
+		if err := im.upsert(ctx, b); err != nil {
+			im.fail(err)
+			return
+		}
+
+		im.complete(b)
+	}()
+
+	return nil
+}
+
+// upsert upserts a batch. When the batch is rejected by validation, its
+// vectors are upserted one by one to single out the invalid ones.
+func (im *importer) upsert(ctx context.Context, b batch) error {
+	if len(b.vectors) == 0 {
+		return nil
+	}
+
+	_, err := im.ic.UpsertVectors(ctx, pinecone.UpsertVectorsParams{Vectors: b.vectors, Namespace: im.opts.Namespace})
+	if err == nil {
+		im.mu.Lock()
+		im.progress.Upserted += int64(len(b.vectors))
+		im.mu.Unlock()
+		return nil
+	}
+	if !errors.Is(err, pinecone.ErrInvalidParams) {
+		return err
+	}
+
+	for i, vector := range b.vectors {
+		_, err := im.ic.UpsertVectors(ctx, pinecone.UpsertVectorsParams{Vectors: []*pinecone.Vector{vector}, Namespace: im.opts.Namespace})
+		if errors.Is(err, pinecone.ErrInvalidParams) {
+			im.reject(b.positions[i], vector.ID, err)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		im.mu.Lock()
+		im.progress.Upserted++
+		im.mu.Unlock()
+	}
+
+	return nil
+}
+
+func (im *importer) reject(position int64, id string, err error) {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	im.rejectLocked(position, id, err)
+}
+
+func (im *importer) rejectLocked(position int64, id string, err error) {
+	im.progress.Rejected++
+	if im.errorFile == nil {
+		return
+	}
+
+	line, _ := json.Marshal(RejectedRecord{Record: position, ID: id, Error: err.Error()})
+	if _, writeErr := im.errorFile.Write(append(line, '\n')); writeErr != nil && im.err == nil {
+		im.err = fmt.Errorf("failed to write the error file: %w", writeErr)
+		im.cancel()
+	}
+}
+
+func (im *importer) fail(err error) {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	if im.err == nil {
+		im.err = err
+		im.cancel()
+	}
+}
+
+// complete marks a batch as done, and advances the checkpoint over every
+// batch completed without gaps since the last one.
+func (im *importer) complete(b batch) {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	im.completed[b.start] = b
+	advanced := false
+	for {
+		next, ok := im.completed[im.progress.Records]
+		if !ok {
+			break
+		}
+
+		delete(im.completed, next.start)
+		im.progress.Records = next.end
+		advanced = true
+	}
+	if !advanced {
+		return
+	}
+
+	if im.checkpointPath != "" && im.err == nil {
+		if err := im.saveCheckpoint(); err != nil {
+			im.err = fmt.Errorf("failed to save the checkpoint: %w", err)
+			im.cancel()
+			return
+		}
+	}
+
+	if im.opts.OnProgress != nil {
+		im.opts.OnProgress(im.progress)
+	}
+}
+
+func (im *importer) saveCheckpoint() error {
+	data, err := json.Marshal(checkpoint{Path: im.sourcePath, Progress: im.progress})
+	if err != nil {
+		return err
+	}
+
+	tmp := im.checkpointPath + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, im.checkpointPath)
+}
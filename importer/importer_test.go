@@ -0,0 +1,298 @@
+package importer
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/nekomeowww/go-pinecone"
+	"github.com/nekomeowww/go-pinecone/export"
+	"github.com/parquet-go/parquet-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeIndex struct {
+	mu       sync.Mutex
+	vectors  map[string]*pinecone.Vector
+	requests atomic.Int32
+	// upsert requests fail with 500 once this many succeeded, when positive
+	failAfter atomic.Int32
+}
+
+func newFakeIndex(t *testing.T) (*fakeIndex, *pinecone.IndexClient) {
+	index := &fakeIndex{vectors: make(map[string]*pinecone.Vector)}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/describe_index_stats", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(pinecone.DescribeIndexStatsResponse{Dimensions: 2})
+	})
+	mux.HandleFunc("/vectors/upsert", func(w http.ResponseWriter, r *http.Request) {
+		if failAfter := index.failAfter.Load(); failAfter > 0 && index.requests.Load() >= failAfter {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		var params pinecone.UpsertVectorsParams
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&params))
+
+		index.mu.Lock()
+		for _, v := range params.Vectors {
+			index.vectors[v.ID] = v
+		}
+		index.mu.Unlock()
+		index.requests.Add(1)
+
+		_ = json.NewEncoder(w).Encode(pinecone.UpsertVectorsResponse{UpsertedCount: len(params.Vectors)})
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	ic, err := pinecone.NewIndexClient(pinecone.WithAPIKey("key"), pinecone.WithBaseURL(server.URL), pinecone.WithControllerBaseURL(server.URL))
+	require.NoError(t, err)
+
+	return index, ic
+}
+
+func (f *fakeIndex) ids() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	ids := make([]string, 0, len(f.vectors))
+	for id := range f.vectors {
+		ids = append(ids, id)
+	}
+
+	return ids
+}
+
+func readRejected(t *testing.T, path string) []RejectedRecord {
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	var records []RejectedRecord
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var record RejectedRecord
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+		records = append(records, record)
+	}
+
+	return records
+}
+
+func TestImport_NDJSON(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	index, ic := newFakeIndex(t)
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "vectors.jsonl")
+	require.NoError(os.WriteFile(path, []byte(strings.Join([]string{
+		`{"id":"a","values":[1,2]}`,
+		`not json`,
+		`{"id":"b","values":[1,2,3]}`,
+		``,
+		`{"id":"c","values":[3,4],"metadata":{"genre":"drama"}}`,
+		`{"values":[3,4]}`,
+	}, "\n")), 0o644))
+
+	var updates []Progress
+	progress, err := Import(context.Background(), ic, Source{Path: path}, Options{
+		Namespace:  "ns",
+		BatchSize:  1,
+		ErrorPath:  filepath.Join(dir, "errors.jsonl"),
+		OnProgress: func(p Progress) { updates = append(updates, p) },
+	})
+	require.NoError(err)
+	assert.Equal(&Progress{Records: 5, Upserted: 2, Rejected: 3}, progress)
+	assert.ElementsMatch([]string{"a", "c"}, index.ids())
+	assert.NotEmpty(updates)
+	assert.Equal(*progress, updates[len(updates)-1])
+
+	rejected := readRejected(t, filepath.Join(dir, "errors.jsonl"))
+	require.Len(rejected, 3)
+	assert.Equal(int64(1), rejected[0].Record)
+	assert.Equal(RejectedRecord{Record: 2, ID: "b", Error: "vector has dimension 3, index has dimension 2"}, rejected[1])
+	assert.Equal(int64(4), rejected[2].Record)
+}
+
+func TestImport_CSV(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	index, ic := newFakeIndex(t)
+
+	path := filepath.Join(t.TempDir(), "vectors.csv")
+	require.NoError(os.WriteFile(path, []byte("key;embedding;genre;extra\n"+
+		"a;[1,2];drama;{}\n"+
+		"b;[3,4];comedy;{}\n"), 0o644))
+
+	progress, err := Import(context.Background(), ic, Source{
+		Path: path,
+		CSV: CSVOptions{
+			IDColumn:        "key",
+			ValuesColumn:    "embedding",
+			MetadataColumns: []string{"genre"},
+			Comma:           ';',
+		},
+	}, Options{})
+	require.NoError(err)
+	assert.Equal(int64(2), progress.Upserted)
+
+	index.mu.Lock()
+	defer index.mu.Unlock()
+	assert.Equal(&pinecone.Vector{ID: "b", Values: []float32{3, 4}, Metadata: map[string]any{"genre": "comedy"}}, index.vectors["b"])
+}
+
+func TestImport_ParquetLists(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	index, ic := newFakeIndex(t)
+
+	// pyarrow writes lists as nested groups, and floats as doubles
+	type row struct {
+		ID            string    `parquet:"id"`
+		Values        []float64 `parquet:"values,list"`
+		SparseIndices []int64   `parquet:"sparse_indices,list"`
+		SparseValues  []float64 `parquet:"sparse_values,list"`
+	}
+
+	path := filepath.Join(t.TempDir(), "vectors.parquet")
+	require.NoError(parquet.WriteFile(path, []row{
+		{ID: "a", Values: []float64{1, 2}, SparseIndices: []int64{3}, SparseValues: []float64{0.5}},
+		{ID: "b", Values: []float64{3, 4}},
+	}))
+
+	progress, err := Import(context.Background(), ic, Source{Path: path}, Options{})
+	require.NoError(err)
+	assert.Equal(int64(2), progress.Upserted)
+
+	index.mu.Lock()
+	defer index.mu.Unlock()
+	assert.Equal(&pinecone.Vector{ID: "a", Values: []float32{1, 2}, SparseValues: &pinecone.SparseVector{Indices: []int32{3}, Values: []float32{0.5}}}, index.vectors["a"])
+	assert.Equal(&pinecone.Vector{ID: "b", Values: []float32{3, 4}}, index.vectors["b"])
+}
+
+type exportSource []*pinecone.Vector
+
+func (s exportSource) Spec(ctx context.Context) (*pinecone.IndexSpec, error) {
+	return &pinecone.IndexSpec{Dimension: 2}, nil
+}
+
+func (s exportSource) Scan(ctx context.Context, params pinecone.ScanParams, fn func(vector *pinecone.Vector) error) error {
+	for _, v := range s {
+		if err := fn(v); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func TestImport_Exported(t *testing.T) {
+	source := exportSource{
+		{ID: "a", Values: []float32{1, 2}, Metadata: map[string]any{"genre": "drama"}},
+		{ID: "b", Values: []float32{3, 4}, SparseValues: &pinecone.SparseVector{Indices: []int32{1}, Values: []float32{0.5}}},
+	}
+
+	for _, tc := range []struct {
+		format      export.Format
+		compression export.Compression
+		file        string
+	}{
+		{export.FormatNDJSON, export.CompressionZstd, export.NDJSONFileName + ".zst"},
+		{export.FormatParquet, export.CompressionGzip, export.ParquetFileName},
+		{export.FormatNPY, export.CompressionGzip, export.NPYFileName + ".gz"},
+	} {
+		t.Run(string(tc.format), func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			dir := t.TempDir()
+			_, err := export.Namespace(context.Background(), source, export.Options{Dir: dir, Format: tc.format, Compression: tc.compression})
+			require.NoError(err)
+
+			index, ic := newFakeIndex(t)
+			progress, err := Import(context.Background(), ic, Source{Path: filepath.Join(dir, tc.file)}, Options{})
+			require.NoError(err)
+			assert.Equal(&Progress{Records: 2, Upserted: 2}, progress)
+
+			index.mu.Lock()
+			defer index.mu.Unlock()
+			assert.Equal([]float32{3, 4}, index.vectors["b"].Values)
+			if tc.format != export.FormatNPY {
+				assert.Equal(source[0], index.vectors["a"])
+				assert.Equal(source[1], index.vectors["b"])
+			}
+		})
+	}
+}
+
+func TestImport_ResumesFromCheckpoint(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	index, ic := newFakeIndex(t)
+	index.failAfter.Store(3)
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "vectors.ndjson")
+	lines := make([]string, 0, 10)
+	for i := 0; i < 10; i++ {
+		lines = append(lines, `{"id":"`+string(rune('a'+i))+`","values":[1,2]}`)
+	}
+	require.NoError(os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o644))
+
+	checkpointPath := filepath.Join(dir, "checkpoint.json")
+	opts := Options{BatchSize: 2, Concurrency: 1, CheckpointPath: checkpointPath}
+
+	_, err := Import(context.Background(), ic, Source{Path: path}, opts)
+	require.ErrorIs(err, pinecone.ErrRequestFailed)
+	assert.Len(index.ids(), 6)
+
+	data, err := os.ReadFile(checkpointPath)
+	require.NoError(err)
+
+	var saved checkpoint
+	require.NoError(json.Unmarshal(data, &saved))
+	assert.Equal(checkpoint{Path: path, Progress: Progress{Records: 6, Upserted: 6}}, saved)
+
+	index.failAfter.Store(0)
+	index.requests.Store(0)
+
+	progress, err := Import(context.Background(), ic, Source{Path: path}, opts)
+	require.NoError(err)
+	assert.Equal(&Progress{Records: 10, Upserted: 10}, progress)
+	assert.Len(index.ids(), 10)
+	assert.Equal(int32(2), index.requests.Load(), "only the remaining records are upserted")
+
+	_, err = os.Stat(checkpointPath)
+	assert.True(os.IsNotExist(err))
+
+	require.NoError(os.WriteFile(checkpointPath, data, 0o644))
+	_, err = Import(context.Background(), ic, Source{Path: filepath.Join(dir, "other.ndjson")}, Options{CheckpointPath: checkpointPath})
+	assert.ErrorIs(err, pinecone.ErrInvalidParams)
+}
+
+func TestImport_InvalidSource(t *testing.T) {
+	_, ic := newFakeIndex(t)
+
+	_, err := Import(context.Background(), ic, Source{}, Options{})
+	assert.ErrorIs(t, err, pinecone.ErrInvalidParams)
+
+	_, err = Import(context.Background(), ic, Source{Path: "vectors.txt"}, Options{})
+	assert.ErrorIs(t, err, pinecone.ErrInvalidParams)
+}
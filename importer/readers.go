@@ -0,0 +1,365 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/nekomeowww/go-pinecone"
+	"github.com/nekomeowww/go-pinecone/export"
+	"github.com/nekomeowww/go-pinecone/internal/npy"
+	"github.com/parquet-go/parquet-go"
+)
+
+// readFunc is called with every record of a file in order. Records that
+// cannot be parsed are passed with a nil vector and the parse error, and
+// with the ID when it is known.
+type readFunc func(id string, vector *pinecone.Vector, parseErr error) error
+
+func detectFormat(path string) (Format, error) {
+	name := strings.TrimSuffix(path, export.CompressionOf(path).Extension())
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".ndjson", ".jsonl":
+		return FormatNDJSON, nil
+	case ".csv":
+		return FormatCSV, nil
+	case ".parquet":
+		return FormatParquet, nil
+	case ".npy":
+		return FormatNPY, nil
+	default:
+		return "", fmt.Errorf("%w: cannot detect the format of %q", pinecone.ErrInvalidParams, path)
+	}
+}
+
+func readNDJSON(path string, fn readFunc) error {
+	file, err := export.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	reader := bufio.NewReaderSize(file, 1<<16)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			var vector pinecone.Vector
+			if parseErr := json.Unmarshal(line, &vector); parseErr != nil {
+				if err := fn("", nil, parseErr); err != nil {
+					return err
+				}
+			} else if err := fn(vector.ID, &vector, nil); err != nil {
+				return err
+			}
+		}
+
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+	}
+}
+
+func readCSV(path string, options CSVOptions, fn readFunc) error {
+	file, err := export.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(bufio.NewReaderSize(file, 1<<16))
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+	if options.Comma != 0 {
+		reader.Comma = options.Comma
+	}
+
+	header, err := reader.Read()
+	if err != nil {
+		return fmt.Errorf("failed to read the csv header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[name] = i
+	}
+
+	idColumn, ok := columns[options.idColumn()]
+	if !ok {
+		return fmt.Errorf("%w: csv has no id column %q", pinecone.ErrInvalidParams, options.idColumn())
+	}
+	valuesColumn, ok := columns[options.valuesColumn()]
+	if !ok {
+		return fmt.Errorf("%w: csv has no values column %q", pinecone.ErrInvalidParams, options.valuesColumn())
+	}
+	metadataColumn, hasMetadataColumn := columns[options.metadataColumn()]
+
+	metadataColumns := make(map[string]int, len(options.MetadataColumns))
+	for _, name := range options.MetadataColumns {
+		i, ok := columns[name]
+		if !ok {
+			return fmt.Errorf("%w: csv has no metadata column %q", pinecone.ErrInvalidParams, name)
+		}
+		metadataColumns[name] = i
+	}
+
+	for {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			if err := fn("", nil, parseErr); err != nil {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		vector, id, rowErr := parseCSVRow(row, idColumn, valuesColumn, metadataColumn, hasMetadataColumn, metadataColumns)
+		if err := fn(id, vector, rowErr); err != nil {
+			return err
+		}
+	}
+}
+
+func parseCSVRow(row []string, idColumn, valuesColumn, metadataColumn int, hasMetadataColumn bool, metadataColumns map[string]int) (*pinecone.Vector, string, error) {
+	field := func(i int) string {
+		if i < len(row) {
+			return row[i]
+		}
+		return ""
+	}
+
+	id := field(idColumn)
+	vector := &pinecone.Vector{ID: id}
+
+	if values := field(valuesColumn); values != "" {
+		if err := json.Unmarshal([]byte(values), &vector.Values); err != nil {
+			return nil, id, fmt.Errorf("invalid values: %w", err)
+		}
+	}
+
+	if hasMetadataColumn {
+		if metadata := field(metadataColumn); metadata != "" {
+			if err := json.Unmarshal([]byte(metadata), &vector.Metadata); err != nil {
+				return nil, id, fmt.Errorf("invalid metadata: %w", err)
+			}
+		}
+	}
+
+	for name, i := range metadataColumns {
+		if vector.Metadata == nil {
+			vector.Metadata = make(map[string]any, len(metadataColumns))
+		}
+		vector.Metadata[name] = field(i)
+	}
+
+	return vector, id, nil
+}
+
+func readParquet(path string, fn readFunc) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	parquetFile, err := parquet.OpenFile(file, info.Size())
+	if err != nil {
+		return err
+	}
+
+	// the leaf columns by top-level field, so that both flat repeated columns
+	// and nested lists, as written by pyarrow, are read as lists
+	columns := make(map[string]int)
+	for i, columnPath := range parquetFile.Schema().Columns() {
+		if _, ok := columns[columnPath[0]]; !ok {
+			columns[columnPath[0]] = i
+		}
+	}
+	if _, ok := columns["id"]; !ok {
+		return fmt.Errorf("%w: parquet file has no id column", pinecone.ErrInvalidParams)
+	}
+
+	for _, rowGroup := range parquetFile.RowGroups() {
+		if err := readParquetRowGroup(rowGroup, columns, fn); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func readParquetRowGroup(rowGroup parquet.RowGroup, columns map[string]int, fn readFunc) error {
+	rows := rowGroup.Rows()
+	defer rows.Close()
+
+	buffer := make([]parquet.Row, 64)
+	for {
+		n, err := rows.ReadRows(buffer)
+		for _, row := range buffer[:n] {
+			vector, parseErr := parseParquetRow(row, columns)
+			if err := fn(vector.ID, vector, parseErr); err != nil {
+				return err
+			}
+		}
+
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func parseParquetRow(row parquet.Row, columns map[string]int) (*pinecone.Vector, error) {
+	// the non-null values of every leaf column
+	values := make(map[int][]parquet.Value, len(columns))
+	row.Range(func(columnIndex int, columnValues []parquet.Value) bool {
+		for _, value := range columnValues {
+			if !value.IsNull() {
+				values[columnIndex] = append(values[columnIndex], value)
+			}
+		}
+
+		return true
+	})
+
+	column := func(name string) []parquet.Value {
+		if i, ok := columns[name]; ok {
+			return values[i]
+		}
+		return nil
+	}
+
+	vector := new(pinecone.Vector)
+	if id := column("id"); len(id) > 0 && id[0].Kind() == parquet.ByteArray {
+		vector.ID = string(id[0].ByteArray())
+	}
+
+	vector.Values = float32s(column("values"))
+
+	indices := int32s(column("sparse_indices"))
+	if len(indices) > 0 {
+		vector.SparseValues = &pinecone.SparseVector{Indices: indices, Values: float32s(column("sparse_values"))}
+	}
+
+	if metadata := column("metadata"); len(metadata) > 0 && metadata[0].Kind() == parquet.ByteArray && len(metadata[0].ByteArray()) > 0 {
+		if err := json.Unmarshal(metadata[0].ByteArray(), &vector.Metadata); err != nil {
+			return vector, fmt.Errorf("invalid metadata: %w", err)
+		}
+	}
+
+	return vector, nil
+}
+
+// float32s converts float and double values, returning nil for none.
+func float32s(values []parquet.Value) []float32 {
+	if len(values) == 0 {
+		return nil
+	}
+
+	converted := make([]float32, 0, len(values))
+	for _, value := range values {
+		switch value.Kind() {
+		case parquet.Float:
+			converted = append(converted, value.Float())
+		case parquet.Double:
+			converted = append(converted, float32(value.Double()))
+		}
+	}
+
+	return converted
+}
+
+// int32s converts int32 and int64 values, returning nil for none.
+func int32s(values []parquet.Value) []int32 {
+	if len(values) == 0 {
+		return nil
+	}
+
+	converted := make([]int32, 0, len(values))
+	for _, value := range values {
+		switch value.Kind() {
+		case parquet.Int32:
+			converted = append(converted, value.Int32())
+		case parquet.Int64:
+			converted = append(converted, int32(value.Int64()))
+		}
+	}
+
+	return converted
+}
+
+func readNPY(path, idsPath string, fn readFunc) error {
+	matrix, err := export.Open(path)
+	if err != nil {
+		return err
+	}
+	defer matrix.Close()
+
+	ids, err := export.Open(idsPath)
+	if err != nil {
+		return err
+	}
+	defer ids.Close()
+
+	matrixReader := bufio.NewReaderSize(matrix, 1<<16)
+	rows, columns, err := npy.ReadHeader(matrixReader)
+	if err != nil {
+		return err
+	}
+
+	idsScanner := bufio.NewScanner(ids)
+	row := make([]byte, 4*columns)
+	for i := 0; i < rows; i++ {
+		if !idsScanner.Scan() {
+			if err := idsScanner.Err(); err != nil {
+				return err
+			}
+			return fmt.Errorf("%w: ids file has %d ids, matrix has %d rows", pinecone.ErrInvalidParams, i, rows)
+		}
+
+		if _, err := io.ReadFull(matrixReader, row); err != nil {
+			return fmt.Errorf("failed to read row %d of the matrix: %w", i, err)
+		}
+
+		values := make([]float32, columns)
+		for j := range values {
+			values[j] = math.Float32frombits(binary.LittleEndian.Uint32(row[4*j:]))
+		}
+
+		var id string
+		if err := json.Unmarshal(idsScanner.Bytes(), &id); err != nil {
+			return fmt.Errorf("%w: invalid id on line %d of the ids file: %w", pinecone.ErrInvalidParams, i+1, err)
+		}
+		if err := fn(id, &pinecone.Vector{ID: id, Values: values}, nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -1,28 +1,242 @@
 package pinecone
 
 import (
+	"context"
 	"fmt"
+	"math"
+	"sync"
+	"sync/atomic"
+
 	"github.com/imroc/req/v3"
 )
 
 // IndexClient client for vector operations
 type IndexClient struct {
-	reqClient *req.Client
+	options    *options
+	reqClient  *req.Client
+	controller *Client
+
+	describeMutex    sync.Mutex
+	indexDescription *DescribeIndexResponse
+
+	modelMutex sync.Mutex
+	indexModel *IndexModel
+
+	specMutex sync.Mutex
+	spec      *IndexSpec
+
+	normalizationAdjusted atomic.Int64
+	normalizationRejected atomic.Int64
 }
 
 func NewIndexClient(opts ...CallOptions) (*IndexClient, error) {
 	appliedOptions := applyCallOptions(opts)
+	if err := appliedOptions.normalization.validate(); err != nil {
+		return nil, err
+	}
+
+	baseURL := appliedOptions.baseURL
+	if baseURL == "" {
+		baseURL = fmt.Sprintf("https://%s-%s.svc.%s.pinecone.io", appliedOptions.indexName, appliedOptions.projectName, appliedOptions.environment)
+	}
+	if appliedOptions.cache != nil {
+		appliedOptions.cache.index = baseURL
+	}
+
 	reqClient := req.
 		C().
-		SetBaseURL(fmt.Sprintf("https://%s-%s.svc.%s.pinecone.io", appliedOptions.indexName, appliedOptions.projectName, appliedOptions.environment)).
+		SetBaseURL(baseURL).
 		SetCommonHeader("Api-Key", appliedOptions.apiKey)
+
+	// the base URL of the index never applies to the controller
+	controllerOptions := *appliedOptions
+	controllerOptions.baseURL = ""
+
 	return &IndexClient{
-		reqClient: reqClient,
+		options:    appliedOptions,
+		reqClient:  reqClient,
+		controller: newClient(&controllerOptions),
 	}, nil
 }
 
 func (ic *IndexClient) Debug() *IndexClient {
 	ic.reqClient.DebugLog = true
 	ic.reqClient = ic.reqClient.EnableDumpAll()
+	ic.controller = ic.controller.Debug()
 	return ic
 }
+
+// DescribeIndex gets a description of the index the client is connected to.
+// The description is fetched from the controller once and cached afterwards.
+func (ic *IndexClient) DescribeIndex(ctx context.Context) (*DescribeIndexResponse, error) {
+	ic.describeMutex.Lock()
+	defer ic.describeMutex.Unlock()
+
+	if ic.indexDescription != nil {
+		return ic.indexDescription, nil
+	}
+
+	resp, err := ic.controller.DescribeIndex(ctx, ic.options.indexName)
+	if err != nil {
+		return nil, err
+	}
+
+	ic.indexDescription = resp
+
+	return resp, nil
+}
+
+// describeIndexModel gets a description of the index from the global
+// control plane, fetched once and cached afterwards.
+func (ic *IndexClient) describeIndexModel(ctx context.Context) (*IndexModel, error) {
+	ic.modelMutex.Lock()
+	defer ic.modelMutex.Unlock()
+
+	if ic.indexModel != nil {
+		return ic.indexModel, nil
+	}
+
+	resp, err := ic.controller.DescribeIndexModel(ctx, ic.options.indexName)
+	if err != nil {
+		return nil, err
+	}
+
+	ic.indexModel = resp
+
+	return resp, nil
+}
+
+// IndexSpec represents the dimension and metric of an index.
+type IndexSpec struct {
+	Dimension int
+	// The metric, empty when it could not be learned and was not set with WithMetric.
+	Metric CreateIndexMetric
+}
+
+// Spec returns the dimension and metric of the index. They are taken from
+// WithDimension and WithMetric when set, and are otherwise learned once from
+// DescribeIndex when the index name is known, or from DescribeIndexStats,
+// and cached afterwards. A dimension of zero means it is still unknown, as
+// for an empty index described by DescribeIndexStats.
+func (ic *IndexClient) Spec(ctx context.Context) (*IndexSpec, error) {
+	if ic.options.dimension > 0 {
+		return &IndexSpec{Dimension: ic.options.dimension, Metric: ic.options.metric}, nil
+	}
+
+	ic.specMutex.Lock()
+	defer ic.specMutex.Unlock()
+
+	if ic.spec != nil {
+		return ic.spec, nil
+	}
+
+	spec := &IndexSpec{Metric: ic.options.metric}
+	if ic.options.indexName != "" {
+		index, err := ic.DescribeIndex(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		spec.Dimension = index.Database.Dimension
+		if index.Database.Metric != "" {
+			spec.Metric = CreateIndexMetric(index.Database.Metric)
+		}
+	} else {
+		stats, err := ic.DescribeIndexStats(ctx, DescribeIndexStatsParams{})
+		if err != nil {
+			return nil, err
+		}
+
+		spec.Dimension = int(stats.Dimensions)
+	}
+
+	if spec.Dimension > 0 {
+		ic.spec = spec
+	}
+
+	return spec, nil
+}
+
+// metric returns the metric of the index, learned through Spec when the
+// index name or dimension is known, and defaulting to cosine otherwise, as
+// DescribeIndexStats does not report it.
+func (ic *IndexClient) metric(ctx context.Context) (CreateIndexMetric, error) {
+	metric := ic.options.metric
+	if ic.options.indexName != "" || ic.options.dimension > 0 {
+		spec, err := ic.Spec(ctx)
+		if err != nil {
+			return "", err
+		}
+
+		metric = spec.Metric
+	}
+	if metric == "" {
+		metric = CreateIndexMetricCosine
+	}
+
+	return metric, nil
+}
+
+// valuesValidator validates the dense and sparse values of the vectors of a
+// single call against the index when dimension validation is enabled. The
+// spec is learned at most once per call, also when the index is still empty
+// and Spec cannot cache its dimension.
+type valuesValidator struct {
+	ic   *IndexClient
+	spec *IndexSpec
+}
+
+func (ic *IndexClient) newValuesValidator() *valuesValidator {
+	return &valuesValidator{ic: ic}
+}
+
+func (v *valuesValidator) validate(ctx context.Context, id string, values []float32, sparse *SparseVector) error {
+	if v.ic.options == nil || !v.ic.options.validateDimension {
+		return nil
+	}
+
+	if v.ic.options.strictValues {
+		if err := validateFiniteValues(id, values, sparse); err != nil {
+			return err
+		}
+	}
+
+	if len(values) == 0 {
+		return nil
+	}
+
+	if v.spec == nil {
+		spec, err := v.ic.Spec(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to learn the index dimension: %w", err)
+		}
+
+		v.spec = spec
+	}
+
+	if v.spec.Dimension > 0 && len(values) != v.spec.Dimension {
+		return fmt.Errorf("%w: vector %q has dimension %d, index has dimension %d", ErrInvalidParams, id, len(values), v.spec.Dimension)
+	}
+
+	return nil
+}
+
+func validateFiniteValues(id string, values []float32, sparse *SparseVector) error {
+	for i, v := range values {
+		if math.IsNaN(float64(v)) || math.IsInf(float64(v), 0) {
+			return fmt.Errorf("%w: vector %q has non-finite value %v at position %d", ErrInvalidParams, id, v, i)
+		}
+	}
+
+	if sparse == nil {
+		return nil
+	}
+
+	for i, v := range sparse.Values {
+		if math.IsNaN(float64(v)) || math.IsInf(float64(v), 0) {
+			return fmt.Errorf("%w: vector %q has non-finite sparse value %v at index %d", ErrInvalidParams, id, v, sparse.Indices[i])
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,170 @@
+package pinecone
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndexClient_Spec(t *testing.T) {
+	t.Run("from DescribeIndex", func(t *testing.T) {
+		assert := assert.New(t)
+		require := require.New(t)
+
+		var describes atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal("/databases/example", r.URL.Path)
+			describes.Add(1)
+
+			_ = json.NewEncoder(w).Encode(DescribeIndexResponse{Database: Database{Name: "example", Metric: "cosine", Dimension: 3}})
+		}))
+		defer server.Close()
+
+		ic := newTestIndexClient(server.URL, WithIndexName("example"))
+
+		for i := 0; i < 2; i++ {
+			spec, err := ic.Spec(context.Background())
+			require.NoError(err)
+			assert.Equal(&IndexSpec{Dimension: 3, Metric: CreateIndexMetricCosine}, spec)
+		}
+		assert.Equal(int32(1), describes.Load())
+	})
+
+	t.Run("from DescribeIndexStats", func(t *testing.T) {
+		assert := assert.New(t)
+		require := require.New(t)
+
+		_, server := newFakeIndexServer(t)
+		defer server.Close()
+
+		spec, err := newTestIndexClient(server.URL).Spec(context.Background())
+		require.NoError(err)
+		assert.Equal(2, spec.Dimension)
+	})
+
+	t.Run("from options", func(t *testing.T) {
+		assert := assert.New(t)
+		require := require.New(t)
+
+		spec, err := newTestIndexClient("http://127.0.0.1:0", WithDimension(4), WithMetric(CreateIndexMetricEuclidean)).Spec(context.Background())
+		require.NoError(err)
+		assert.Equal(&IndexSpec{Dimension: 4, Metric: CreateIndexMetricEuclidean}, spec)
+	})
+}
+
+func TestIndexClient_DimensionValidation(t *testing.T) {
+	t.Run("rejects mismatched dimensions", func(t *testing.T) {
+		assert := assert.New(t)
+		require := require.New(t)
+
+		index, server := newFakeIndexServer(t)
+		defer server.Close()
+
+		ic := newTestIndexClient(server.URL, WithDimensionValidation())
+
+		_, err := ic.UpsertVectors(context.Background(), UpsertVectorsParams{
+			Vectors: []*Vector{{ID: "a", Values: []float32{1, 0}}, {ID: "b", Values: []float32{1, 0, 0}}},
+		})
+		require.ErrorIs(err, ErrInvalidParams)
+		assert.Contains(err.Error(), `"b"`)
+		assert.Zero(index.count(""))
+
+		_, err = ic.UpsertVectors(context.Background(), UpsertVectorsParams{
+			Vectors: []*Vector{{ID: "a", Values: []float32{1, 0}}},
+		})
+		require.NoError(err)
+		assert.Equal(1, index.count(""))
+
+		err = ic.UpdateVector(context.Background(), UpdateVectorParams{ID: "a", Values: []float32{1}})
+		require.ErrorIs(err, ErrInvalidParams)
+
+		_, err = ic.Query(context.Background(), QueryParams{TopK: 1, Vector: []float32{1, 0, 0}})
+		require.ErrorIs(err, ErrInvalidParams)
+
+		_, err = ic.Query(context.Background(), QueryParams{TopK: 1, Vector: []float32{1, 0}})
+		require.NoError(err)
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		require := require.New(t)
+
+		_, server := newFakeIndexServer(t)
+		defer server.Close()
+
+		_, err := newTestIndexClient(server.URL).UpsertVectors(context.Background(), UpsertVectorsParams{
+			Vectors: []*Vector{{ID: "a", Values: []float32{1, 0, 0}}},
+		})
+		require.NoError(err)
+	})
+
+	t.Run("fails when the dimension cannot be learned", func(t *testing.T) {
+		require := require.New(t)
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+
+		_, err := newTestIndexClient(server.URL, WithDimensionValidation()).UpsertVectors(context.Background(), UpsertVectorsParams{
+			Vectors: []*Vector{{ID: "a", Values: []float32{1, 0}}},
+		})
+		require.ErrorIs(err, ErrRequestFailed)
+	})
+
+	t.Run("learns the dimension of an empty index once per call", func(t *testing.T) {
+		assert := assert.New(t)
+		require := require.New(t)
+
+		var describes atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/describe_index_stats":
+				describes.Add(1)
+				_ = json.NewEncoder(w).Encode(DescribeIndexStatsResponse{})
+			case "/vectors/upsert":
+				_ = json.NewEncoder(w).Encode(UpsertVectorsResponse{UpsertedCount: 3})
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		defer server.Close()
+
+		_, err := newTestIndexClient(server.URL, WithDimensionValidation()).UpsertVectors(context.Background(), UpsertVectorsParams{
+			Vectors: []*Vector{{ID: "a", Values: []float32{1, 0}}, {ID: "b", Values: []float32{0, 1}}, {ID: "c", Values: []float32{1, 1}}},
+		})
+		require.NoError(err)
+		assert.Equal(int32(1), describes.Load())
+	})
+
+	t.Run("strict mode rejects non-finite values", func(t *testing.T) {
+		assert := assert.New(t)
+		require := require.New(t)
+
+		index, server := newFakeIndexServer(t)
+		defer server.Close()
+
+		ic := newTestIndexClient(server.URL, WithStrictValidation())
+
+		_, err := ic.UpsertVectors(context.Background(), UpsertVectorsParams{
+			Vectors: []*Vector{{ID: "a", Values: []float32{float32(math.NaN()), 0}}},
+		})
+		require.ErrorIs(err, ErrInvalidParams)
+
+		_, err = ic.UpsertVectors(context.Background(), UpsertVectorsParams{
+			Vectors: []*Vector{{
+				ID:           "a",
+				Values:       []float32{1, 0},
+				SparseValues: &SparseVector{Indices: []int32{7}, Values: []float32{float32(math.Inf(1))}},
+			}},
+		})
+		require.ErrorIs(err, ErrInvalidParams)
+		assert.Zero(index.count(""))
+	})
+}
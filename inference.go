@@ -0,0 +1,293 @@
+package pinecone
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/imroc/req/v3"
+)
+
+const defaultEmbedBatchSize = 96
+
+// EmbedModelMaxBatchSize holds the maximum number of inputs per embed request
+// of the models hosted by Pinecone. Models that are not listed are sent 96
+// inputs per request.
+var EmbedModelMaxBatchSize = map[string]int{
+	"multilingual-e5-large":      96,
+	"llama-text-embed-v2":        96,
+	"pinecone-sparse-english-v0": 96,
+}
+
+// InferenceClient client for the Pinecone Inference API.
+type InferenceClient struct {
+	reqClient *req.Client
+}
+
+// NewInferenceClient creates a new Pinecone Inference API client.
+func NewInferenceClient(opts ...CallOptions) (*InferenceClient, error) {
+	appliedOptions := applyCallOptions(opts)
+	baseURL := appliedOptions.baseURL
+	if baseURL == "" {
+		baseURL = defaultAPIBaseURL
+	}
+
+	reqClient := req.
+		C().
+		SetBaseURL(baseURL).
+		SetCommonHeader("Api-Key", appliedOptions.apiKey).
+		SetCommonHeader("X-Pinecone-API-Version", apiVersion)
+	return &InferenceClient{
+		reqClient: reqClient,
+	}, nil
+}
+
+// Debug enables debug logging and http dump for the client.
+func (c *InferenceClient) Debug() *InferenceClient {
+	c.reqClient.DebugLog = true
+	c.reqClient = c.reqClient.EnableDumpAll()
+	return c
+}
+
+// EmbedInputType describes whether the inputs are embedded as documents to
+// store, or as queries to search with.
+type EmbedInputType string
+
+const (
+	EmbedInputTypePassage EmbedInputType = "passage"
+	EmbedInputTypeQuery   EmbedInputType = "query"
+)
+
+// EmbedVectorType is the type of the vectors a model produces.
+type EmbedVectorType string
+
+const (
+	EmbedVectorTypeDense  EmbedVectorType = "dense"
+	EmbedVectorTypeSparse EmbedVectorType = "sparse"
+)
+
+// EmbedParameters represents the model-specific parameters of an embed request.
+// See https://docs.pinecone.io/reference/api/inference/generate-embeddings for more information.
+type EmbedParameters struct {
+	// Whether the inputs are documents or queries.
+	InputType EmbedInputType `json:"input_type,omitempty"`
+	// How inputs longer than the model limit are handled: END or NONE.
+	Truncate string `json:"truncate,omitempty"`
+	// The dimension of the embeddings, for models that support several.
+	Dimension int `json:"dimension,omitempty"`
+	// Whether sparse models return the tokens behind each sparse index.
+	ReturnTokens bool `json:"return_tokens,omitempty"`
+}
+
+// Embedding represents one embedding returned by an embed request. Dense
+// models fill Values, sparse models fill SparseValues.
+type Embedding struct {
+	VectorType   EmbedVectorType `json:"vector_type"`
+	Values       []float32       `json:"values"`
+	SparseValues *SparseVector   `json:"sparse_values"`
+	// The tokens behind each sparse index, when requested with ReturnTokens.
+	SparseTokens []string `json:"sparse_tokens"`
+}
+
+// EmbedUsage represents the usage reported by an embed request.
+type EmbedUsage struct {
+	TotalTokens int `json:"total_tokens"`
+}
+
+// EmbedResponse represents the response from an embed request.
+type EmbedResponse struct {
+	Model      string          `json:"model"`
+	VectorType EmbedVectorType `json:"vector_type"`
+	// One embedding per input, in the same order as the inputs.
+	Data  []*Embedding `json:"data"`
+	Usage EmbedUsage   `json:"usage"`
+}
+
+type embedInput struct {
+	Text string `json:"text"`
+}
+
+type embedBodyParams struct {
+	Model      string          `json:"model"`
+	Parameters EmbedParameters `json:"parameters"`
+	Inputs     []embedInput    `json:"inputs"`
+}
+
+type embedResponseBody struct {
+	Model      string          `json:"model"`
+	VectorType EmbedVectorType `json:"vector_type"`
+	Data       []struct {
+		VectorType    EmbedVectorType `json:"vector_type"`
+		Values        []float32       `json:"values"`
+		SparseValues  []float32       `json:"sparse_values"`
+		SparseIndices []int64         `json:"sparse_indices"`
+		SparseTokens  []string        `json:"sparse_tokens"`
+	} `json:"data"`
+	Usage EmbedUsage `json:"usage"`
+}
+
+// Embed generates embeddings for the inputs with a model hosted by Pinecone.
+// Inputs are split into requests of at most EmbedModelMaxBatchSize inputs,
+// and the usage of all requests is summed.
+//
+// API Reference: https://docs.pinecone.io/reference/api/inference/generate-embeddings
+func (c *InferenceClient) Embed(ctx context.Context, model string, inputs []string, parameters EmbedParameters) (*EmbedResponse, error) {
+	if model == "" {
+		return nil, fmt.Errorf("%w: model is required", ErrInvalidParams)
+	}
+	if len(inputs) < 1 {
+		return nil, fmt.Errorf("%w: inputs is required", ErrInvalidParams)
+	}
+
+	batchSize, ok := EmbedModelMaxBatchSize[model]
+	if !ok || batchSize <= 0 {
+		batchSize = defaultEmbedBatchSize
+	}
+
+	respBody := &EmbedResponse{
+		Model: model,
+		Data:  make([]*Embedding, 0, len(inputs)),
+	}
+	for start := 0; start < len(inputs); start += batchSize {
+		end := start + batchSize
+		if end > len(inputs) {
+			end = len(inputs)
+		}
+
+		resp, err := c.embedBatch(ctx, model, inputs[start:end], parameters)
+		if err != nil {
+			return nil, err
+		}
+
+		respBody.VectorType = resp.VectorType
+		respBody.Data = append(respBody.Data, resp.Data...)
+		respBody.Usage.TotalTokens += resp.Usage.TotalTokens
+	}
+
+	return respBody, nil
+}
+
+func (c *InferenceClient) embedBatch(ctx context.Context, model string, inputs []string, parameters EmbedParameters) (*EmbedResponse, error) {
+	body := embedBodyParams{
+		Model:      model,
+		Parameters: parameters,
+		Inputs:     make([]embedInput, len(inputs)),
+	}
+	for i, input := range inputs {
+		body.Inputs[i] = embedInput{Text: input}
+	}
+
+	var respBody embedResponseBody
+	resp, err := c.reqClient.
+		R().
+		SetContentType("application/json").
+		SetBody(body).
+		SetSuccessResult(&respBody).
+		SetContext(ctx).
+		Post("/embed")
+	if err != nil {
+		return nil, err
+	}
+	if !resp.IsSuccessState() {
+		buffer := new(bytes.Buffer)
+		_, err := buffer.ReadFrom(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, &RequestError{StatusCode: resp.StatusCode, Body: buffer.String()}
+	}
+
+	if len(respBody.Data) != len(inputs) {
+		return nil, fmt.Errorf("%w: got %d embeddings for %d inputs", ErrRequestFailed, len(respBody.Data), len(inputs))
+	}
+
+	embedResp := &EmbedResponse{
+		Model:      respBody.Model,
+		VectorType: respBody.VectorType,
+		Data:       make([]*Embedding, len(respBody.Data)),
+		Usage:      respBody.Usage,
+	}
+	for i, data := range respBody.Data {
+		vectorType := data.VectorType
+		if vectorType == "" {
+			vectorType = respBody.VectorType
+		}
+
+		embedding := &Embedding{
+			VectorType:   vectorType,
+			SparseTokens: data.SparseTokens,
+		}
+		if vectorType == EmbedVectorTypeSparse {
+			sparse, err := newSparseVectorFromEmbedding(data.SparseIndices, data.SparseValues)
+			if err != nil {
+				return nil, err
+			}
+
+			embedding.SparseValues = sparse
+		} else {
+			embedding.Values = data.Values
+		}
+
+		embedResp.Data[i] = embedding
+	}
+
+	return embedResp, nil
+}
+
+// newSparseVectorFromEmbedding decodes the sparse indices and values of an
+// embedding into a SparseVector.
+func newSparseVectorFromEmbedding(indices []int64, values []float32) (*SparseVector, error) {
+	if len(indices) != len(values) {
+		return nil, fmt.Errorf("%w: sparse embedding has %d indices and %d values", ErrRequestFailed, len(indices), len(values))
+	}
+
+	sparse := &SparseVector{
+		Indices: make([]int32, len(indices)),
+		Values:  values,
+	}
+	for i, index := range indices {
+		if index < 0 || index > math.MaxInt32 {
+			return nil, fmt.Errorf("%w: sparse index %d does not fit into int32", ErrRequestFailed, index)
+		}
+
+		sparse.Indices[i] = int32(index)
+	}
+
+	return sparse, nil
+}
+
+// Embedder returns an Embedder that embeds texts with the given dense model,
+// to be used with WithEmbedder.
+func (c *InferenceClient) Embedder(model string, parameters EmbedParameters) Embedder {
+	return &inferenceEmbedder{
+		client:     c,
+		model:      model,
+		parameters: parameters,
+	}
+}
+
+type inferenceEmbedder struct {
+	client     *InferenceClient
+	model      string
+	parameters EmbedParameters
+}
+
+func (e *inferenceEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	resp, err := e.client.Embed(ctx, e.model, texts, e.parameters)
+	if err != nil {
+		return nil, err
+	}
+
+	embeddings := make([][]float32, len(resp.Data))
+	for i, embedding := range resp.Data {
+		if embedding.VectorType == EmbedVectorTypeSparse {
+			return nil, fmt.Errorf("%w: model %q produces sparse vectors", ErrInvalidParams, e.model)
+		}
+
+		embeddings[i] = embedding.Values
+	}
+
+	return embeddings, nil
+}
@@ -0,0 +1,114 @@
+package pinecone
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newInferenceTestServer(t *testing.T, batches *[]int) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/embed", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "test-key", r.Header.Get("Api-Key"))
+		assert.Equal(t, apiVersion, r.Header.Get("X-Pinecone-API-Version"))
+
+		var body embedBodyParams
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		*batches = append(*batches, len(body.Inputs))
+
+		data := make([]map[string]any, len(body.Inputs))
+		switch body.Model {
+		case "dense-model":
+			for i, input := range body.Inputs {
+				data[i] = map[string]any{"vector_type": "dense", "values": []float32{float32(len(input.Text)), 0}}
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"model": body.Model, "vector_type": "dense", "data": data,
+				"usage": map[string]any{"total_tokens": len(body.Inputs) * 2},
+			})
+		case "sparse-model":
+			for i := range body.Inputs {
+				data[i] = map[string]any{"vector_type": "sparse", "sparse_indices": []int64{3, 10}, "sparse_values": []float32{0.5, 0.25}, "sparse_tokens": []string{"a", "b"}}
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"model": body.Model, "vector_type": "sparse", "data": data,
+				"usage": map[string]any{"total_tokens": 1},
+			})
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":{"code":"INVALID_ARGUMENT","message":"unknown model"}}`))
+		}
+	})
+
+	return httptest.NewServer(mux)
+}
+
+func TestInferenceEmbed(t *testing.T) {
+	var batches []int
+	server := newInferenceTestServer(t, &batches)
+	defer server.Close()
+
+	c, err := NewInferenceClient(WithAPIKey("test-key"), WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	t.Run("Dense", func(t *testing.T) {
+		assert := assert.New(t)
+		require := require.New(t)
+
+		EmbedModelMaxBatchSize["dense-model"] = 2
+		defer delete(EmbedModelMaxBatchSize, "dense-model")
+		batches = nil
+
+		resp, err := c.Embed(context.Background(), "dense-model", []string{"a", "bb", "ccc"}, EmbedParameters{InputType: EmbedInputTypePassage})
+		require.NoError(err)
+		assert.Equal([]int{2, 1}, batches)
+		assert.Equal(EmbedVectorTypeDense, resp.VectorType)
+		require.Len(resp.Data, 3)
+		assert.Equal([]float32{3, 0}, resp.Data[2].Values)
+		assert.Nil(resp.Data[2].SparseValues)
+		assert.Equal(6, resp.Usage.TotalTokens)
+	})
+
+	t.Run("Sparse", func(t *testing.T) {
+		assert := assert.New(t)
+		require := require.New(t)
+
+		resp, err := c.Embed(context.Background(), "sparse-model", []string{"a"}, EmbedParameters{ReturnTokens: true})
+		require.NoError(err)
+		require.Len(resp.Data, 1)
+		assert.Equal(EmbedVectorTypeSparse, resp.Data[0].VectorType)
+		assert.Equal(&SparseVector{Indices: []int32{3, 10}, Values: []float32{0.5, 0.25}}, resp.Data[0].SparseValues)
+		assert.Equal([]string{"a", "b"}, resp.Data[0].SparseTokens)
+
+		data, err := json.Marshal(resp.Data[0])
+		require.NoError(err)
+		assert.JSONEq(`{
+			"vector_type": "sparse",
+			"values": null,
+			"sparse_values": {"indices": [3, 10], "values": [0.5, 0.25]},
+			"sparse_tokens": ["a", "b"]
+		}`, string(data))
+	})
+
+	t.Run("Embedder", func(t *testing.T) {
+		embeddings, err := c.Embedder("dense-model", EmbedParameters{}).Embed(context.Background(), []string{"abcd"})
+		require.NoError(t, err)
+		assert.Equal(t, [][]float32{{4, 0}}, embeddings)
+
+		_, err = c.Embedder("sparse-model", EmbedParameters{}).Embed(context.Background(), []string{"abcd"})
+		require.ErrorIs(t, err, ErrInvalidParams)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		_, err := c.Embed(context.Background(), "unknown-model", []string{"a"}, EmbedParameters{})
+		require.ErrorIs(t, err, ErrRequestFailed)
+
+		_, err = c.Embed(context.Background(), "dense-model", nil, EmbedParameters{})
+		require.ErrorIs(t, err, ErrInvalidParams)
+	})
+}
@@ -0,0 +1,106 @@
+// Package npy reads and writes the headers of NumPy .npy files holding
+// two-dimensional little-endian float32 arrays, the layout of a matrix of
+// dense vectors.
+//
+// See https://numpy.org/doc/stable/reference/generated/numpy.lib.format.html
+// for the file format.
+package npy
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const magic = "\x93NUMPY"
+
+// ErrUnsupported is returned when reading a file holding anything else than
+// a C-ordered two-dimensional float32 array.
+var ErrUnsupported = errors.New("unsupported npy file")
+
+var shapePattern = regexp.MustCompile(`'shape':\s*\((\d+),\s*(\d+)\s*,?\s*\)`)
+
+// WriteHeader writes the header of a .npy file of a rows by columns float32
+// matrix. The header is padded so the data starts at a multiple of 64 bytes.
+func WriteHeader(w io.Writer, rows, columns int) error {
+	dict := fmt.Sprintf("{'descr': '<f4', 'fortran_order': False, 'shape': (%d, %d), }", rows, columns)
+
+	// magic, version and header length take 10 bytes, the header ends with a newline
+	padding := 64 - (10+len(dict)+1)%64
+	if padding == 64 {
+		padding = 0
+	}
+
+	header := new(bytes.Buffer)
+	header.WriteString(magic)
+	header.Write([]byte{1, 0})
+	_ = binary.Write(header, binary.LittleEndian, uint16(len(dict)+padding+1))
+	header.WriteString(dict)
+	header.WriteString(strings.Repeat(" ", padding))
+	header.WriteByte('\n')
+
+	_, err := w.Write(header.Bytes())
+
+	return err
+}
+
+// ReadHeader reads the header of a .npy file and returns the shape of the
+// float32 matrix that follows.
+func ReadHeader(r io.Reader) (rows, columns int, err error) {
+	prefix := make([]byte, 8)
+	if _, err := io.ReadFull(r, prefix); err != nil {
+		return 0, 0, err
+	}
+	if string(prefix[:6]) != magic {
+		return 0, 0, fmt.Errorf("%w: missing magic bytes", ErrUnsupported)
+	}
+
+	var length int
+	switch prefix[6] {
+	case 1:
+		var n uint16
+		if err := binary.Read(r, binary.LittleEndian, &n); err != nil {
+			return 0, 0, err
+		}
+		length = int(n)
+	case 2, 3:
+		var n uint32
+		if err := binary.Read(r, binary.LittleEndian, &n); err != nil {
+			return 0, 0, err
+		}
+		length = int(n)
+	default:
+		return 0, 0, fmt.Errorf("%w: version %d", ErrUnsupported, prefix[6])
+	}
+	if length > 1<<20 {
+		return 0, 0, fmt.Errorf("%w: header is too large", ErrUnsupported)
+	}
+
+	header := make([]byte, length)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return 0, 0, err
+	}
+
+	dict := string(header)
+	if !strings.Contains(dict, "'descr': '<f4'") {
+		return 0, 0, fmt.Errorf("%w: only little-endian float32 arrays are supported", ErrUnsupported)
+	}
+	if strings.Contains(dict, "'fortran_order': True") {
+		return 0, 0, fmt.Errorf("%w: fortran order", ErrUnsupported)
+	}
+
+	shape := shapePattern.FindStringSubmatch(dict)
+	if shape == nil {
+		return 0, 0, fmt.Errorf("%w: only two-dimensional arrays are supported", ErrUnsupported)
+	}
+
+	rows, _ = strconv.Atoi(shape[1])
+	columns, _ = strconv.Atoi(shape[2])
+
+	return rows, columns, nil
+}
@@ -0,0 +1,34 @@
+package npy
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeader(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	buf := new(bytes.Buffer)
+	require.NoError(WriteHeader(buf, 1000, 1536))
+	assert.Zero(buf.Len() % 64)
+	assert.Equal(byte('\n'), buf.Bytes()[buf.Len()-1])
+
+	rows, columns, err := ReadHeader(buf)
+	require.NoError(err)
+	assert.Equal(1000, rows)
+	assert.Equal(1536, columns)
+	assert.Zero(buf.Len())
+}
+
+func TestReadHeader_Unsupported(t *testing.T) {
+	header := "{'descr': '<f8', 'fortran_order': False, 'shape': (2, 3), }\n"
+	data := append([]byte("\x93NUMPY\x01\x00"), byte(len(header)), 0)
+	data = append(data, header...)
+
+	_, _, err := ReadHeader(bytes.NewReader(data))
+	assert.ErrorIs(t, err, ErrUnsupported)
+}
@@ -0,0 +1,477 @@
+// Package migrate copies namespaces from one index to another, e.g. from a
+// pod-based index to a serverless one, to another region, or to an index
+// with another metric, optionally renaming the namespaces and transforming
+// the vectors on the way.
+package migrate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/nekomeowww/go-pinecone"
+)
+
+const (
+	defaultBatchSize      = 100
+	defaultConcurrency    = 4
+	defaultVerifyInterval = time.Second
+)
+
+// ErrCountMismatch is returned when the number of vectors of a destination
+// namespace does not match its source once the migration completes.
+var ErrCountMismatch = errors.New("vector count mismatch")
+
+// TransformFunc transforms a vector of a source namespace before it is
+// upserted into the destination, e.g. to drop fields, rewrite its metadata
+// or re-embed it. Returning a nil vector drops it, returning an error stops
+// the migration.
+type TransformFunc func(ctx context.Context, namespace string, vector *pinecone.Vector) (*pinecone.Vector, error)
+
+// Options represents the options of a migration.
+type Options struct {
+	// The source namespaces to copy. Defaults to every namespace of the
+	// source index.
+	Namespaces []string
+	// Maps source namespaces to the name of their destination namespace.
+	// Namespaces not in the map keep their name.
+	Rename map[string]string
+	// Called with every vector before it is upserted.
+	Transform TransformFunc
+	// The number of vectors per upsert request. Defaults to 100.
+	BatchSize int
+	// The number of upsert requests sent in parallel. Defaults to 4.
+	Concurrency int
+	// The path of the checkpoint file. When set, progress is saved after
+	// every page of vectors copied, and a migration finding a checkpoint
+	// resumes where it stopped. The checkpoint is removed once every
+	// namespace is copied.
+	CheckpointPath string
+	// How long the verification waits for the counts of the destination to
+	// catch up, as the stats of an index are eventually consistent. Defaults
+	// to a single check.
+	VerifyTimeout time.Duration
+	// Called with the progress of a namespace after every batch.
+	OnProgress func(progress NamespaceReport)
+}
+
+// Report represents the outcome of a migration.
+type Report struct {
+	Namespaces []NamespaceReport `json:"namespaces"`
+}
+
+// NamespaceReport represents the outcome of the migration of a namespace.
+type NamespaceReport struct {
+	Source      string `json:"source"`
+	Destination string `json:"destination"`
+	// The number of vectors upserted into the destination.
+	Copied int64 `json:"copied"`
+	// The number of vectors dropped by the transform.
+	Dropped int64 `json:"dropped"`
+	// The number of vectors of the source and destination namespaces, as
+	// reported by DescribeIndexStats during verification.
+	SourceCount      int64 `json:"sourceCount"`
+	DestinationCount int64 `json:"destinationCount"`
+	// Whether the destination holds as many vectors as the source, minus the
+	// dropped ones.
+	Verified bool `json:"verified"`
+}
+
+type checkpoint struct {
+	Namespaces map[string]*namespaceCheckpoint `json:"namespaces"`
+}
+
+type namespaceCheckpoint struct {
+	Cursor  string `json:"cursor"`
+	Done    bool   `json:"done"`
+	Copied  int64  `json:"copied"`
+	Dropped int64  `json:"dropped"`
+}
+
+// Migrate copies namespaces from the source index to the destination, then
+// verifies the per-namespace counts of both indexes with DescribeIndexStats.
+// The destination namespaces are expected to be empty, or to hold only
+// vectors also in the source, for the verification to succeed. When it does
+// not, the report is returned along with ErrCountMismatch.
+//
+// Resumed migrations continue at the start of the page of vectors being
+// copied, so a few vectors may be upserted, and counted, twice.
+func Migrate(ctx context.Context, source, destination *pinecone.IndexClient, opts Options) (*Report, error) {
+	if opts.BatchSize < 0 || opts.Concurrency < 0 {
+		return nil, fmt.Errorf("%w: batch size and concurrency must not be negative", pinecone.ErrInvalidParams)
+	}
+	if opts.BatchSize == 0 {
+		opts.BatchSize = defaultBatchSize
+	}
+	if opts.Concurrency == 0 {
+		opts.Concurrency = defaultConcurrency
+	}
+
+	namespaces := opts.Namespaces
+	if len(namespaces) == 0 {
+		stats, err := source.DescribeIndexStats(ctx, pinecone.DescribeIndexStatsParams{})
+		if err != nil {
+			return nil, err
+		}
+
+		for namespace := range stats.Namespaces {
+			namespaces = append(namespaces, namespace)
+		}
+		sort.Strings(namespaces)
+	}
+
+	report := &Report{Namespaces: make([]NamespaceReport, len(namespaces))}
+	destinations := make(map[string]string, len(namespaces))
+	for i, namespace := range namespaces {
+		name := namespace
+		if renamed, ok := opts.Rename[namespace]; ok {
+			name = renamed
+		}
+		if other, ok := destinations[name]; ok {
+			return nil, fmt.Errorf("%w: namespaces %q and %q are both copied to %q", pinecone.ErrInvalidParams, other, namespace, name)
+		}
+
+		destinations[name] = namespace
+		report.Namespaces[i] = NamespaceReport{Source: namespace, Destination: name}
+	}
+
+	saved, err := loadCheckpoint(opts.CheckpointPath)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range report.Namespaces {
+		m := &migration{
+			source:      source,
+			destination: destination,
+			opts:        opts,
+			report:      &report.Namespaces[i],
+			saved:       saved,
+			completed:   make(map[int64]bool),
+			sem:         make(chan struct{}, opts.Concurrency),
+		}
+		if err := m.run(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	if opts.CheckpointPath != "" {
+		if err := os.Remove(opts.CheckpointPath); err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+
+	if err := verify(ctx, source, destination, report, opts.VerifyTimeout); err != nil {
+		return report, err
+	}
+
+	return report, nil
+}
+
+func loadCheckpoint(path string) (*checkpoint, error) {
+	saved := &checkpoint{Namespaces: make(map[string]*namespaceCheckpoint)}
+	if path == "" {
+		return saved, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return saved, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, saved); err != nil {
+		return nil, fmt.Errorf("invalid checkpoint: %w", err)
+	}
+	if saved.Namespaces == nil {
+		saved.Namespaces = make(map[string]*namespaceCheckpoint)
+	}
+
+	return saved, nil
+}
+
+// pendingCursor is a cursor of the source namespace, saved once every batch
+// dispatched before it has completed.
+type pendingCursor struct {
+	batches int64
+	cursor  string
+	done    bool
+	// the vectors dropped from the pages before the cursor, as the pages
+	// after it are scanned, and their vectors dropped, again on resume
+	dropped int64
+}
+
+// migration copies a namespace.
+type migration struct {
+	source      *pinecone.IndexClient
+	destination *pinecone.IndexClient
+	opts        Options
+	report      *NamespaceReport
+	saved       *checkpoint
+
+	// the vectors of the batch being filled, and the number of batches
+	// dispatched so far
+	current    []*pinecone.Vector
+	dispatched int64
+
+	sem    chan struct{}
+	wg     sync.WaitGroup
+	cancel context.CancelFunc
+
+	mu sync.Mutex
+	// guarded by mu
+	err       error
+	completed map[int64]bool
+	watermark int64
+	cursors   []pendingCursor
+}
+
+func (m *migration) run(ctx context.Context) error {
+	var cursor string
+	if saved, ok := m.saved.Namespaces[m.report.Source]; ok {
+		if saved.Done {
+			m.report.Copied = saved.Copied
+			m.report.Dropped = saved.Dropped
+			return nil
+		}
+
+		cursor = saved.Cursor
+		m.report.Copied = saved.Copied
+		m.report.Dropped = saved.Dropped
+	}
+
+	ctx, m.cancel = context.WithCancel(ctx)
+	defer m.cancel()
+
+	err := m.source.Scan(ctx, pinecone.ScanParams{
+		Namespace:     m.report.Source,
+		IncludeValues: true,
+		Concurrency:   m.opts.Concurrency,
+		Cursor:        cursor,
+		Checkpoint:    m.checkpoint,
+	}, func(vector *pinecone.Vector) error {
+		return m.add(ctx, vector)
+	})
+	if err == nil {
+		err = m.dispatch(ctx)
+	}
+
+	m.wg.Wait()
+
+	if m.err != nil {
+		return m.err
+	}
+
+	return err
+}
+
+func (m *migration) add(ctx context.Context, vector *pinecone.Vector) error {
+	if m.opts.Transform != nil {
+		id := vector.ID
+
+		var err error
+		vector, err = m.opts.Transform(ctx, m.report.Source, vector)
+		if err != nil {
+			return fmt.Errorf("failed to transform vector %q: %w", id, err)
+		}
+		if vector == nil {
+			m.mu.Lock()
+			m.report.Dropped++
+			m.mu.Unlock()
+			return nil
+		}
+	}
+
+	m.current = append(m.current, vector)
+	if len(m.current) >= m.opts.BatchSize {
+		return m.dispatch(ctx)
+	}
+
+	return nil
+}
+
+// dispatch upserts the current batch in the background, waiting for a free
+// slot first.
+func (m *migration) dispatch(ctx context.Context) error {
+	vectors := m.current
+	m.current = nil
+	if len(vectors) == 0 {
+		return nil
+	}
+
+	select {
+	case m.sem <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	seq := m.dispatched
+	m.dispatched++
+
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		defer func() { <-m.sem }()
+
+		_, err := m.destination.UpsertVectors(ctx, pinecone.UpsertVectorsParams{Vectors: vectors, Namespace: m.report.Destination})
+		if err != nil {
+			m.fail(fmt.Errorf("failed to upsert into namespace %q: %w", m.report.Destination, err))
+			return
+		}
+
+		m.complete(seq, int64(len(vectors)))
+	}()
+
+	return nil
+}
+
+// checkpoint is called by Scan once a page has been yielded. The cursor is
+// saved once every batch holding vectors of the page, including the one
+// being filled, has completed.
+func (m *migration) checkpoint(cursor string, done bool) error {
+	batches := m.dispatched
+	if len(m.current) > 0 {
+		batches++
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.err != nil {
+		return m.err
+	}
+
+	m.cursors = append(m.cursors, pendingCursor{batches: batches, cursor: cursor, done: done, dropped: m.report.Dropped})
+
+	return m.advanceLocked()
+}
+
+func (m *migration) fail(err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.err == nil {
+		m.err = err
+		m.cancel()
+	}
+}
+
+// complete marks a batch as done, and advances the watermark over every
+// batch completed without gaps since the last one.
+func (m *migration) complete(seq, copied int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.report.Copied += copied
+	m.completed[seq] = true
+	for m.completed[m.watermark] {
+		delete(m.completed, m.watermark)
+		m.watermark++
+	}
+
+	if err := m.advanceLocked(); err != nil && m.err == nil {
+		m.err = err
+		m.cancel()
+		return
+	}
+
+	if m.opts.OnProgress != nil {
+		m.opts.OnProgress(*m.report)
+	}
+}
+
+// advanceLocked saves the last cursor of which every batch has completed.
+func (m *migration) advanceLocked() error {
+	var ready *pendingCursor
+	for len(m.cursors) > 0 && m.cursors[0].batches <= m.watermark {
+		ready = &m.cursors[0]
+		m.cursors = m.cursors[1:]
+	}
+	if ready == nil {
+		return nil
+	}
+
+	m.saved.Namespaces[m.report.Source] = &namespaceCheckpoint{
+		Cursor:  ready.cursor,
+		Done:    ready.done,
+		Copied:  m.report.Copied,
+		Dropped: ready.dropped,
+	}
+	if m.opts.CheckpointPath == "" {
+		return nil
+	}
+
+	if err := saveCheckpoint(m.opts.CheckpointPath, m.saved); err != nil {
+		return fmt.Errorf("failed to save the checkpoint: %w", err)
+	}
+
+	return nil
+}
+
+func saveCheckpoint(path string, saved *checkpoint) error {
+	data, err := json.Marshal(saved)
+	if err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, path)
+}
+
+// verify compares the counts of the namespaces of both indexes, until they
+// match or the timeout expires.
+func verify(ctx context.Context, source, destination *pinecone.IndexClient, report *Report, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		sourceStats, err := source.DescribeIndexStats(ctx, pinecone.DescribeIndexStatsParams{})
+		if err != nil {
+			return err
+		}
+		destinationStats, err := destination.DescribeIndexStats(ctx, pinecone.DescribeIndexStatsParams{})
+		if err != nil {
+			return err
+		}
+
+		var mismatches []string
+		for i := range report.Namespaces {
+			ns := &report.Namespaces[i]
+			ns.SourceCount = vectorCount(sourceStats, ns.Source)
+			ns.DestinationCount = vectorCount(destinationStats, ns.Destination)
+			ns.Verified = ns.DestinationCount == ns.SourceCount-ns.Dropped
+			if !ns.Verified {
+				mismatches = append(mismatches, fmt.Sprintf("%q has %d vectors, expected %d", ns.Destination, ns.DestinationCount, ns.SourceCount-ns.Dropped))
+			}
+		}
+		if len(mismatches) == 0 {
+			return nil
+		}
+		if !time.Now().Before(deadline) {
+			return fmt.Errorf("%w: %v", ErrCountMismatch, mismatches)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(defaultVerifyInterval):
+		}
+	}
+}
+
+func vectorCount(stats *pinecone.DescribeIndexStatsResponse, namespace string) int64 {
+	if count, ok := stats.Namespaces[namespace]; ok && count != nil {
+		return count.VectorCount
+	}
+
+	return 0
+}
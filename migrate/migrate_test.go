@@ -0,0 +1,353 @@
+package migrate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/nekomeowww/go-pinecone"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeIndex struct {
+	mu      sync.Mutex
+	vectors map[string]map[string]*pinecone.Vector
+	upserts atomic.Int32
+	// upsert requests fail with 500 once this many succeeded, when positive
+	failAfter atomic.Int32
+	// added to the counts reported by describe_index_stats
+	countOffset atomic.Int64
+	// upsert requests wait until the channel is closed, when set
+	gate atomic.Pointer[chan struct{}]
+}
+
+func newFakeIndex(t *testing.T) (*fakeIndex, *pinecone.IndexClient) {
+	index := &fakeIndex{vectors: make(map[string]map[string]*pinecone.Vector)}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/describe_index_stats", func(w http.ResponseWriter, r *http.Request) {
+		index.mu.Lock()
+		defer index.mu.Unlock()
+
+		stats := pinecone.DescribeIndexStatsResponse{Dimensions: 2, Namespaces: make(map[string]*pinecone.VectorCount)}
+		for namespace, vectors := range index.vectors {
+			count := int64(len(vectors)) + index.countOffset.Load()
+			stats.Namespaces[namespace] = &pinecone.VectorCount{VectorCount: count}
+			stats.TotalVectorCount += count
+		}
+
+		_ = json.NewEncoder(w).Encode(stats)
+	})
+	mux.HandleFunc("/vectors/upsert", func(w http.ResponseWriter, r *http.Request) {
+		if gate := index.gate.Load(); gate != nil {
+			<-*gate
+		}
+		if failAfter := index.failAfter.Load(); failAfter > 0 && index.upserts.Load() >= failAfter {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		var params pinecone.UpsertVectorsParams
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&params))
+
+		index.mu.Lock()
+		if index.vectors[params.Namespace] == nil {
+			index.vectors[params.Namespace] = make(map[string]*pinecone.Vector)
+		}
+		for _, v := range params.Vectors {
+			index.vectors[params.Namespace][v.ID] = v
+		}
+		index.mu.Unlock()
+		index.upserts.Add(1)
+
+		_ = json.NewEncoder(w).Encode(pinecone.UpsertVectorsResponse{UpsertedCount: len(params.Vectors)})
+	})
+	mux.HandleFunc("/vectors/fetch", func(w http.ResponseWriter, r *http.Request) {
+		namespace := r.URL.Query().Get("namespace")
+
+		index.mu.Lock()
+		defer index.mu.Unlock()
+
+		respBody := pinecone.FetchVectorsResponse{Vectors: make(map[string]*pinecone.Vector), Namespace: namespace}
+		for _, id := range r.URL.Query()["ids"] {
+			if v, ok := index.vectors[namespace][id]; ok {
+				respBody.Vectors[id] = v
+			}
+		}
+
+		_ = json.NewEncoder(w).Encode(respBody)
+	})
+	mux.HandleFunc("/vectors/list", func(w http.ResponseWriter, r *http.Request) {
+		namespace := r.URL.Query().Get("namespace")
+		token := r.URL.Query().Get("paginationToken")
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+		index.mu.Lock()
+		defer index.mu.Unlock()
+
+		ids := make([]string, 0)
+		for id := range index.vectors[namespace] {
+			if id > token {
+				ids = append(ids, id)
+			}
+		}
+		sort.Strings(ids)
+
+		type listed struct {
+			ID string `json:"id"`
+		}
+		respBody := struct {
+			Vectors    []listed `json:"vectors"`
+			Namespace  string   `json:"namespace"`
+			Pagination *struct {
+				Next string `json:"next"`
+			} `json:"pagination,omitempty"`
+		}{Vectors: make([]listed, 0), Namespace: namespace}
+		if limit > 0 && len(ids) > limit {
+			ids = ids[:limit]
+			respBody.Pagination = &struct {
+				Next string `json:"next"`
+			}{Next: ids[limit-1]}
+		}
+		for _, id := range ids {
+			respBody.Vectors = append(respBody.Vectors, listed{ID: id})
+		}
+
+		_ = json.NewEncoder(w).Encode(respBody)
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	ic, err := pinecone.NewIndexClient(pinecone.WithAPIKey("key"), pinecone.WithBaseURL(server.URL), pinecone.WithControllerBaseURL(server.URL))
+	require.NoError(t, err)
+
+	return index, ic
+}
+
+func (f *fakeIndex) put(namespace string, count int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.vectors[namespace] == nil {
+		f.vectors[namespace] = make(map[string]*pinecone.Vector)
+	}
+	for i := 0; i < count; i++ {
+		id := fmt.Sprintf("%s-%03d", namespace, i)
+		f.vectors[namespace][id] = &pinecone.Vector{
+			ID:       id,
+			Values:   []float32{float32(i), 1},
+			Metadata: map[string]any{"i": float64(i), "secret": "x"},
+		}
+	}
+}
+
+func (f *fakeIndex) namespace(namespace string) map[string]*pinecone.Vector {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.vectors[namespace]
+}
+
+func TestMigrate(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	source, sourceClient := newFakeIndex(t)
+	destination, destinationClient := newFakeIndex(t)
+	source.put("a", 250)
+	source.put("b", 30)
+
+	var progressed atomic.Int32
+	report, err := Migrate(context.Background(), sourceClient, destinationClient, Options{
+		Rename:    map[string]string{"a": "renamed"},
+		BatchSize: 40,
+		Transform: func(ctx context.Context, namespace string, vector *pinecone.Vector) (*pinecone.Vector, error) {
+			if int(vector.Values[0])%10 == 0 {
+				return nil, nil
+			}
+
+			delete(vector.Metadata, "secret")
+			vector.Metadata["from"] = namespace
+			return vector, nil
+		},
+		OnProgress: func(NamespaceReport) { progressed.Add(1) },
+	})
+	require.NoError(err)
+	assert.NotZero(progressed.Load())
+	assert.Equal(&Report{Namespaces: []NamespaceReport{
+		{Source: "a", Destination: "renamed", Copied: 225, Dropped: 25, SourceCount: 250, DestinationCount: 225, Verified: true},
+		{Source: "b", Destination: "b", Copied: 27, Dropped: 3, SourceCount: 30, DestinationCount: 27, Verified: true},
+	}}, report)
+
+	renamed := destination.namespace("renamed")
+	require.Len(renamed, 225)
+	assert.Equal(map[string]any{"i": float64(1), "from": "a"}, renamed["a-001"].Metadata)
+	assert.Equal([]float32{1, 1}, renamed["a-001"].Values)
+	assert.Nil(destination.namespace("a"))
+}
+
+func TestMigrate_Verification(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	source, sourceClient := newFakeIndex(t)
+	destination, destinationClient := newFakeIndex(t)
+	source.put("a", 10)
+	destination.countOffset.Store(-1)
+
+	report, err := Migrate(context.Background(), sourceClient, destinationClient, Options{Namespaces: []string{"a"}})
+	require.ErrorIs(err, ErrCountMismatch)
+	require.Len(report.Namespaces, 1)
+	assert.False(report.Namespaces[0].Verified)
+	assert.Equal(int64(10), report.Namespaces[0].Copied)
+	assert.Equal(int64(9), report.Namespaces[0].DestinationCount)
+}
+
+func TestMigrate_Checkpoint(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	source, sourceClient := newFakeIndex(t)
+	destination, destinationClient := newFakeIndex(t)
+	source.put("a", 150)
+	source.put("b", 150)
+
+	checkpointPath := filepath.Join(t.TempDir(), "checkpoint.json")
+	destination.failAfter.Store(5)
+
+	_, err := Migrate(context.Background(), sourceClient, destinationClient, Options{
+		BatchSize:      50,
+		Concurrency:    1,
+		CheckpointPath: checkpointPath,
+	})
+	require.ErrorIs(err, pinecone.ErrRequestFailed)
+
+	data, err := os.ReadFile(checkpointPath)
+	require.NoError(err)
+
+	var saved checkpoint
+	require.NoError(json.Unmarshal(data, &saved))
+	require.Contains(saved.Namespaces, "a")
+	assert.True(saved.Namespaces["a"].Done)
+	require.Contains(saved.Namespaces, "b")
+	assert.False(saved.Namespaces["b"].Done)
+	assert.Equal("b-099", saved.Namespaces["b"].Cursor)
+
+	// the namespace a is not copied again, b resumes from its second page
+	destination.failAfter.Store(0)
+	upserts := destination.upserts.Load()
+
+	report, err := Migrate(context.Background(), sourceClient, destinationClient, Options{
+		BatchSize:      50,
+		Concurrency:    1,
+		CheckpointPath: checkpointPath,
+	})
+	require.NoError(err)
+	assert.Equal(int32(1), destination.upserts.Load()-upserts)
+	assert.Len(destination.namespace("a"), 150)
+	assert.Len(destination.namespace("b"), 150)
+	assert.True(report.Namespaces[0].Verified)
+	assert.True(report.Namespaces[1].Verified)
+	assert.Equal(int64(150), report.Namespaces[1].Copied)
+
+	_, err = os.Stat(checkpointPath)
+	assert.True(errors.Is(err, os.ErrNotExist))
+}
+
+func TestMigrate_CheckpointWithDrops(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	source, sourceClient := newFakeIndex(t)
+	destination, destinationClient := newFakeIndex(t)
+	source.put("a", 300)
+
+	// the odd vectors are dropped, so that the first batch is made of the
+	// first two pages, and completes once the last page was transformed
+	scanned := make(chan struct{})
+	destination.gate.Store(&scanned)
+	var scannedOnce sync.Once
+	transform := func(ctx context.Context, namespace string, vector *pinecone.Vector) (*pinecone.Vector, error) {
+		if vector.ID == "a-299" {
+			scannedOnce.Do(func() { close(scanned) })
+		}
+		if i, _ := strconv.Atoi(vector.ID[2:]); i%2 == 1 {
+			return nil, nil
+		}
+
+		return vector, nil
+	}
+
+	checkpointPath := filepath.Join(t.TempDir(), "checkpoint.json")
+	destination.failAfter.Store(1)
+
+	_, err := Migrate(context.Background(), sourceClient, destinationClient, Options{
+		Transform:      transform,
+		BatchSize:      100,
+		Concurrency:    1,
+		CheckpointPath: checkpointPath,
+	})
+	require.ErrorIs(err, pinecone.ErrRequestFailed)
+
+	data, err := os.ReadFile(checkpointPath)
+	require.NoError(err)
+
+	var saved checkpoint
+	require.NoError(json.Unmarshal(data, &saved))
+	require.Contains(saved.Namespaces, "a")
+	assert.Equal("a-199", saved.Namespaces["a"].Cursor)
+	assert.Equal(int64(100), saved.Namespaces["a"].Dropped)
+
+	// the last page is scanned again, and its drops counted once
+	destination.failAfter.Store(0)
+	report, err := Migrate(context.Background(), sourceClient, destinationClient, Options{
+		Transform:      transform,
+		BatchSize:      100,
+		Concurrency:    1,
+		CheckpointPath: checkpointPath,
+	})
+	require.NoError(err)
+	assert.Len(destination.namespace("a"), 150)
+	assert.Equal(int64(150), report.Namespaces[0].Dropped)
+	assert.True(report.Namespaces[0].Verified)
+}
+
+func TestMigrate_InvalidOptions(t *testing.T) {
+	_, sourceClient := newFakeIndex(t)
+	_, destinationClient := newFakeIndex(t)
+
+	_, err := Migrate(context.Background(), sourceClient, destinationClient, Options{BatchSize: -1})
+	require.ErrorIs(t, err, pinecone.ErrInvalidParams)
+
+	_, err = Migrate(context.Background(), sourceClient, destinationClient, Options{
+		Namespaces: []string{"a", "b"},
+		Rename:     map[string]string{"a": "b"},
+	})
+	require.ErrorIs(t, err, pinecone.ErrInvalidParams)
+}
+
+func TestMigrate_TransformError(t *testing.T) {
+	source, sourceClient := newFakeIndex(t)
+	_, destinationClient := newFakeIndex(t)
+	source.put("a", 10)
+
+	errTransform := errors.New("embedding failed")
+	_, err := Migrate(context.Background(), sourceClient, destinationClient, Options{
+		Transform: func(ctx context.Context, namespace string, vector *pinecone.Vector) (*pinecone.Vector, error) {
+			return nil, errTransform
+		},
+	})
+	require.ErrorIs(t, err, errTransform)
+}
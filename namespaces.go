@@ -0,0 +1,197 @@
+package pinecone
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/samber/lo"
+)
+
+// PartialFailureMode controls how failures of individual namespace queries
+// are handled by QueryNamespaces.
+type PartialFailureMode int
+
+const (
+	// PartialFailureModeFailFast fails the whole request as soon as any
+	// namespace query fails. This is the default.
+	PartialFailureModeFailFast PartialFailureMode = iota
+	// PartialFailureModeAllowPartial returns the merged matches of the
+	// namespaces that succeeded, and reports the failed ones in
+	// QueryNamespacesResponse.Errors. The request only fails if every
+	// namespace query fails.
+	PartialFailureModeAllowPartial
+)
+
+// QueryNamespacesParams represents the parameters for a cross-namespace query request.
+type QueryNamespacesParams struct {
+	// The query to run against every namespace. QueryParams.Namespace is ignored.
+	QueryParams
+	// The distance metric of the index, used to order the merged matches.
+	// Defaults to the metric set with WithMetric, or else to the metric
+	// learned from DescribeIndex when the index name is known, failing the
+	// query when it cannot be described. Clients that only know the host of
+	// the index default to cosine, as DescribeIndexStats does not report the
+	// metric.
+	Metric CreateIndexMetric
+	// How failures of individual namespace queries are handled.
+	PartialFailureMode PartialFailureMode
+	// The maximum number of namespaces queried concurrently. Zero or less
+	// means all namespaces are queried at once.
+	MaxConcurrency int
+}
+
+// NamespaceQueryVector represents a scored vector annotated with the namespace it came from.
+type NamespaceQueryVector struct {
+	QueryVector
+	Namespace string `json:"namespace"`
+}
+
+// QueryNamespacesResponse represents the response from a cross-namespace query request.
+type QueryNamespacesResponse struct {
+	// The global top-K matches across all namespaces, ordered by the index metric.
+	Matches []*NamespaceQueryVector `json:"matches"`
+	// The errors of the namespaces that failed, keyed by namespace. Only
+	// populated with PartialFailureModeAllowPartial.
+	Errors map[string]error `json:"-"`
+}
+
+// QueryNamespaces runs the same query against each of the given namespaces
+// concurrently, and merges the matches into a global top-K ordered by the
+// index metric.
+func (ic *IndexClient) QueryNamespaces(ctx context.Context, namespaces []string, params QueryNamespacesParams) (*QueryNamespacesResponse, error) {
+	if err := validateQueryNamespacesParams(namespaces, params); err != nil {
+		return nil, err
+	}
+
+	namespaces = lo.Uniq(namespaces)
+
+	if params.Metric == "" {
+		metric, err := ic.metric(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to learn the index metric: %w", err)
+		}
+
+		params.Metric = metric
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	concurrency := params.MaxConcurrency
+	if concurrency <= 0 || concurrency > len(namespaces) {
+		concurrency = len(namespaces)
+	}
+
+	responses := make([]*QueryResponse, len(namespaces))
+	errs := make([]error, len(namespaces))
+	semaphore := make(chan struct{}, concurrency)
+
+	var wg sync.WaitGroup
+	for i, namespace := range namespaces {
+		wg.Add(1)
+		go func(i int, namespace string) {
+			defer wg.Done()
+
+			select {
+			case semaphore <- struct{}{}:
+				defer func() { <-semaphore }()
+			case <-ctx.Done():
+				errs[i] = ctx.Err()
+				return
+			}
+
+			queryParams := params.QueryParams
+			queryParams.Namespace = namespace
+
+			resp, err := ic.Query(ctx, queryParams)
+			if err != nil {
+				errs[i] = err
+				if params.PartialFailureMode == PartialFailureModeFailFast {
+					cancel()
+				}
+				return
+			}
+
+			responses[i] = resp
+		}(i, namespace)
+	}
+
+	wg.Wait()
+
+	respBody := &QueryNamespacesResponse{
+		Matches: make([]*NamespaceQueryVector, 0),
+		Errors:  make(map[string]error),
+	}
+
+	if params.PartialFailureMode == PartialFailureModeFailFast {
+		if i, ok := firstQueryNamespacesError(errs); ok {
+			return nil, fmt.Errorf("failed to query namespace %q: %w", namespaces[i], errs[i])
+		}
+	}
+
+	for i, namespace := range namespaces {
+		if errs[i] != nil {
+			respBody.Errors[namespace] = errs[i]
+			continue
+		}
+
+		for _, match := range responses[i].Matches {
+			if match == nil {
+				continue
+			}
+
+			respBody.Matches = append(respBody.Matches, &NamespaceQueryVector{
+				QueryVector: *match,
+				Namespace:   namespace,
+			})
+		}
+	}
+
+	if len(respBody.Errors) == len(namespaces) {
+		return nil, fmt.Errorf("all namespace queries failed: %w", errs[0])
+	}
+
+	sort.SliceStable(respBody.Matches, func(i, j int) bool {
+		return scoreLess(params.Metric, respBody.Matches[j].Score, respBody.Matches[i].Score)
+	})
+
+	if int64(len(respBody.Matches)) > params.TopK {
+		respBody.Matches = respBody.Matches[:params.TopK]
+	}
+
+	return respBody, nil
+}
+
+// firstQueryNamespacesError returns the index of the first error that is
+// not caused by the cancellation of the other namespace queries, falling back
+// to the first error of any kind.
+func firstQueryNamespacesError(errs []error) (int, bool) {
+	first := -1
+	for i, err := range errs {
+		if err == nil {
+			continue
+		}
+		if first < 0 {
+			first = i
+		}
+		if !errors.Is(err, context.Canceled) {
+			return i, true
+		}
+	}
+
+	return first, first >= 0
+}
+
+// scoreLess reports whether score a ranks lower (is less similar) than
+// score b for the given metric. For euclidean a lower score means a closer
+// match, for cosine and dotproduct a higher score does.
+func scoreLess(metric CreateIndexMetric, a, b float32) bool {
+	if metric == CreateIndexMetricEuclidean {
+		return a > b
+	}
+
+	return a < b
+}
@@ -0,0 +1,143 @@
+package pinecone
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newQueryNamespacesTestServer(t *testing.T, matches map[string][]*QueryVector) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var params QueryParams
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&params))
+
+		namespaceMatches, ok := matches[params.Namespace]
+		if !ok {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte("namespace unavailable"))
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(QueryResponse{Matches: namespaceMatches, Namespace: params.Namespace})
+	}))
+}
+
+func TestQueryNamespaces(t *testing.T) {
+	matches := map[string][]*QueryVector{
+		"a": {
+			{Vector: Vector{ID: "a1"}, Score: 0.9},
+			{Vector: Vector{ID: "a2"}, Score: 0.3},
+		},
+		"b": {
+			{Vector: Vector{ID: "b1"}, Score: 0.7},
+			{Vector: Vector{ID: "b2"}, Score: 0.1},
+		},
+	}
+
+	server := newQueryNamespacesTestServer(t, matches)
+	defer server.Close()
+
+	ic := newTestIndexClient(server.URL)
+
+	t.Run("Cosine", func(t *testing.T) {
+		assert := assert.New(t)
+		require := require.New(t)
+
+		resp, err := ic.QueryNamespaces(context.Background(), []string{"a", "b"}, QueryNamespacesParams{
+			QueryParams: QueryParams{TopK: 3, Vector: []float32{1, 0}},
+		})
+		require.NoError(err)
+		require.Len(resp.Matches, 3)
+		assert.Equal("a1", resp.Matches[0].ID)
+		assert.Equal("a", resp.Matches[0].Namespace)
+		assert.Equal("b1", resp.Matches[1].ID)
+		assert.Equal("b", resp.Matches[1].Namespace)
+		assert.Equal("a2", resp.Matches[2].ID)
+		assert.Empty(resp.Errors)
+	})
+
+	t.Run("Euclidean", func(t *testing.T) {
+		assert := assert.New(t)
+		require := require.New(t)
+
+		resp, err := ic.QueryNamespaces(context.Background(), []string{"a", "b"}, QueryNamespacesParams{
+			QueryParams: QueryParams{TopK: 2, Vector: []float32{1, 0}},
+			Metric:      CreateIndexMetricEuclidean,
+		})
+		require.NoError(err)
+		require.Len(resp.Matches, 2)
+		assert.Equal("b2", resp.Matches[0].ID)
+		assert.Equal("a2", resp.Matches[1].ID)
+	})
+
+	t.Run("IndexMetric", func(t *testing.T) {
+		mux := http.NewServeMux()
+		mux.HandleFunc("/databases/euclidean-index", func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewEncoder(w).Encode(DescribeIndexResponse{Database: Database{Name: "euclidean-index", Metric: "euclidean", Dimension: 2}})
+		})
+		mux.Handle("/", server.Config.Handler)
+		euclideanServer := httptest.NewServer(mux)
+		defer euclideanServer.Close()
+
+		for name, ic := range map[string]*IndexClient{
+			"DescribeIndex": newTestIndexClient(euclideanServer.URL, WithIndexName("euclidean-index")),
+			"WithMetric":    newTestIndexClient(server.URL, WithMetric(CreateIndexMetricEuclidean)),
+		} {
+			t.Run(name, func(t *testing.T) {
+				resp, err := ic.QueryNamespaces(context.Background(), []string{"a", "b"}, QueryNamespacesParams{
+					QueryParams: QueryParams{TopK: 2, Vector: []float32{1, 0}},
+				})
+				require.NoError(t, err)
+				require.Len(t, resp.Matches, 2)
+				assert.Equal(t, "b2", resp.Matches[0].ID)
+				assert.Equal(t, "a2", resp.Matches[1].ID)
+			})
+		}
+	})
+
+	t.Run("FailFast", func(t *testing.T) {
+		assert := assert.New(t)
+		require := require.New(t)
+
+		resp, err := ic.QueryNamespaces(context.Background(), []string{"a", "missing"}, QueryNamespacesParams{
+			QueryParams: QueryParams{TopK: 2, Vector: []float32{1, 0}},
+		})
+		require.Error(err)
+		assert.Nil(resp)
+		assert.ErrorIs(err, ErrRequestFailed)
+		assert.Contains(err.Error(), `"missing"`)
+	})
+
+	t.Run("AllowPartial", func(t *testing.T) {
+		assert := assert.New(t)
+		require := require.New(t)
+
+		resp, err := ic.QueryNamespaces(context.Background(), []string{"a", "missing"}, QueryNamespacesParams{
+			QueryParams:        QueryParams{TopK: 5, Vector: []float32{1, 0}},
+			PartialFailureMode: PartialFailureModeAllowPartial,
+		})
+		require.NoError(err)
+		assert.Len(resp.Matches, 2)
+		require.Contains(resp.Errors, "missing")
+		assert.ErrorIs(resp.Errors["missing"], ErrRequestFailed)
+
+		_, err = ic.QueryNamespaces(context.Background(), []string{"missing"}, QueryNamespacesParams{
+			QueryParams:        QueryParams{TopK: 5, Vector: []float32{1, 0}},
+			PartialFailureMode: PartialFailureModeAllowPartial,
+		})
+		require.Error(err)
+		assert.ErrorIs(err, ErrRequestFailed)
+	})
+
+	t.Run("InvalidParams", func(t *testing.T) {
+		_, err := ic.QueryNamespaces(context.Background(), nil, QueryNamespacesParams{
+			QueryParams: QueryParams{TopK: 1, Vector: []float32{1, 0}},
+		})
+		require.ErrorIs(t, err, ErrInvalidParams)
+	})
+}
@@ -0,0 +1,138 @@
+package pinecone
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+var (
+	// ErrZeroVector is returned when a vector to normalize has only zero values.
+	ErrZeroVector = errors.New("zero vector")
+	// ErrNonFiniteVector is returned when a vector to normalize has NaN or infinite values.
+	ErrNonFiniteVector = errors.New("non-finite vector")
+)
+
+// Normalization is the normalization applied to dense values before they
+// are sent to the index.
+type Normalization string
+
+const (
+	// NormalizationL2 scales dense values to unit length.
+	NormalizationL2 Normalization = "l2"
+)
+
+// normalizationTolerance is how far from unit length the norm of a vector
+// can be before it is adjusted, to absorb float32 rounding.
+const normalizationTolerance = 1e-5
+
+// WithVectorNormalization makes IndexClient normalize the dense values of
+// UpsertVectors, UpdateVector and Query before they are sent, which keeps
+// scores of dotproduct indexes consistent when the embeddings are not
+// exactly unit length. Zero vectors are rejected with ErrZeroVector, and
+// vectors with NaN or infinite values with ErrNonFiniteVector. The values
+// passed by the caller are never modified. NewIndexClient fails with
+// ErrInvalidParams on an unknown normalization.
+func WithVectorNormalization(normalization Normalization) CallOptions {
+	return CallOptions{
+		applyFunc: func(o *options) {
+			o.normalization = normalization
+		},
+	}
+}
+
+// validate checks that the normalization is known, the empty normalization
+// standing for none.
+func (n Normalization) validate() error {
+	switch n {
+	case "", NormalizationL2:
+		return nil
+	default:
+		return fmt.Errorf("%w: unknown vector normalization %q", ErrInvalidParams, n)
+	}
+}
+
+// NormalizationStats represents the number of vectors handled by vector
+// normalization since the IndexClient was created.
+type NormalizationStats struct {
+	// The number of vectors whose values were adjusted, counted once the
+	// request sending them succeeded. Queries served from the query cache
+	// are not sent, and not counted.
+	Adjusted int64
+	// The number of vectors rejected for being zero or non-finite.
+	Rejected int64
+}
+
+// NormalizationStats returns the number of vectors adjusted and rejected by
+// vector normalization so far.
+func (ic *IndexClient) NormalizationStats() NormalizationStats {
+	return NormalizationStats{
+		Adjusted: ic.normalizationAdjusted.Load(),
+		Rejected: ic.normalizationRejected.Load(),
+	}
+}
+
+// normalizeVectors returns the vectors with normalized values, and the
+// number of vectors adjusted. Vectors that need adjusting are copied, the
+// others are returned as is.
+func (ic *IndexClient) normalizeVectors(vectors []*Vector) ([]*Vector, int64, error) {
+	if ic.options == nil || ic.options.normalization == "" {
+		return vectors, 0, nil
+	}
+
+	var adjustedCount int64
+	normalized := make([]*Vector, len(vectors))
+	for i, v := range vectors {
+		values, adjusted, err := ic.normalizeValues(v.ID, v.Values)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		normalized[i] = v
+		if adjusted {
+			copied := *v
+			copied.Values = values
+			normalized[i] = &copied
+			adjustedCount++
+		}
+	}
+
+	return normalized, adjustedCount, nil
+}
+
+// normalizeValues returns the dense values normalized to unit length, and
+// whether they were adjusted. The values themselves are returned when
+// normalization is disabled or they already have unit length. Rejected
+// values are counted right away, adjusted ones are left to the caller to
+// count once they are sent.
+func (ic *IndexClient) normalizeValues(id string, values []float32) ([]float32, bool, error) {
+	if ic.options == nil || ic.options.normalization == "" || len(values) == 0 {
+		return values, false, nil
+	}
+
+	var sum float64
+	for i, v := range values {
+		if math.IsNaN(float64(v)) || math.IsInf(float64(v), 0) {
+			ic.normalizationRejected.Add(1)
+			return nil, false, fmt.Errorf("%w: %w: vector %q has value %v at position %d", ErrInvalidParams, ErrNonFiniteVector, id, v, i)
+		}
+
+		sum += float64(v) * float64(v)
+	}
+	if sum == 0 {
+		ic.normalizationRejected.Add(1)
+		return nil, false, fmt.Errorf("%w: %w: vector %q cannot be normalized", ErrInvalidParams, ErrZeroVector, id)
+	}
+
+	norm := math.Sqrt(sum)
+	if math.Abs(norm-1) <= normalizationTolerance {
+		return values, false, nil
+	}
+
+	normalized := make([]float32, len(values))
+	for i, v := range values {
+		normalized[i] = float32(float64(v) / norm)
+	}
+
+	return normalized, true, nil
+}
@@ -0,0 +1,149 @@
+package pinecone
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndexClient_VectorNormalization(t *testing.T) {
+	t.Run("normalizes values", func(t *testing.T) {
+		assert := assert.New(t)
+		require := require.New(t)
+
+		index, server := newFakeIndexServer(t)
+		defer server.Close()
+
+		ic := newTestIndexClient(server.URL, WithVectorNormalization(NormalizationL2))
+
+		values := []float32{3, 4}
+		_, err := ic.UpsertVectors(context.Background(), UpsertVectorsParams{
+			Vectors: []*Vector{{ID: "a", Values: values}, {ID: "b", Values: []float32{0, 1}}},
+		})
+		require.NoError(err)
+		assert.Equal([]float32{3, 4}, values)
+
+		index.mu.Lock()
+		assert.InDeltaSlice([]float32{0.6, 0.8}, index.vectors[""]["a"].Values, 1e-6)
+		assert.Equal([]float32{0, 1}, index.vectors[""]["b"].Values)
+		index.mu.Unlock()
+
+		require.NoError(ic.UpdateVector(context.Background(), UpdateVectorParams{ID: "b", Values: []float32{0, 2}}))
+
+		index.mu.Lock()
+		assert.InDeltaSlice([]float32{0, 1}, index.vectors[""]["b"].Values, 1e-6)
+		index.mu.Unlock()
+
+		resp, err := ic.Query(context.Background(), QueryParams{TopK: 1, Vector: []float32{10, 0}})
+		require.NoError(err)
+		require.Len(resp.Matches, 1)
+		assert.Equal("a", resp.Matches[0].ID)
+		assert.InDelta(0.6, resp.Matches[0].Score, 1e-6)
+
+		assert.Equal(NormalizationStats{Adjusted: 3}, ic.NormalizationStats())
+	})
+
+	t.Run("rejects zero and non-finite vectors", func(t *testing.T) {
+		assert := assert.New(t)
+		require := require.New(t)
+
+		index, server := newFakeIndexServer(t)
+		defer server.Close()
+
+		ic := newTestIndexClient(server.URL, WithVectorNormalization(NormalizationL2))
+
+		_, err := ic.UpsertVectors(context.Background(), UpsertVectorsParams{
+			Vectors: []*Vector{{ID: "a", Values: []float32{0, 0}}},
+		})
+		require.ErrorIs(err, ErrZeroVector)
+		require.ErrorIs(err, ErrInvalidParams)
+
+		_, err = ic.Query(context.Background(), QueryParams{TopK: 1, Vector: []float32{float32(math.Inf(-1)), 0}})
+		require.ErrorIs(err, ErrNonFiniteVector)
+
+		err = ic.UpdateVector(context.Background(), UpdateVectorParams{ID: "a", Values: []float32{float32(math.NaN()), 1}})
+		require.ErrorIs(err, ErrNonFiniteVector)
+
+		assert.Zero(index.count(""))
+		assert.Equal(NormalizationStats{Rejected: 3}, ic.NormalizationStats())
+	})
+
+	t.Run("counts adjusted vectors once sent", func(t *testing.T) {
+		assert := assert.New(t)
+		require := require.New(t)
+
+		_, server := newFakeIndexServer(t)
+		defer server.Close()
+		handler := server.Config.Handler
+		server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/vectors/upsert" {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			handler.ServeHTTP(w, r)
+		})
+
+		ic := newTestIndexClient(server.URL, WithVectorNormalization(NormalizationL2))
+
+		_, err := ic.UpsertVectors(context.Background(), UpsertVectorsParams{
+			Vectors: []*Vector{{ID: "a", Values: []float32{3, 4}}},
+		})
+		require.ErrorIs(err, ErrRequestFailed)
+		assert.Equal(NormalizationStats{}, ic.NormalizationStats())
+
+		_, err = ic.UpsertVectors(context.Background(), UpsertVectorsParams{
+			Vectors: []*Vector{{ID: "a", Values: []float32{3, 4}}, {ID: "b", Values: []float32{0, 0}}},
+		})
+		require.ErrorIs(err, ErrZeroVector)
+		assert.Equal(NormalizationStats{Rejected: 1}, ic.NormalizationStats())
+	})
+
+	t.Run("does not count cache hits", func(t *testing.T) {
+		assert := assert.New(t)
+		require := require.New(t)
+
+		_, server := newFakeIndexServer(t)
+		defer server.Close()
+		var requests requestCounter
+		server.Config.Handler = requests.wrap(server.Config.Handler)
+
+		ic := newTestIndexClient(server.URL, WithVectorNormalization(NormalizationL2), WithQueryCache(NewLRUCache(10, 0), time.Minute))
+
+		for i := 0; i < 2; i++ {
+			_, err := ic.Query(context.Background(), QueryParams{TopK: 1, Vector: []float32{3, 4}})
+			require.NoError(err)
+		}
+		assert.Equal(1, requests.take("/query"))
+		assert.Equal(NormalizationStats{Adjusted: 1}, ic.NormalizationStats())
+	})
+
+	t.Run("rejects unknown normalizations", func(t *testing.T) {
+		_, err := NewIndexClient(WithBaseURL("http://localhost"), WithVectorNormalization("l1"))
+		require.ErrorIs(t, err, ErrInvalidParams)
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		assert := assert.New(t)
+		require := require.New(t)
+
+		index, server := newFakeIndexServer(t)
+		defer server.Close()
+
+		ic := newTestIndexClient(server.URL)
+
+		_, err := ic.UpsertVectors(context.Background(), UpsertVectorsParams{
+			Vectors: []*Vector{{ID: "a", Values: []float32{3, 4}}},
+		})
+		require.NoError(err)
+
+		index.mu.Lock()
+		assert.Equal([]float32{3, 4}, index.vectors[""]["a"].Values)
+		index.mu.Unlock()
+		assert.Equal(NormalizationStats{}, ic.NormalizationStats())
+	})
+}
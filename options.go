@@ -5,6 +5,17 @@ type options struct {
 	environment string
 	projectName string
 	indexName   string
+	baseURL     string
+	// controllerBaseURL overrides the base URL of the controller API.
+	controllerBaseURL string
+	metric            CreateIndexMetric
+	vnodes            int
+	embedder          Embedder
+	cache             *queryCache
+	dimension         int
+	validateDimension bool
+	strictValues      bool
+	normalization     Normalization
 }
 
 type CallOptions struct {
@@ -58,3 +69,88 @@ func WithIndexName(indexName string) CallOptions {
 		},
 	}
 }
+
+// WithBaseURL overrides the base URL the client talks to, e.g. the host of a
+// serverless index or a local server in tests.
+func WithBaseURL(baseURL string) CallOptions {
+	return CallOptions{
+		applyFunc: func(o *options) {
+			o.baseURL = baseURL
+		},
+	}
+}
+
+// WithControllerBaseURL overrides the base URL of the controller API, which
+// serves the index operations. It takes precedence over WithBaseURL for Client,
+// and is the only way to override the controller used by IndexClient. It
+// also overrides the global control plane serving backups and restore jobs.
+func WithControllerBaseURL(baseURL string) CallOptions {
+	return CallOptions{
+		applyFunc: func(o *options) {
+			o.controllerBaseURL = baseURL
+		},
+	}
+}
+
+// WithMetric sets the distance metric of the index, used to order merged
+// query results.
+func WithMetric(metric CreateIndexMetric) CallOptions {
+	return CallOptions{
+		applyFunc: func(o *options) {
+			o.metric = metric
+		},
+	}
+}
+
+// WithVirtualNodes sets the number of virtual nodes each shard occupies on
+// the consistent hash ring of a ShardedIndex.
+func WithVirtualNodes(vnodes int) CallOptions {
+	return CallOptions{
+		applyFunc: func(o *options) {
+			o.vnodes = vnodes
+		},
+	}
+}
+
+// WithEmbedder sets the embedder used by the text-level helpers of
+// IndexClient, such as UpsertTexts and QueryText.
+func WithEmbedder(embedder Embedder) CallOptions {
+	return CallOptions{
+		applyFunc: func(o *options) {
+			o.embedder = embedder
+		},
+	}
+}
+
+// WithDimension sets the dimension of the index, so that it does not need to
+// be learned when dimension validation is enabled.
+func WithDimension(dimension int) CallOptions {
+	return CallOptions{
+		applyFunc: func(o *options) {
+			o.dimension = dimension
+		},
+	}
+}
+
+// WithDimensionValidation makes IndexClient reject dense values whose length
+// does not match the dimension of the index, before anything is sent. The
+// dimension is set with WithDimension, or learned once from DescribeIndex
+// when the index name is known, or from DescribeIndexStats otherwise.
+func WithDimensionValidation() CallOptions {
+	return CallOptions{
+		applyFunc: func(o *options) {
+			o.validateDimension = true
+		},
+	}
+}
+
+// WithStrictValidation enables dimension validation, and additionally makes
+// IndexClient reject dense and sparse values that are NaN or infinite.
+func WithStrictValidation() CallOptions {
+	return CallOptions{
+		applyFunc: func(o *options) {
+			o.validateDimension = true
+			o.strictValues = true
+		},
+	}
+}
@@ -6,28 +6,66 @@ import (
 	"github.com/imroc/req/v3"
 )
 
+const (
+	// defaultAPIBaseURL is the base URL of the global APIs of Pinecone, such
+	// as the control plane and inference.
+	defaultAPIBaseURL = "https://api.pinecone.io"
+	// apiVersion is the version of the Pinecone API requested by every
+	// client of the package.
+	apiVersion = "2025-04"
+)
+
 // Client is the main entry point for the Pinecone API.
 type Client struct {
 	options   *options
 	reqClient *req.Client
+	// the global control plane, serving the APIs of serverless indexes such as backups
+	controlPlane *req.Client
 }
 
 // New creates a new Pinecone client.
 func New(callOpts ...CallOptions) (*Client, error) {
-	opts := applyCallOptions(callOpts)
+	return newClient(applyCallOptions(callOpts)), nil
+}
+
+func newClient(opts *options) *Client {
+	baseURL := opts.controllerBaseURL
+	if baseURL == "" {
+		baseURL = opts.baseURL
+	}
+	if baseURL == "" {
+		baseURL = fmt.Sprintf("https://controller.%s.pinecone.io", opts.environment)
+	}
+
+	controlPlaneBaseURL := opts.controllerBaseURL
+	if controlPlaneBaseURL == "" {
+		controlPlaneBaseURL = opts.baseURL
+	}
+	if controlPlaneBaseURL == "" {
+		controlPlaneBaseURL = defaultAPIBaseURL
+	}
+
 	reqClient := req.
 		C().
-		SetBaseURL(fmt.Sprintf("https://controller.%s.pinecone.io", opts.environment)).
+		SetBaseURL(baseURL).
 		SetCommonHeader("Api-Key", opts.apiKey)
+	controlPlane := req.
+		C().
+		SetBaseURL(controlPlaneBaseURL).
+		SetCommonHeader("Api-Key", opts.apiKey).
+		SetCommonHeader("X-Pinecone-API-Version", apiVersion)
 	return &Client{
-		options:   opts,
-		reqClient: reqClient,
-	}, nil
+		options:      opts,
+		reqClient:    reqClient,
+		controlPlane: controlPlane,
+	}
 }
 
 // Debug enables debug logging and http dump for the client.
 func (c *Client) Debug() *Client {
 	c.reqClient.DebugLog = true
 	c.reqClient = c.reqClient.EnableDumpAll()
+	c.controlPlane.DebugLog = true
+	c.controlPlane = c.controlPlane.EnableDumpAll()
 	return c
 }
@@ -9,10 +9,19 @@ const (
 	EnvTestPineconeAPIKey = "TEST_PINECONE_API_KEY"
 )
 
-func TestMain(m *testing.M) {
+// skipWithoutAPIKey skips the live tests against the Pinecone API when no API
+// key is set, so that the tests against local servers run without one.
+func skipWithoutAPIKey(t *testing.T) {
+	t.Helper()
+
 	if os.Getenv(EnvTestPineconeAPIKey) == "" {
-		panic("TEST_PINECONE_API_KEY environment variable is not set")
+		t.Skipf("%s environment variable is not set", EnvTestPineconeAPIKey)
 	}
+}
 
-	os.Exit(m.Run())
+// newTestIndexClient creates an index client that talks to the given local
+// test server instead of the Pinecone API, for both vector and index operations.
+func newTestIndexClient(baseURL string, opts ...CallOptions) *IndexClient {
+	ic, _ := NewIndexClient(append([]CallOptions{WithBaseURL(baseURL), WithControllerBaseURL(baseURL)}, opts...)...)
+	return ic
 }
@@ -0,0 +1,244 @@
+// Package reconcile compares a namespace with the source of truth it is
+// derived from, e.g. a database table, reports how they drifted apart, and
+// optionally repairs the namespace.
+package reconcile
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/nekomeowww/go-pinecone"
+)
+
+const (
+	// DefaultHashKey is the metadata key the content hash of a vector is
+	// read from by default, the one written by UpsertIfChanged.
+	DefaultHashKey = pinecone.ContentHashMetadataKey
+
+	defaultBatchSize = 100
+)
+
+// Record represents a record of the source of truth.
+type Record struct {
+	// Required. The ID of the vector of the record.
+	ID string
+	// The hash of the content of the record, compared with the hash stored
+	// in the metadata of its vector to find stale vectors, e.g. the
+	// pinecone.ContentHash of the vector. Staleness is not checked when
+	// empty.
+	Hash string
+}
+
+// SourceOfTruth is the source the vectors of a namespace are derived from.
+type SourceOfTruth interface {
+	// Records calls fn with every record of the source, and stops at the
+	// first error returned by fn.
+	Records(ctx context.Context, fn func(record Record) error) error
+	// Vectors returns the vectors of the records with the given IDs, with
+	// their content hash in the metadata. Records no longer in the source
+	// are left out. Only called to repair a namespace.
+	Vectors(ctx context.Context, ids []string) ([]*pinecone.Vector, error)
+}
+
+// Options represents the options of a reconciliation.
+type Options struct {
+	// The metadata key holding the content hash of the vectors. Defaults to
+	// DefaultHashKey.
+	HashKey string
+	// Whether the missing and stale vectors are upserted from the source, and
+	// the orphaned vectors deleted.
+	Repair bool
+	// Whether the repair is only planned: the vectors are still loaded from
+	// the source and counted, but nothing is written to the namespace.
+	DryRun bool
+	// The number of vectors per upsert and delete request. Defaults to 100.
+	BatchSize int
+}
+
+// Report represents the differences between a namespace and its source of
+// truth. IDs are sorted.
+type Report struct {
+	Namespace string `json:"namespace"`
+	// The IDs of the records without a vector.
+	Missing []string `json:"missing"`
+	// The IDs of the vectors without a record.
+	Orphaned []string `json:"orphaned"`
+	// The IDs of the vectors of which the content hash differs from the one
+	// of their record.
+	Stale []string `json:"stale"`
+	// The number of vectors matching their record.
+	InSync int64 `json:"inSync"`
+
+	// The number of vectors upserted and deleted by the repair, or that
+	// would be in a dry run.
+	Upserted int64 `json:"upserted"`
+	Deleted  int64 `json:"deleted"`
+	DryRun   bool  `json:"dryRun"`
+}
+
+// Drifted reports whether the namespace differs from its source of truth.
+func (r *Report) Drifted() bool {
+	return len(r.Missing) > 0 || len(r.Orphaned) > 0 || len(r.Stale) > 0
+}
+
+// Reconcile compares the IDs of a namespace with the records of its source
+// of truth, and the content hashes of the vectors when the records have one.
+// The IDs are only listed when no record has a hash, otherwise every vector
+// is scanned for its metadata.
+//
+// The records of the source are held in memory, and changes made to either
+// side while reconciling may be reported as drift.
+func Reconcile(ctx context.Context, ic *pinecone.IndexClient, namespace string, source SourceOfTruth, opts Options) (*Report, error) {
+	if opts.BatchSize < 0 {
+		return nil, fmt.Errorf("%w: batch size must not be negative", pinecone.ErrInvalidParams)
+	}
+	if opts.BatchSize == 0 {
+		opts.BatchSize = defaultBatchSize
+	}
+	if opts.HashKey == "" {
+		opts.HashKey = DefaultHashKey
+	}
+
+	records := make(map[string]string)
+	hashed := false
+	err := source.Records(ctx, func(record Record) error {
+		if record.ID == "" {
+			return fmt.Errorf("%w: record id is required", pinecone.ErrInvalidParams)
+		}
+
+		records[record.ID] = record.Hash
+		hashed = hashed || record.Hash != ""
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the source of truth: %w", err)
+	}
+
+	report := &Report{
+		Namespace: namespace,
+		Missing:   make([]string, 0),
+		Orphaned:  make([]string, 0),
+		Stale:     make([]string, 0),
+		DryRun:    opts.DryRun,
+	}
+
+	seen := make(map[string]bool, len(records))
+	compare := func(id string, metadata map[string]any) {
+		hash, ok := records[id]
+		if !ok {
+			report.Orphaned = append(report.Orphaned, id)
+			return
+		}
+
+		seen[id] = true
+		if hash != "" {
+			if stored, _ := metadata[opts.HashKey].(string); stored != hash {
+				report.Stale = append(report.Stale, id)
+				return
+			}
+		}
+
+		report.InSync++
+	}
+
+	if hashed {
+		err = ic.Scan(ctx, pinecone.ScanParams{Namespace: namespace}, func(vector *pinecone.Vector) error {
+			compare(vector.ID, vector.Metadata)
+			return nil
+		})
+	} else {
+		err = listIDs(ctx, ic, namespace, func(id string) {
+			compare(id, nil)
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for id := range records {
+		if !seen[id] {
+			report.Missing = append(report.Missing, id)
+		}
+	}
+
+	sort.Strings(report.Missing)
+	sort.Strings(report.Orphaned)
+	sort.Strings(report.Stale)
+
+	if opts.Repair {
+		if err := repair(ctx, ic, source, report, opts); err != nil {
+			return report, err
+		}
+	}
+
+	return report, nil
+}
+
+func listIDs(ctx context.Context, ic *pinecone.IndexClient, namespace string, fn func(id string)) error {
+	var paginationToken string
+	for {
+		page, err := ic.ListVectorIDs(ctx, pinecone.ListVectorIDsParams{
+			Namespace:       namespace,
+			Limit:           pinecone.MaxListVectorIDsLimit,
+			PaginationToken: paginationToken,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, id := range page.IDs {
+			fn(id)
+		}
+
+		paginationToken = page.NextPaginationToken
+		if paginationToken == "" {
+			return nil
+		}
+	}
+}
+
+// repair upserts the missing and stale vectors from the source, then
+// deletes the orphaned ones.
+func repair(ctx context.Context, ic *pinecone.IndexClient, source SourceOfTruth, report *Report, opts Options) error {
+	ids := make([]string, 0, len(report.Missing)+len(report.Stale))
+	ids = append(ids, report.Missing...)
+	ids = append(ids, report.Stale...)
+
+	for start := 0; start < len(ids); start += opts.BatchSize {
+		end := min(start+opts.BatchSize, len(ids))
+
+		vectors, err := source.Vectors(ctx, ids[start:end])
+		if err != nil {
+			return fmt.Errorf("failed to load vectors from the source of truth: %w", err)
+		}
+		if len(vectors) == 0 {
+			continue
+		}
+
+		if !opts.DryRun {
+			_, err = ic.UpsertVectors(ctx, pinecone.UpsertVectorsParams{Vectors: vectors, Namespace: report.Namespace})
+			if err != nil {
+				return fmt.Errorf("failed to upsert vectors: %w", err)
+			}
+		}
+
+		report.Upserted += int64(len(vectors))
+	}
+
+	for start := 0; start < len(report.Orphaned); start += opts.BatchSize {
+		end := min(start+opts.BatchSize, len(report.Orphaned))
+
+		if !opts.DryRun {
+			err := ic.DeleteVectors(ctx, pinecone.DeleteVectorsParams{IDs: report.Orphaned[start:end], Namespace: report.Namespace})
+			if err != nil {
+				return fmt.Errorf("failed to delete vectors: %w", err)
+			}
+		}
+
+		report.Deleted += int64(end - start)
+	}
+
+	return nil
+}
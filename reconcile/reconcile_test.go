@@ -0,0 +1,265 @@
+package reconcile
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/nekomeowww/go-pinecone"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeIndex struct {
+	mu      sync.Mutex
+	vectors map[string]*pinecone.Vector
+	writes  atomic.Int32
+	fetches atomic.Int32
+}
+
+func newFakeIndex(t *testing.T, vectors ...*pinecone.Vector) (*fakeIndex, *pinecone.IndexClient) {
+	index := &fakeIndex{vectors: make(map[string]*pinecone.Vector)}
+	for _, v := range vectors {
+		index.vectors[v.ID] = v
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/vectors/upsert", func(w http.ResponseWriter, r *http.Request) {
+		var params pinecone.UpsertVectorsParams
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&params))
+		assert.Equal(t, "ns", params.Namespace)
+
+		index.mu.Lock()
+		for _, v := range params.Vectors {
+			index.vectors[v.ID] = v
+		}
+		index.mu.Unlock()
+		index.writes.Add(1)
+
+		_ = json.NewEncoder(w).Encode(pinecone.UpsertVectorsResponse{UpsertedCount: len(params.Vectors)})
+	})
+	mux.HandleFunc("/vectors/delete", func(w http.ResponseWriter, r *http.Request) {
+		var params pinecone.DeleteVectorsParams
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&params))
+		assert.Equal(t, "ns", params.Namespace)
+
+		index.mu.Lock()
+		for _, id := range params.IDs {
+			delete(index.vectors, id)
+		}
+		index.mu.Unlock()
+		index.writes.Add(1)
+
+		_, _ = w.Write([]byte(`{}`))
+	})
+	mux.HandleFunc("/vectors/fetch", func(w http.ResponseWriter, r *http.Request) {
+		index.fetches.Add(1)
+
+		index.mu.Lock()
+		defer index.mu.Unlock()
+
+		respBody := pinecone.FetchVectorsResponse{Vectors: make(map[string]*pinecone.Vector), Namespace: "ns"}
+		for _, id := range r.URL.Query()["ids"] {
+			if v, ok := index.vectors[id]; ok {
+				respBody.Vectors[id] = v
+			}
+		}
+
+		_ = json.NewEncoder(w).Encode(respBody)
+	})
+	mux.HandleFunc("/vectors/list", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "ns", r.URL.Query().Get("namespace"))
+		token := r.URL.Query().Get("paginationToken")
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+		index.mu.Lock()
+		defer index.mu.Unlock()
+
+		ids := make([]string, 0)
+		for id := range index.vectors {
+			if id > token {
+				ids = append(ids, id)
+			}
+		}
+		sort.Strings(ids)
+
+		type listed struct {
+			ID string `json:"id"`
+		}
+		respBody := struct {
+			Vectors    []listed `json:"vectors"`
+			Pagination *struct {
+				Next string `json:"next"`
+			} `json:"pagination,omitempty"`
+		}{Vectors: make([]listed, 0)}
+		if limit > 0 && len(ids) > limit {
+			ids = ids[:limit]
+			respBody.Pagination = &struct {
+				Next string `json:"next"`
+			}{Next: ids[limit-1]}
+		}
+		for _, id := range ids {
+			respBody.Vectors = append(respBody.Vectors, listed{ID: id})
+		}
+
+		_ = json.NewEncoder(w).Encode(respBody)
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	ic, err := pinecone.NewIndexClient(pinecone.WithAPIKey("key"), pinecone.WithBaseURL(server.URL), pinecone.WithControllerBaseURL(server.URL))
+	require.NoError(t, err)
+
+	return index, ic
+}
+
+func (f *fakeIndex) ids() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	ids := make([]string, 0, len(f.vectors))
+	for id := range f.vectors {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	return ids
+}
+
+// table is a source of truth of IDs and hashes.
+type table map[string]string
+
+func (s table) Records(ctx context.Context, fn func(record Record) error) error {
+	for id, hash := range s {
+		if err := fn(Record{ID: id, Hash: hash}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s table) Vectors(ctx context.Context, ids []string) ([]*pinecone.Vector, error) {
+	vectors := make([]*pinecone.Vector, 0, len(ids))
+	for _, id := range ids {
+		hash, ok := s[id]
+		if !ok {
+			continue
+		}
+
+		vectors = append(vectors, &pinecone.Vector{ID: id, Values: []float32{1, 2}, Metadata: map[string]any{DefaultHashKey: hash}})
+	}
+
+	return vectors, nil
+}
+
+func hashed(id, hash string) *pinecone.Vector {
+	return &pinecone.Vector{ID: id, Values: []float32{1, 2}, Metadata: map[string]any{DefaultHashKey: hash}}
+}
+
+func TestReconcile(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	index, ic := newFakeIndex(t,
+		hashed("a", "1"),
+		hashed("b", "old"),
+		hashed("c", "3"),
+		hashed("orphan", "4"),
+		&pinecone.Vector{ID: "unhashed", Values: []float32{1, 2}},
+	)
+	source := table{"a": "1", "b": "2", "c": "3", "missing": "5", "unhashed": "6"}
+
+	report, err := Reconcile(context.Background(), ic, "ns", source, Options{})
+	require.NoError(err)
+	assert.True(report.Drifted())
+	assert.Equal([]string{"missing"}, report.Missing)
+	assert.Equal([]string{"orphan"}, report.Orphaned)
+	assert.Equal([]string{"b", "unhashed"}, report.Stale)
+	assert.Equal(int64(2), report.InSync)
+	assert.Zero(report.Upserted)
+	assert.Zero(index.writes.Load())
+
+	t.Run("DryRun", func(t *testing.T) {
+		report, err := Reconcile(context.Background(), ic, "ns", source, Options{Repair: true, DryRun: true})
+		require.NoError(err)
+		assert.True(report.DryRun)
+		assert.Equal(int64(3), report.Upserted)
+		assert.Equal(int64(1), report.Deleted)
+		assert.Zero(index.writes.Load())
+	})
+
+	t.Run("Repair", func(t *testing.T) {
+		report, err := Reconcile(context.Background(), ic, "ns", source, Options{Repair: true, BatchSize: 2})
+		require.NoError(err)
+		assert.Equal(int64(3), report.Upserted)
+		assert.Equal(int64(1), report.Deleted)
+		assert.Equal(int32(3), index.writes.Load())
+		assert.Equal([]string{"a", "b", "c", "missing", "unhashed"}, index.ids())
+
+		report, err = Reconcile(context.Background(), ic, "ns", source, Options{})
+		require.NoError(err)
+		assert.False(report.Drifted())
+		assert.Equal(int64(5), report.InSync)
+	})
+}
+
+func TestReconcile_IDsOnly(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	index, ic := newFakeIndex(t, hashed("a", "stale"), hashed("b", "2"), hashed("orphan", "3"))
+	source := table{"a": "", "b": "", "missing": ""}
+
+	report, err := Reconcile(context.Background(), ic, "ns", source, Options{})
+	require.NoError(err)
+	assert.Equal([]string{"missing"}, report.Missing)
+	assert.Equal([]string{"orphan"}, report.Orphaned)
+	assert.Empty(report.Stale)
+	assert.Equal(int64(2), report.InSync)
+	// without hashes, the IDs are listed but the vectors are never fetched
+	assert.Zero(index.fetches.Load())
+}
+
+func TestReconcile_CustomHashKey(t *testing.T) {
+	require := require.New(t)
+
+	_, ic := newFakeIndex(t, &pinecone.Vector{ID: "a", Values: []float32{1, 2}, Metadata: map[string]any{"etag": "1"}})
+
+	report, err := Reconcile(context.Background(), ic, "ns", table{"a": "1"}, Options{HashKey: "etag"})
+	require.NoError(err)
+	require.False(report.Drifted())
+}
+
+type failingSource struct {
+	table
+}
+
+var errSource = errors.New("connection refused")
+
+func (failingSource) Vectors(ctx context.Context, ids []string) ([]*pinecone.Vector, error) {
+	return nil, errSource
+}
+
+func TestReconcile_Errors(t *testing.T) {
+	index, ic := newFakeIndex(t)
+
+	_, err := Reconcile(context.Background(), ic, "ns", table{"": ""}, Options{})
+	require.ErrorIs(t, err, pinecone.ErrInvalidParams)
+
+	_, err = Reconcile(context.Background(), ic, "ns", table{}, Options{BatchSize: -1})
+	require.ErrorIs(t, err, pinecone.ErrInvalidParams)
+
+	report, err := Reconcile(context.Background(), ic, "ns", failingSource{table{"a": "1"}}, Options{Repair: true})
+	require.ErrorIs(t, err, errSource)
+	assert.Equal(t, []string{"a"}, report.Missing)
+	assert.Zero(t, index.writes.Load())
+}
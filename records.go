@@ -0,0 +1,225 @@
+package pinecone
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+const (
+	defaultRecordsNamespace = "__default__"
+	maxUpsertRecordsBatch   = 96
+)
+
+// Record represents a raw record of an index with integrated embedding. The
+// record ID is held in the "_id" field, the text to embed in the field
+// declared by the field map of the index, and any other field is stored as
+// metadata.
+type Record map[string]any
+
+// ID returns the ID of the record.
+func (r Record) ID() string {
+	id, _ := r["_id"].(string)
+	return id
+}
+
+// UpsertRecords upserts raw text records into a namespace of an index with
+// integrated embedding. Records are sent as NDJSON in batches of 96, and are
+// validated against the field map of the index first.
+//
+// API Reference: https://docs.pinecone.io/reference/api/data-plane/upsert_records
+func (ic *IndexClient) UpsertRecords(ctx context.Context, namespace string, records []Record) error {
+	embed, err := ic.integratedEmbedding(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := validateUpsertRecordsParams(embed, records); err != nil {
+		return err
+	}
+
+	for start := 0; start < len(records); start += maxUpsertRecordsBatch {
+		end := start + maxUpsertRecordsBatch
+		if end > len(records) {
+			end = len(records)
+		}
+
+		body := new(bytes.Buffer)
+		encoder := json.NewEncoder(body)
+		for _, record := range records[start:end] {
+			if err := encoder.Encode(record); err != nil {
+				return fmt.Errorf("%w: record %q cannot be encoded: %s", ErrInvalidParams, record.ID(), err)
+			}
+		}
+
+		resp, err := ic.reqClient.
+			R().
+			SetHeader("X-Pinecone-API-Version", apiVersion).
+			SetContentType("application/x-ndjson").
+			SetBodyBytes(body.Bytes()).
+			SetContext(ctx).
+			Post("/records/namespaces/" + recordsNamespacePath(namespace) + "/upsert")
+		if err != nil {
+			return err
+		}
+		if !resp.IsSuccessState() {
+			buffer := new(bytes.Buffer)
+			_, err := buffer.ReadFrom(resp.Body)
+			if err != nil {
+				return err
+			}
+
+			return &RequestError{StatusCode: resp.StatusCode, Body: buffer.String()}
+		}
+	}
+
+	return nil
+}
+
+// SearchRerank represents the reranking step of a search request.
+type SearchRerank struct {
+	// Required. The reranking model, e.g. bge-reranker-v2-m3.
+	Model string `json:"model"`
+	// Required. The fields of the hits to rank on.
+	RankFields []string `json:"rank_fields"`
+	// The number of hits to return after reranking. Defaults to TopK.
+	TopN int `json:"top_n,omitempty"`
+	// The query to rerank with. Defaults to the text of the search inputs.
+	Query string `json:"query,omitempty"`
+	// Model-specific parameters, e.g. truncate.
+	Parameters map[string]any `json:"parameters,omitempty"`
+}
+
+// SearchParams represents the parameters for a search records request.
+// See https://docs.pinecone.io/reference/api/data-plane/search_records for more information.
+type SearchParams struct {
+	// Required. The inputs of the embedding model, e.g. {"text": "..."}.
+	Inputs map[string]any
+	// Required. The number of hits to return.
+	TopK int
+	// A metadata filter to apply.
+	Filter map[string]any
+	// The fields of the records to return. Defaults to all fields.
+	Fields []string
+	// Reranks the hits before they are returned.
+	Rerank *SearchRerank
+}
+
+type searchQuery struct {
+	Inputs map[string]any `json:"inputs"`
+	TopK   int            `json:"top_k"`
+	Filter map[string]any `json:"filter,omitempty"`
+}
+
+type searchBodyParams struct {
+	Query  searchQuery   `json:"query"`
+	Fields []string      `json:"fields,omitempty"`
+	Rerank *SearchRerank `json:"rerank,omitempty"`
+}
+
+// Hit represents a record matched by a search request.
+type Hit struct {
+	ID     string         `json:"_id"`
+	Score  float32        `json:"_score"`
+	Fields map[string]any `json:"fields"`
+}
+
+// SearchUsage represents the usage reported by a search request.
+type SearchUsage struct {
+	ReadUnits        int `json:"read_units"`
+	EmbedTotalTokens int `json:"embed_total_tokens"`
+	RerankUnits      int `json:"rerank_units"`
+}
+
+// SearchRecordsResponse represents the response from a search records request.
+type SearchRecordsResponse struct {
+	Hits  []*Hit      `json:"hits"`
+	Usage SearchUsage `json:"usage"`
+}
+
+type searchResponseBody struct {
+	Result struct {
+		Hits []*Hit `json:"hits"`
+	} `json:"result"`
+	Usage SearchUsage `json:"usage"`
+}
+
+// SearchRecords searches a namespace of an index with integrated embedding
+// with a text query. The inputs are validated against the field map of the
+// index first.
+//
+// API Reference: https://docs.pinecone.io/reference/api/data-plane/search_records
+func (ic *IndexClient) SearchRecords(ctx context.Context, namespace string, params SearchParams) (*SearchRecordsResponse, error) {
+	embed, err := ic.integratedEmbedding(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateSearchParams(embed, params); err != nil {
+		return nil, err
+	}
+
+	var respBody searchResponseBody
+	resp, err := ic.reqClient.
+		R().
+		SetHeader("X-Pinecone-API-Version", apiVersion).
+		SetContentType("application/json").
+		SetBody(searchBodyParams{
+			Query: searchQuery{
+				Inputs: params.Inputs,
+				TopK:   params.TopK,
+				Filter: params.Filter,
+			},
+			Fields: params.Fields,
+			Rerank: params.Rerank,
+		}).
+		SetSuccessResult(&respBody).
+		SetContext(ctx).
+		Post("/records/namespaces/" + recordsNamespacePath(namespace) + "/search")
+	if err != nil {
+		return nil, err
+	}
+	if !resp.IsSuccessState() {
+		buffer := new(bytes.Buffer)
+		_, err := buffer.ReadFrom(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, &RequestError{StatusCode: resp.StatusCode, Body: buffer.String()}
+	}
+
+	if respBody.Result.Hits == nil {
+		respBody.Result.Hits = make([]*Hit, 0)
+	}
+
+	return &SearchRecordsResponse{
+		Hits:  respBody.Result.Hits,
+		Usage: respBody.Usage,
+	}, nil
+}
+
+// integratedEmbedding returns the integrated embedding configuration of the
+// index, and fails if the index was not created for a hosted model.
+func (ic *IndexClient) integratedEmbedding(ctx context.Context) (*IndexEmbed, error) {
+	index, err := ic.describeIndexModel(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if index.Embed == nil || len(index.Embed.FieldMap) == 0 {
+		return nil, fmt.Errorf("%w: index %q has no integrated embedding", ErrInvalidParams, index.Name)
+	}
+
+	return index.Embed, nil
+}
+
+func recordsNamespacePath(namespace string) string {
+	if namespace == "" {
+		namespace = defaultRecordsNamespace
+	}
+
+	return url.PathEscape(namespace)
+}
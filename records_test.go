@@ -0,0 +1,154 @@
+package pinecone
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRecordsTestServer(t *testing.T, upserted *[]Record, searches *[]map[string]any) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/indexes/integrated", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, apiVersion, r.Header.Get("X-Pinecone-API-Version"))
+
+		_, _ = w.Write([]byte(`{
+			"name": "integrated",
+			"vector_type": "dense",
+			"metric": "cosine",
+			"dimension": 1024,
+			"status": {"ready": true, "state": "Ready"},
+			"host": "integrated-abc123.svc.aped-4627-b74a.pinecone.io",
+			"spec": {"serverless": {"region": "us-east-1", "cloud": "aws"}},
+			"deletion_protection": "disabled",
+			"tags": null,
+			"embed": {
+				"model": "llama-text-embed-v2",
+				"metric": "cosine",
+				"dimension": 1024,
+				"vector_type": "dense",
+				"field_map": {"text": "chunk_text"},
+				"read_parameters": {"input_type": "query", "truncate": "END"},
+				"write_parameters": {"input_type": "passage", "truncate": "END"}
+			}
+		}`))
+	})
+	mux.HandleFunc("/indexes/plain", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{
+			"name": "plain",
+			"vector_type": "dense",
+			"metric": "dotproduct",
+			"dimension": 2,
+			"status": {"ready": true, "state": "Ready"},
+			"host": "plain-abc123.svc.aped-4627-b74a.pinecone.io",
+			"spec": {"serverless": {"region": "us-east-1", "cloud": "aws"}},
+			"deletion_protection": "disabled"
+		}`))
+	})
+	mux.HandleFunc("/records/namespaces/__default__/upsert", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/x-ndjson", r.Header.Get("Content-Type"))
+		assert.Equal(t, apiVersion, r.Header.Get("X-Pinecone-API-Version"))
+
+		scanner := bufio.NewScanner(r.Body)
+		for scanner.Scan() {
+			var record Record
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+			*upserted = append(*upserted, record)
+		}
+
+		w.WriteHeader(http.StatusCreated)
+	})
+	mux.HandleFunc("/records/namespaces/docs/search", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, apiVersion, r.Header.Get("X-Pinecone-API-Version"))
+
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		*searches = append(*searches, body)
+
+		_, _ = w.Write([]byte(`{
+			"result": {"hits": [
+				{"_id": "rec-2", "_score": 0.9, "fields": {"chunk_text": "apples are red", "category": "fruit"}},
+				{"_id": "rec-1", "_score": 0.4, "fields": {"chunk_text": "the sky is blue", "category": "nature"}}
+			]},
+			"usage": {"read_units": 6, "embed_total_tokens": 8, "rerank_units": 1}
+		}`))
+	})
+
+	return httptest.NewServer(mux)
+}
+
+func TestUpsertRecords(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	var upserted []Record
+	var searches []map[string]any
+	server := newRecordsTestServer(t, &upserted, &searches)
+	defer server.Close()
+
+	ic := newTestIndexClient(server.URL, WithIndexName("integrated"))
+
+	records := make([]Record, 0, 100)
+	for i := 0; i < 100; i++ {
+		records = append(records, Record{"_id": fmt.Sprintf("rec-%d", i), "chunk_text": "text", "category": "fruit"})
+	}
+
+	require.NoError(ic.UpsertRecords(context.Background(), "", records))
+	assert.Len(upserted, 100)
+	assert.Equal("fruit", upserted[99]["category"])
+
+	err := ic.UpsertRecords(context.Background(), "", []Record{{"_id": "rec-1", "text": "wrong field"}})
+	require.ErrorIs(err, ErrInvalidParams)
+	assert.Contains(err.Error(), `"chunk_text"`)
+
+	err = ic.UpsertRecords(context.Background(), "", []Record{{"chunk_text": "no id"}})
+	require.ErrorIs(err, ErrInvalidParams)
+
+	plain := newTestIndexClient(server.URL, WithIndexName("plain"))
+	err = plain.UpsertRecords(context.Background(), "", records)
+	require.ErrorIs(err, ErrInvalidParams)
+	assert.Contains(err.Error(), "no integrated embedding")
+}
+
+func TestSearchRecords(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	var upserted []Record
+	var searches []map[string]any
+	server := newRecordsTestServer(t, &upserted, &searches)
+	defer server.Close()
+
+	ic := newTestIndexClient(server.URL, WithIndexName("integrated"))
+
+	resp, err := ic.SearchRecords(context.Background(), "docs", SearchParams{
+		Inputs: map[string]any{"text": "red fruit"},
+		TopK:   2,
+		Filter: map[string]any{"category": map[string]any{"$eq": "fruit"}},
+		Fields: []string{"chunk_text", "category"},
+		Rerank: &SearchRerank{Model: "bge-reranker-v2-m3", RankFields: []string{"chunk_text"}},
+	})
+	require.NoError(err)
+	require.Len(resp.Hits, 2)
+	assert.Equal("rec-2", resp.Hits[0].ID)
+	assert.Equal(float32(0.9), resp.Hits[0].Score)
+	assert.Equal("apples are red", resp.Hits[0].Fields["chunk_text"])
+	assert.Equal(6, resp.Usage.ReadUnits)
+	assert.Equal(1, resp.Usage.RerankUnits)
+
+	require.Len(searches, 1)
+	query := searches[0]["query"].(map[string]any)
+	assert.Equal(float64(2), query["top_k"])
+	assert.Equal("red fruit", query["inputs"].(map[string]any)["text"])
+	assert.Equal("bge-reranker-v2-m3", searches[0]["rerank"].(map[string]any)["model"])
+
+	_, err = ic.SearchRecords(context.Background(), "docs", SearchParams{Inputs: map[string]any{"query": "red"}, TopK: 1})
+	require.ErrorIs(err, ErrInvalidParams)
+	assert.Contains(err.Error(), `"text"`)
+}
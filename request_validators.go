@@ -1,6 +1,21 @@
 package pinecone
 
-import "fmt"
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+)
+
+const (
+	// MaxVectorIDLength is the maximum length of a vector ID.
+	MaxVectorIDLength = 512
+	// MaxMetadataSize is the maximum size of the JSON-serialized metadata of a vector.
+	MaxMetadataSize = 40 * 1024
+	// MaxSparseNonZeros is the maximum number of non-zero values of a sparse vector.
+	MaxSparseNonZeros = 1000
+	// MaxListVectorIDsLimit is the maximum number of IDs listed per page.
+	MaxListVectorIDsLimit = 100
+)
 
 // validateQueryParams validates the query parameters.
 func validateQueryParams(params QueryParams) error {
@@ -36,6 +51,28 @@ func validateDeleteVectorsParams(params DeleteVectorsParams) error {
 	return nil
 }
 
+// validateListVectorIDsParams validates the list vector IDs parameters.
+func validateListVectorIDsParams(params ListVectorIDsParams) error {
+	if params.Limit < 0 || params.Limit > MaxListVectorIDsLimit {
+		return fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidParams, MaxListVectorIDsLimit)
+	}
+
+	return nil
+}
+
+// validateScanParams validates the scan parameters.
+func validateScanParams(params ScanParams) error {
+	if params.PageSize < 0 || params.PageSize > MaxListVectorIDsLimit {
+		return fmt.Errorf("%w: page size must be between 1 and %d", ErrInvalidParams, MaxListVectorIDsLimit)
+	}
+
+	if params.Concurrency < 0 {
+		return fmt.Errorf("%w: concurrency must be greater than 0", ErrInvalidParams)
+	}
+
+	return nil
+}
+
 // validateFetchVectorsParams validates the fetch vectors parameters.
 func validateFetchVectorsParams(params FetchVectorsParams) error {
 	if params.IDs == nil || len(params.IDs) < 1 {
@@ -55,7 +92,15 @@ func validateUpdateVectorParams(params UpdateVectorParams) error {
 		return fmt.Errorf("%w: sparse vector values and indices must be the same length", ErrInvalidParams)
 	}
 
-	return nil
+	if err := validateVectorID(params.ID); err != nil {
+		return err
+	}
+
+	if err := validateSparseNonZeros(params.ID, params.SparseValues); err != nil {
+		return err
+	}
+
+	return validateMetadata(params.ID, params.SetMetadata)
 }
 
 // validateUpsertVectorsParams validates the upsert vectors parameters.
@@ -65,9 +110,243 @@ func validateUpsertVectorsParams(params UpsertVectorsParams) error {
 	}
 
 	for _, v := range params.Vectors {
+		if v == nil {
+			return fmt.Errorf("%w: vectors must not contain nil", ErrInvalidParams)
+		}
+
 		if v.SparseValues != nil && len(v.SparseValues.Values) != len(v.SparseValues.Indices) {
 			return fmt.Errorf("%w: sparse vector values and indices must be the same length", ErrInvalidParams)
 		}
+
+		if err := validateVectorID(v.ID); err != nil {
+			return err
+		}
+
+		if err := validateSparseNonZeros(v.ID, v.SparseValues); err != nil {
+			return err
+		}
+
+		if err := validateMetadata(v.ID, v.Metadata); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateVectorID validates the length and charset of a vector ID. IDs must
+// be printable ASCII.
+func validateVectorID(id string) error {
+	if id == "" {
+		return fmt.Errorf("%w: vector id is required", ErrInvalidParams)
+	}
+
+	if len(id) > MaxVectorIDLength {
+		return fmt.Errorf("%w: vector %q: id is longer than %d characters", ErrInvalidParams, id, MaxVectorIDLength)
+	}
+
+	for _, r := range id {
+		if r < 0x20 || r > 0x7e {
+			return fmt.Errorf("%w: vector %q: id must only contain printable ASCII characters", ErrInvalidParams, id)
+		}
+	}
+
+	return nil
+}
+
+// validateSparseNonZeros validates the number of non-zero values of a sparse vector.
+func validateSparseNonZeros(id string, sparse *SparseVector) error {
+	if sparse != nil && len(sparse.Values) > MaxSparseNonZeros {
+		return fmt.Errorf("%w: vector %q: sparse values has %d non-zeros, the maximum is %d", ErrInvalidParams, id, len(sparse.Values), MaxSparseNonZeros)
+	}
+
+	return nil
+}
+
+// validateMetadata validates metadata against the Pinecone metadata rules:
+// values must be strings, finite numbers, booleans or lists of strings, and
+// the serialized metadata must not exceed MaxMetadataSize.
+func validateMetadata(id string, metadata map[string]any) error {
+	if len(metadata) == 0 {
+		return nil
+	}
+
+	for field, value := range metadata {
+		if field == "" {
+			return fmt.Errorf("%w: vector %q: metadata field names must not be empty", ErrInvalidParams, id)
+		}
+
+		if err := validateMetadataValue(value); err != nil {
+			return fmt.Errorf("%w: vector %q: metadata field %q %s", ErrInvalidParams, id, field, err)
+		}
+	}
+
+	body, err := json.Marshal(metadata)
+	if err != nil {
+		return fmt.Errorf("%w: vector %q: metadata cannot be serialized: %s", ErrInvalidParams, id, err)
+	}
+
+	if len(body) > MaxMetadataSize {
+		return fmt.Errorf("%w: vector %q: metadata is %d bytes, the maximum is %d", ErrInvalidParams, id, len(body), MaxMetadataSize)
+	}
+
+	return nil
+}
+
+// validateMetadataValue validates a single metadata value, and describes
+// why it is invalid.
+func validateMetadataValue(value any) error {
+	switch v := value.(type) {
+	case string, bool,
+		int, int8, int16, int32, int64,
+		uint, uint8, uint16, uint32, uint64:
+		return nil
+	case float32:
+		return validateMetadataNumber(float64(v))
+	case float64:
+		return validateMetadataNumber(v)
+	case []string:
+		return nil
+	case []any:
+		for i, item := range v {
+			if _, ok := item.(string); !ok {
+				return fmt.Errorf("must be a list of strings, item %d is %T", i, item)
+			}
+		}
+
+		return nil
+	case nil:
+		return fmt.Errorf("must not be null")
+	default:
+		return fmt.Errorf("has unsupported type %T, only strings, numbers, booleans and lists of strings are supported", value)
+	}
+}
+
+func validateMetadataNumber(v float64) error {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return fmt.Errorf("must be a finite number")
+	}
+
+	return nil
+}
+
+// validateQueryNamespacesParams validates the query namespaces parameters.
+func validateQueryNamespacesParams(namespaces []string, params QueryNamespacesParams) error {
+	if len(namespaces) < 1 {
+		return fmt.Errorf("%w: namespaces is required", ErrInvalidParams)
+	}
+
+	if params.PartialFailureMode != PartialFailureModeFailFast && params.PartialFailureMode != PartialFailureModeAllowPartial {
+		return fmt.Errorf("%w: unknown partial failure mode %d", ErrInvalidParams, params.PartialFailureMode)
+	}
+
+	return validateQueryParams(params.QueryParams)
+}
+
+// validateUpsertHybridVectorsParams validates the upsert hybrid vectors parameters.
+func validateUpsertHybridVectorsParams(params UpsertVectorsParams) error {
+	if err := validateUpsertVectorsParams(params); err != nil {
+		return err
+	}
+
+	for _, v := range params.Vectors {
+		if len(v.Values) == 0 {
+			return fmt.Errorf("%w: vector %q is missing dense values", ErrInvalidParams, v.ID)
+		}
+		if v.SparseValues == nil || len(v.SparseValues.Indices) == 0 {
+			return fmt.Errorf("%w: vector %q is missing sparse values", ErrInvalidParams, v.ID)
+		}
+	}
+
+	return nil
+}
+
+// validateUpsertTextsParams validates the upsert texts parameters.
+func validateUpsertTextsParams(params UpsertTextsParams) error {
+	if len(params.Records) < 1 {
+		return fmt.Errorf("%w: records is required", ErrInvalidParams)
+	}
+
+	for _, record := range params.Records {
+		if record.ID == "" {
+			return fmt.Errorf("%w: record id is required", ErrInvalidParams)
+		}
+		if record.Text == "" {
+			return fmt.Errorf("%w: record %q text is required", ErrInvalidParams, record.ID)
+		}
+	}
+
+	return nil
+}
+
+// validateRerankParams validates the rerank parameters.
+func validateRerankParams(params RerankParams) error {
+	if params.Model == "" {
+		return fmt.Errorf("%w: model is required", ErrInvalidParams)
+	}
+
+	if params.Query == "" {
+		return fmt.Errorf("%w: query is required", ErrInvalidParams)
+	}
+
+	if len(params.Documents) < 1 {
+		return fmt.Errorf("%w: documents is required", ErrInvalidParams)
+	}
+
+	if params.TopN < 0 {
+		return fmt.Errorf("%w: top n must not be negative", ErrInvalidParams)
+	}
+
+	return nil
+}
+
+// validateUpsertRecordsParams validates the upsert records parameters
+// against the field map of the index.
+func validateUpsertRecordsParams(embed *IndexEmbed, records []Record) error {
+	if len(records) < 1 {
+		return fmt.Errorf("%w: records is required", ErrInvalidParams)
+	}
+
+	for i, record := range records {
+		if record.ID() == "" {
+			return fmt.Errorf("%w: record %d is missing the _id field", ErrInvalidParams, i)
+		}
+
+		for _, field := range embed.FieldMap {
+			text, ok := record[field].(string)
+			if !ok || text == "" {
+				return fmt.Errorf("%w: record %q is missing the text field %q declared by the field map", ErrInvalidParams, record.ID(), field)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateSearchParams validates the search records parameters against the
+// field map of the index.
+func validateSearchParams(embed *IndexEmbed, params SearchParams) error {
+	if params.TopK < 1 {
+		return fmt.Errorf("%w: top k is required and must be greater than 0", ErrInvalidParams)
+	}
+
+	if len(params.Inputs) < 1 {
+		return fmt.Errorf("%w: inputs is required", ErrInvalidParams)
+	}
+
+	for input := range embed.FieldMap {
+		if _, ok := params.Inputs[input]; !ok {
+			return fmt.Errorf("%w: inputs is missing %q declared by the field map", ErrInvalidParams, input)
+		}
+	}
+
+	if params.Rerank != nil {
+		if params.Rerank.Model == "" {
+			return fmt.Errorf("%w: rerank model is required", ErrInvalidParams)
+		}
+		if len(params.Rerank.RankFields) < 1 {
+			return fmt.Errorf("%w: rerank rank fields is required", ErrInvalidParams)
+		}
 	}
 
 	return nil
@@ -0,0 +1,164 @@
+package pinecone
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+)
+
+// RerankDocument represents a document to rerank. Documents are ranked by
+// their "text" field, unless other fields are given with RerankParams.RankFields.
+type RerankDocument map[string]any
+
+// RerankParams represents the parameters for a rerank request.
+// See https://docs.pinecone.io/reference/api/inference/rerank for more information.
+type RerankParams struct {
+	// Required. The reranking model, e.g. bge-reranker-v2-m3.
+	Model string `json:"model"`
+	// Required. The query to rank the documents against.
+	Query string `json:"query"`
+	// Required. The documents to rank.
+	Documents []RerankDocument `json:"documents"`
+	// The number of results to return. Zero returns all documents.
+	TopN int `json:"top_n,omitempty"`
+	// The fields of the documents to rank on. Defaults to ["text"].
+	RankFields []string `json:"rank_fields,omitempty"`
+	// Whether the documents are returned along with the results.
+	ReturnDocuments bool `json:"return_documents"`
+	// Model-specific parameters, e.g. truncate.
+	Parameters map[string]any `json:"parameters,omitempty"`
+}
+
+// RerankResult represents one ranked document.
+type RerankResult struct {
+	// The position of the document in RerankParams.Documents.
+	Index int `json:"index"`
+	// The relevance of the document to the query, higher is more relevant.
+	Score float32 `json:"score"`
+	// The document, when requested with ReturnDocuments.
+	Document RerankDocument `json:"document,omitempty"`
+}
+
+// RerankUsage represents the usage reported by a rerank request.
+type RerankUsage struct {
+	RerankUnits int `json:"rerank_units"`
+}
+
+// RerankResponse represents the response from a rerank request.
+type RerankResponse struct {
+	Model string `json:"model"`
+	// The ranked documents, most relevant first.
+	Data  []*RerankResult `json:"data"`
+	Usage RerankUsage     `json:"usage"`
+}
+
+// Rerank ranks documents by their relevance to a query with a model hosted
+// by Pinecone.
+//
+// API Reference: https://docs.pinecone.io/reference/api/inference/rerank
+func (c *InferenceClient) Rerank(ctx context.Context, params RerankParams) (*RerankResponse, error) {
+	if err := validateRerankParams(params); err != nil {
+		return nil, err
+	}
+
+	var respBody RerankResponse
+	resp, err := c.reqClient.
+		R().
+		SetContentType("application/json").
+		SetBody(params).
+		SetSuccessResult(&respBody).
+		SetContext(ctx).
+		Post("/rerank")
+	if err != nil {
+		return nil, err
+	}
+	if !resp.IsSuccessState() {
+		buffer := new(bytes.Buffer)
+		_, err := buffer.ReadFrom(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, &RequestError{StatusCode: resp.StatusCode, Body: buffer.String()}
+	}
+
+	return &respBody, nil
+}
+
+// RerankMatchesParams represents the parameters for reranking query matches.
+type RerankMatchesParams struct {
+	// Required. The reranking model, e.g. bge-reranker-v2-m3.
+	Model string
+	// Required. The query to rank the matches against.
+	Query string
+	// Required. The metadata field holding the text of the matches.
+	TextField string
+	// The number of matches to return. Zero returns all matches.
+	TopN int
+	// Model-specific parameters, e.g. truncate.
+	Parameters map[string]any
+}
+
+// RerankedMatch represents a query match reordered by a reranking model.
+// QueryVector.Score holds the original similarity score.
+type RerankedMatch struct {
+	*QueryVector
+	RerankScore float32 `json:"rerankScore"`
+}
+
+// RerankMatches reranks the matches of a query response by the text held in
+// a metadata field, and returns them ordered by their rerank score. The query
+// must have been made with IncludeMetadata. Nil matches are skipped.
+func (c *InferenceClient) RerankMatches(ctx context.Context, queryResp *QueryResponse, params RerankMatchesParams) ([]*RerankedMatch, error) {
+	if queryResp == nil {
+		return nil, fmt.Errorf("%w: query response is required", ErrInvalidParams)
+	}
+	if params.TextField == "" {
+		return nil, fmt.Errorf("%w: text field is required", ErrInvalidParams)
+	}
+
+	matches := make([]*QueryVector, 0, len(queryResp.Matches))
+	documents := make([]RerankDocument, 0, len(queryResp.Matches))
+	for _, match := range queryResp.Matches {
+		if match == nil {
+			continue
+		}
+
+		text, ok := match.Metadata[params.TextField].(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: match %q has no text in metadata field %q", ErrInvalidParams, match.ID, params.TextField)
+		}
+
+		matches = append(matches, match)
+		documents = append(documents, RerankDocument{params.TextField: text})
+	}
+	if len(matches) == 0 {
+		return make([]*RerankedMatch, 0), nil
+	}
+
+	resp, err := c.Rerank(ctx, RerankParams{
+		Model:      params.Model,
+		Query:      params.Query,
+		Documents:  documents,
+		TopN:       params.TopN,
+		RankFields: []string{params.TextField},
+		Parameters: params.Parameters,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	reranked := make([]*RerankedMatch, 0, len(resp.Data))
+	for _, result := range resp.Data {
+		if result.Index < 0 || result.Index >= len(matches) {
+			return nil, fmt.Errorf("%w: rerank result index %d out of range", ErrRequestFailed, result.Index)
+		}
+
+		reranked = append(reranked, &RerankedMatch{
+			QueryVector: matches[result.Index],
+			RerankScore: result.Score,
+		})
+	}
+
+	return reranked, nil
+}
@@ -0,0 +1,93 @@
+package rerank
+
+import (
+	"fmt"
+	"math"
+
+	pinecone "github.com/nekomeowww/go-pinecone"
+)
+
+// MMR re-orders matches by Maximal Marginal Relevance, trading relevance to
+// the query for diversity among the selected matches. The relevance of a
+// match is its score, the redundancy is its highest cosine similarity to the
+// matches already selected, computed from the returned values.
+type MMR struct {
+	// The weight of relevance against diversity, from 0 (only diversity) to
+	// 1 (only relevance).
+	Lambda float64
+}
+
+var _ Stage = MMR{}
+
+// Apply selects up to topK matches greedily by marginal relevance. Scores
+// are kept as they are, only the order changes.
+func (m MMR) Apply(matches []*pinecone.QueryVector, topK int) ([]*pinecone.QueryVector, error) {
+	if m.Lambda < 0 || m.Lambda > 1 {
+		return nil, fmt.Errorf("%w: lambda must be between 0 and 1", pinecone.ErrInvalidParams)
+	}
+	for _, match := range matches {
+		if len(match.Values) == 0 {
+			return nil, fmt.Errorf("%w: match %q has no values", pinecone.ErrInvalidParams, match.ID)
+		}
+	}
+
+	if topK <= 0 || topK > len(matches) {
+		topK = len(matches)
+	}
+
+	selected := make([]*pinecone.QueryVector, 0, topK)
+	remaining := append([]*pinecone.QueryVector(nil), matches...)
+	// the highest similarity of each remaining match to the selected ones
+	redundancy := make([]float64, len(remaining))
+	for i := range redundancy {
+		redundancy[i] = math.Inf(-1)
+	}
+
+	for len(selected) < topK {
+		best, bestScore := -1, math.Inf(-1)
+		for i, match := range remaining {
+			score := m.Lambda * float64(match.Score)
+			if len(selected) > 0 {
+				score -= (1 - m.Lambda) * redundancy[i]
+			}
+			// a NaN score never compares higher, the first match is taken
+			// when every score is NaN
+			if best < 0 || score > bestScore {
+				best, bestScore = i, score
+			}
+		}
+
+		chosen := remaining[best]
+		selected = append(selected, chosen)
+		remaining = append(remaining[:best], remaining[best+1:]...)
+		redundancy = append(redundancy[:best], redundancy[best+1:]...)
+
+		for i, match := range remaining {
+			redundancy[i] = math.Max(redundancy[i], cosineSimilarity(match.Values, chosen.Values))
+		}
+	}
+
+	return selected, nil
+}
+
+func (m MMR) needsValues() bool {
+	return true
+}
+
+func cosineSimilarity(a, b []float32) float64 {
+	var dot, normA, normB float64
+	for i := range a {
+		if i >= len(b) {
+			break
+		}
+
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
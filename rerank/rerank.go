@@ -0,0 +1,166 @@
+// Package rerank provides composable client-side re-ranking stages for query
+// matches, such as Maximal Marginal Relevance, recency decay and metadata
+// boosts.
+//
+// Stages treat scores as similarities, where higher is better, as returned
+// by cosine and dotproduct indexes.
+package rerank
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	pinecone "github.com/nekomeowww/go-pinecone"
+)
+
+const defaultOverFetch = 3
+
+// maxTopK is the highest top-K Pinecone accepts for a query.
+const maxTopK = 10000
+
+// Stage re-ranks query matches.
+type Stage interface {
+	// Apply returns the re-ranked matches. topK is the number of matches
+	// the pipeline will eventually return.
+	Apply(matches []*pinecone.QueryVector, topK int) ([]*pinecone.QueryVector, error)
+}
+
+// StageFunc adapts a function to a Stage.
+type StageFunc func(matches []*pinecone.QueryVector, topK int) ([]*pinecone.QueryVector, error)
+
+// Apply calls f.
+func (f StageFunc) Apply(matches []*pinecone.QueryVector, topK int) ([]*pinecone.QueryVector, error) {
+	return f(matches, topK)
+}
+
+// valuesStage is implemented by stages that need the values of the matches.
+type valuesStage interface {
+	needsValues() bool
+}
+
+// metadataStage is implemented by stages that need the metadata of the matches.
+type metadataStage interface {
+	needsMetadata() bool
+}
+
+// Pipeline applies stages in order.
+type Pipeline []Stage
+
+// Apply applies the stages in order to copies of the matches, so the scores
+// of the given matches are left untouched, and truncates the result to topK.
+// A topK of zero or less keeps every match.
+func (p Pipeline) Apply(matches []*pinecone.QueryVector, topK int) ([]*pinecone.QueryVector, error) {
+	result := make([]*pinecone.QueryVector, 0, len(matches))
+	for _, match := range matches {
+		if match == nil {
+			continue
+		}
+
+		copied := *match
+		result = append(result, &copied)
+	}
+
+	for i, stage := range p {
+		var err error
+		result, err = stage.Apply(result, topK)
+		if err != nil {
+			return nil, fmt.Errorf("rerank stage %d: %w", i, err)
+		}
+	}
+
+	if topK > 0 && len(result) > topK {
+		result = result[:topK]
+	}
+
+	return result, nil
+}
+
+func (p Pipeline) needsValues() bool {
+	for _, stage := range p {
+		if s, ok := stage.(valuesStage); ok && s.needsValues() {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p Pipeline) needsMetadata() bool {
+	for _, stage := range p {
+		if s, ok := stage.(metadataStage); ok && s.needsMetadata() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Querier performs query requests, e.g. *pinecone.IndexClient or *pinecone.ShardedIndex.
+type Querier interface {
+	Query(ctx context.Context, params pinecone.QueryParams) (*pinecone.QueryResponse, error)
+}
+
+// QueryParams represents the parameters for a re-ranked query request.
+type QueryParams struct {
+	pinecone.QueryParams
+	// How many times TopK matches are fetched for the pipeline to choose
+	// from, up to the top-K limit of 10000 matches. Defaults to 3.
+	OverFetch int
+}
+
+// Query over-fetches matches from the querier, applies the pipeline, and
+// returns the requested top-K. Values and metadata are requested whenever a
+// stage of the pipeline needs them, and returned only when requested in
+// params.
+func Query(ctx context.Context, querier Querier, params QueryParams, pipeline Pipeline) (*pinecone.QueryResponse, error) {
+	overFetch := params.OverFetch
+	if overFetch <= 0 {
+		overFetch = defaultOverFetch
+	}
+
+	queryParams := params.QueryParams
+	// the over-fetch never takes the request past the top-K limit, a TopK
+	// beyond it is left for the querier to reject
+	queryParams.TopK = min(params.TopK*int64(overFetch), max(params.TopK, maxTopK))
+	if pipeline.needsValues() {
+		queryParams.IncludeValues = true
+	}
+	if pipeline.needsMetadata() {
+		queryParams.IncludeMetadata = true
+	}
+
+	resp, err := querier.Query(ctx, queryParams)
+	if err != nil {
+		return nil, err
+	}
+
+	matches, err := pipeline.Apply(resp.Matches, int(params.TopK))
+	if err != nil {
+		return nil, err
+	}
+
+	// the values and metadata fetched only for the pipeline are not returned
+	for _, match := range matches {
+		if !params.IncludeValues {
+			match.Values = nil
+			match.SparseValues = nil
+		}
+		if !params.IncludeMetadata {
+			match.Metadata = nil
+		}
+	}
+
+	return &pinecone.QueryResponse{
+		Matches:   matches,
+		Namespace: resp.Namespace,
+	}, nil
+}
+
+// sortByScore orders the matches by descending score, keeping the order of
+// matches with equal scores.
+func sortByScore(matches []*pinecone.QueryVector) {
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Score > matches[j].Score
+	})
+}
@@ -0,0 +1,165 @@
+package rerank
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	pinecone "github.com/nekomeowww/go-pinecone"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ids(matches []*pinecone.QueryVector) []string {
+	result := make([]string, len(matches))
+	for i, match := range matches {
+		result[i] = match.ID
+	}
+
+	return result
+}
+
+func match(id string, score float32, values []float32, metadata map[string]any) *pinecone.QueryVector {
+	return &pinecone.QueryVector{
+		Vector: pinecone.Vector{ID: id, Values: values, Metadata: metadata},
+		Score:  score,
+	}
+}
+
+type fakeQuerier struct {
+	params  pinecone.QueryParams
+	matches []*pinecone.QueryVector
+}
+
+func (f *fakeQuerier) Query(_ context.Context, params pinecone.QueryParams) (*pinecone.QueryResponse, error) {
+	f.params = params
+
+	matches := f.matches
+	if int64(len(matches)) > params.TopK {
+		matches = matches[:params.TopK]
+	}
+
+	return &pinecone.QueryResponse{Matches: matches, Namespace: params.Namespace}, nil
+}
+
+func TestMMR(t *testing.T) {
+	matches := []*pinecone.QueryVector{
+		match("a", 0.95, []float32{1, 0}, nil),
+		match("a-duplicate", 0.94, []float32{1, 0.01}, nil),
+		match("b", 0.80, []float32{0, 1}, nil),
+	}
+
+	t.Run("Diversity", func(t *testing.T) {
+		result, err := Pipeline{MMR{Lambda: 0.5}}.Apply(matches, 2)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a", "b"}, ids(result))
+	})
+
+	t.Run("Relevance", func(t *testing.T) {
+		result, err := Pipeline{MMR{Lambda: 1}}.Apply(matches, 2)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a", "a-duplicate"}, ids(result))
+	})
+
+	t.Run("NaNScores", func(t *testing.T) {
+		nan := float32(math.NaN())
+		result, err := Pipeline{MMR{Lambda: 0.5}}.Apply([]*pinecone.QueryVector{
+			match("a", nan, []float32{1, 0}, nil),
+			match("b", nan, []float32{0, 1}, nil),
+		}, 2)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a", "b"}, ids(result))
+	})
+
+	t.Run("MissingValues", func(t *testing.T) {
+		_, err := Pipeline{MMR{Lambda: 0.5}}.Apply([]*pinecone.QueryVector{match("a", 1, nil, nil)}, 1)
+		require.ErrorIs(t, err, pinecone.ErrInvalidParams)
+	})
+}
+
+func TestRecencyDecay(t *testing.T) {
+	now := time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)
+	matches := []*pinecone.QueryVector{
+		match("old", 0.9, nil, map[string]any{"ts": float64(now.Add(-48 * time.Hour).Unix())}),
+		match("new", 0.6, nil, map[string]any{"ts": now.Add(-time.Hour).Format(time.RFC3339)}),
+		match("undated", 0.5, nil, nil),
+	}
+
+	result, err := Pipeline{RecencyDecay{Field: "ts", HalfLife: 24 * time.Hour, Now: func() time.Time { return now }}}.Apply(matches, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"new", "undated", "old"}, ids(result))
+	assert.InDelta(t, 0.225, result[2].Score, 1e-6)
+	assert.Equal(t, float32(0.9), matches[0].Score, "input matches must be left untouched")
+}
+
+func TestBoostAndMinScore(t *testing.T) {
+	matches := []*pinecone.QueryVector{
+		match("a", 0.9, nil, map[string]any{"lang": "fr"}),
+		match("b", 0.8, nil, map[string]any{"lang": "en", "tags": []any{"news", "tech"}}),
+		match("c", 0.5, nil, map[string]any{"lang": "en", "stars": float64(5)}),
+	}
+
+	result, err := Pipeline{
+		Boost{Field: "lang", Value: "en", Factor: 1.5},
+		Boost{Field: "tags", Value: "tech", Factor: 1.1},
+		Boost{Field: "stars", Value: 5, Factor: 1.1},
+		MinScore(0.85),
+	}.Apply(matches, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"b", "a"}, ids(result))
+	assert.InDelta(t, 1.32, result[0].Score, 1e-6)
+
+	result, err = Pipeline{MinScore(0.85)}.Apply(matches, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a"}, ids(result))
+
+	for _, factor := range []float32{0, -1, float32(math.NaN())} {
+		_, err = Pipeline{Boost{Field: "lang", Value: "en", Factor: factor}}.Apply(matches, 0)
+		require.ErrorIs(t, err, pinecone.ErrInvalidParams)
+	}
+}
+
+func TestQuery(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	querier := &fakeQuerier{matches: []*pinecone.QueryVector{
+		match("a", 0.95, []float32{1, 0}, map[string]any{"lang": "fr"}),
+		match("a-duplicate", 0.94, []float32{1, 0.01}, map[string]any{"lang": "fr"}),
+		match("b", 0.80, []float32{0, 1}, map[string]any{"lang": "en"}),
+		match("c", 0.10, []float32{-1, 0}, map[string]any{"lang": "en"}),
+	}}
+
+	resp, err := Query(context.Background(), querier, QueryParams{
+		QueryParams: pinecone.QueryParams{Vector: []float32{1, 0}, TopK: 1, Namespace: "ns"},
+	}, Pipeline{Boost{Field: "lang", Value: "en", Factor: 2}, MMR{Lambda: 0.5}})
+	require.NoError(err)
+	assert.Equal(int64(3), querier.params.TopK)
+	assert.True(querier.params.IncludeValues)
+	assert.True(querier.params.IncludeMetadata)
+	assert.Equal([]string{"b"}, ids(resp.Matches))
+	assert.Nil(resp.Matches[0].Values)
+	assert.Nil(resp.Matches[0].Metadata)
+	assert.Equal("ns", resp.Namespace)
+
+	// the over-fetch is capped at the top-K limit
+	_, err = Query(context.Background(), querier, QueryParams{
+		QueryParams: pinecone.QueryParams{Vector: []float32{1, 0}, TopK: 5000},
+		OverFetch:   5,
+	}, Pipeline{MMR{Lambda: 0.5}})
+	require.NoError(err)
+	assert.Equal(int64(10000), querier.params.TopK)
+
+	// metadata requested by the caller is kept
+	querier.matches = []*pinecone.QueryVector{
+		match("a", 0.9, nil, map[string]any{"lang": "fr"}),
+		match("b", 0.8, nil, map[string]any{"lang": "en"}),
+	}
+	resp, err = Query(context.Background(), querier, QueryParams{
+		QueryParams: pinecone.QueryParams{Vector: []float32{1, 0}, TopK: 1, IncludeMetadata: true},
+	}, Pipeline{Boost{Field: "lang", Value: "en", Factor: 2}})
+	require.NoError(err)
+	assert.Equal([]string{"b"}, ids(resp.Matches))
+	assert.Equal(map[string]any{"lang": "en"}, resp.Matches[0].Metadata)
+}
@@ -0,0 +1,171 @@
+package rerank
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"time"
+
+	pinecone "github.com/nekomeowww/go-pinecone"
+)
+
+// RecencyDecay multiplies the score of each match by an exponential decay of
+// the age of a timestamp metadata field: a match as old as HalfLife keeps
+// half of its score. Timestamps are either numbers of seconds since the Unix
+// epoch, or RFC 3339 strings. Matches without the field keep their score.
+type RecencyDecay struct {
+	// Required. The metadata field holding the timestamp.
+	Field string
+	// Required. The age at which the score is halved.
+	HalfLife time.Duration
+	// Returns the current time. Defaults to time.Now.
+	Now func() time.Time
+}
+
+var _ Stage = RecencyDecay{}
+
+// Apply decays the scores and re-orders the matches by score.
+func (r RecencyDecay) Apply(matches []*pinecone.QueryVector, _ int) ([]*pinecone.QueryVector, error) {
+	if r.Field == "" {
+		return nil, fmt.Errorf("%w: field is required", pinecone.ErrInvalidParams)
+	}
+	if r.HalfLife <= 0 {
+		return nil, fmt.Errorf("%w: half life must be greater than 0", pinecone.ErrInvalidParams)
+	}
+
+	now := time.Now()
+	if r.Now != nil {
+		now = r.Now()
+	}
+
+	for _, match := range matches {
+		value, ok := match.Metadata[r.Field]
+		if !ok {
+			continue
+		}
+
+		timestamp, err := parseTimestamp(value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: match %q field %q: %s", pinecone.ErrInvalidParams, match.ID, r.Field, err)
+		}
+
+		age := now.Sub(timestamp)
+		if age < 0 {
+			age = 0
+		}
+
+		match.Score *= float32(math.Exp2(-float64(age) / float64(r.HalfLife)))
+	}
+
+	sortByScore(matches)
+
+	return matches, nil
+}
+
+func (r RecencyDecay) needsMetadata() bool {
+	return true
+}
+
+func parseTimestamp(value any) (time.Time, error) {
+	switch v := value.(type) {
+	case float64:
+		sec, frac := math.Modf(v)
+		return time.Unix(int64(sec), int64(frac*1e9)), nil
+	case int:
+		return time.Unix(int64(v), 0), nil
+	case int64:
+		return time.Unix(v, 0), nil
+	case string:
+		return time.Parse(time.RFC3339, v)
+	case time.Time:
+		return v, nil
+	default:
+		return time.Time{}, fmt.Errorf("unsupported timestamp type %T", value)
+	}
+}
+
+// Boost multiplies the score of each match whose metadata field equals Value
+// by Factor. For list fields, the match is boosted if the list contains Value.
+type Boost struct {
+	// Required. The metadata field to match.
+	Field string
+	// The value the field must equal or contain.
+	Value any
+	// The factor the score is multiplied by, e.g. 1.2 to boost by 20% or
+	// 0.5 to demote. Must be greater than 0.
+	Factor float32
+}
+
+var _ Stage = Boost{}
+
+// Apply boosts the scores and re-orders the matches by score.
+func (b Boost) Apply(matches []*pinecone.QueryVector, _ int) ([]*pinecone.QueryVector, error) {
+	if b.Field == "" {
+		return nil, fmt.Errorf("%w: field is required", pinecone.ErrInvalidParams)
+	}
+	if !(b.Factor > 0) {
+		return nil, fmt.Errorf("%w: factor must be greater than 0", pinecone.ErrInvalidParams)
+	}
+
+	for _, match := range matches {
+		if metadataMatches(match.Metadata[b.Field], b.Value) {
+			match.Score *= b.Factor
+		}
+	}
+
+	sortByScore(matches)
+
+	return matches, nil
+}
+
+func (b Boost) needsMetadata() bool {
+	return true
+}
+
+func metadataMatches(field, value any) bool {
+	if list, ok := field.([]any); ok {
+		for _, item := range list {
+			if metadataMatches(item, value) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	return reflect.DeepEqual(normalizeNumber(field), normalizeNumber(value))
+}
+
+// normalizeNumber converts numbers to float64, the type metadata numbers are
+// decoded as.
+func normalizeNumber(value any) any {
+	switch v := value.(type) {
+	case int:
+		return float64(v)
+	case int32:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case float32:
+		return float64(v)
+	default:
+		return value
+	}
+}
+
+// MinScore drops the matches scoring below the threshold.
+type MinScore float32
+
+var _ Stage = MinScore(0)
+
+// Apply drops the matches scoring below the threshold.
+func (m MinScore) Apply(matches []*pinecone.QueryVector, _ int) ([]*pinecone.QueryVector, error) {
+	kept := matches[:0]
+	for _, match := range matches {
+		if match.Score >= float32(m) {
+			kept = append(kept, match)
+		}
+	}
+
+	return kept, nil
+}
@@ -0,0 +1,144 @@
+package pinecone
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newRerankTestServer scores each document by the number of query words its
+// rank field contains.
+func newRerankTestServer(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/rerank", func(w http.ResponseWriter, r *http.Request) {
+		var params RerankParams
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&params))
+
+		field := "text"
+		if len(params.RankFields) > 0 {
+			field = params.RankFields[0]
+		}
+
+		results := make([]*RerankResult, len(params.Documents))
+		for i, document := range params.Documents {
+			text, _ := document[field].(string)
+			var score float32
+			for _, word := range strings.Fields(params.Query) {
+				if strings.Contains(text, word) {
+					score++
+				}
+			}
+
+			results[i] = &RerankResult{Index: i, Score: score}
+			if params.ReturnDocuments {
+				results[i].Document = document
+			}
+		}
+
+		sort.SliceStable(results, func(i, j int) bool { return results[i].Score > results[j].Score })
+		if params.TopN > 0 && params.TopN < len(results) {
+			results = results[:params.TopN]
+		}
+
+		_ = json.NewEncoder(w).Encode(RerankResponse{Model: params.Model, Data: results, Usage: RerankUsage{RerankUnits: 1}})
+	})
+
+	return httptest.NewServer(mux)
+}
+
+func TestRerank(t *testing.T) {
+	server := newRerankTestServer(t)
+	defer server.Close()
+
+	c, err := NewInferenceClient(WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	t.Run("Rerank", func(t *testing.T) {
+		assert := assert.New(t)
+		require := require.New(t)
+
+		resp, err := c.Rerank(context.Background(), RerankParams{
+			Model: "reranker",
+			Query: "quick fox",
+			Documents: []RerankDocument{
+				{"text": "lazy dog"},
+				{"text": "quick brown fox"},
+				{"text": "quick cat"},
+			},
+			TopN:            2,
+			ReturnDocuments: true,
+		})
+		require.NoError(err)
+		require.Len(resp.Data, 2)
+		assert.Equal(1, resp.Data[0].Index)
+		assert.Equal(float32(2), resp.Data[0].Score)
+		assert.Equal("quick brown fox", resp.Data[0].Document["text"])
+		assert.Equal(2, resp.Data[1].Index)
+		assert.Equal(1, resp.Usage.RerankUnits)
+	})
+
+	t.Run("RerankMatches", func(t *testing.T) {
+		assert := assert.New(t)
+		require := require.New(t)
+
+		queryResp := &QueryResponse{Matches: []*QueryVector{
+			{Vector: Vector{ID: "a", Metadata: map[string]any{"body": "lazy dog"}}, Score: 0.9},
+			{Vector: Vector{ID: "b", Metadata: map[string]any{"body": "quick brown fox"}}, Score: 0.8},
+			{Vector: Vector{ID: "c", Metadata: map[string]any{"body": "quick cat"}}, Score: 0.7},
+		}}
+
+		reranked, err := c.RerankMatches(context.Background(), queryResp, RerankMatchesParams{
+			Model:     "reranker",
+			Query:     "quick fox",
+			TextField: "body",
+		})
+		require.NoError(err)
+		require.Len(reranked, 3)
+		assert.Equal("b", reranked[0].ID)
+		assert.Equal(float32(0.8), reranked[0].Score)
+		assert.Equal(float32(2), reranked[0].RerankScore)
+		assert.Equal("c", reranked[1].ID)
+		assert.Equal("a", reranked[2].ID)
+
+		// nil matches are skipped
+		withNil := &QueryResponse{Matches: []*QueryVector{nil, queryResp.Matches[0], nil, queryResp.Matches[1]}}
+		reranked, err = c.RerankMatches(context.Background(), withNil, RerankMatchesParams{
+			Model:     "reranker",
+			Query:     "quick fox",
+			TextField: "body",
+		})
+		require.NoError(err)
+		require.Len(reranked, 2)
+		assert.Equal("b", reranked[0].ID)
+		assert.Equal("a", reranked[1].ID)
+
+		reranked, err = c.RerankMatches(context.Background(), &QueryResponse{Matches: []*QueryVector{nil}}, RerankMatchesParams{
+			Model:     "reranker",
+			Query:     "quick fox",
+			TextField: "body",
+		})
+		require.NoError(err)
+		assert.Empty(reranked)
+
+		queryResp.Matches[0].Metadata = nil
+		_, err = c.RerankMatches(context.Background(), queryResp, RerankMatchesParams{
+			Model:     "reranker",
+			Query:     "quick fox",
+			TextField: "body",
+		})
+		require.ErrorIs(err, ErrInvalidParams)
+		assert.Contains(err.Error(), `"a"`)
+	})
+
+	t.Run("InvalidParams", func(t *testing.T) {
+		_, err := c.Rerank(context.Background(), RerankParams{Model: "reranker", Query: "q"})
+		require.ErrorIs(t, err, ErrInvalidParams)
+	})
+}
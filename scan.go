@@ -0,0 +1,159 @@
+package pinecone
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+const defaultScanConcurrency = 4
+
+// ScanParams represents the parameters of a scan.
+type ScanParams struct {
+	Namespace string
+	// Only the vectors whose IDs start with the prefix are scanned.
+	Prefix string
+	// Whether the dense and sparse values are yielded. Metadata is always
+	// yielded.
+	IncludeValues bool
+	// The number of IDs listed per page, at most 100. Defaults to 100.
+	PageSize int
+	// The number of fetch requests sent in parallel for each page. Defaults
+	// to 4.
+	Concurrency int
+	// The cursor to resume a previous scan from, as passed to Checkpoint.
+	// Empty starts from the beginning.
+	Cursor string
+	// Called with the cursor of the next page once every vector of a page
+	// has been yielded. Persist it to resume a scan that stopped halfway.
+	// done is true once the scan is complete, with an empty cursor, which
+	// would start the scan over if passed back as Cursor: persist done as
+	// well, and do not resume a scan that is done.
+	Checkpoint func(cursor string, done bool) error
+}
+
+// Scan walks every vector of a namespace, page by page through
+// ListVectorIDs, and fetches the vectors of each page in parallel chunks
+// through FetchVectors. Vectors are passed to fn one at a time in the order
+// they are listed, and vectors deleted between listing and fetching are
+// skipped. Scan stops at the first error, including errors returned by fn
+// and Checkpoint.
+//
+// Scans resumed from a cursor continue at the start of the page that was
+// being yielded, so the vectors of that page may be yielded twice.
+func (ic *IndexClient) Scan(ctx context.Context, params ScanParams, fn func(vector *Vector) error) error {
+	if err := validateScanParams(params); err != nil {
+		return err
+	}
+
+	pageSize := params.PageSize
+	if pageSize == 0 {
+		pageSize = MaxListVectorIDsLimit
+	}
+
+	concurrency := params.Concurrency
+	if concurrency == 0 {
+		concurrency = defaultScanConcurrency
+	}
+
+	cursor := params.Cursor
+	for {
+		page, err := ic.ListVectorIDs(ctx, ListVectorIDsParams{
+			Namespace:       params.Namespace,
+			Prefix:          params.Prefix,
+			Limit:           pageSize,
+			PaginationToken: cursor,
+		})
+		if err != nil {
+			return err
+		}
+
+		vectors, err := ic.fetchScanPage(ctx, params.Namespace, page.IDs, concurrency)
+		if err != nil {
+			return err
+		}
+
+		for _, id := range page.IDs {
+			v, ok := vectors[id]
+			if !ok {
+				continue
+			}
+
+			if !params.IncludeValues {
+				v.Values = nil
+				v.SparseValues = nil
+			}
+
+			if err := fn(v); err != nil {
+				return err
+			}
+		}
+
+		cursor = page.NextPaginationToken
+		if params.Checkpoint != nil {
+			if err := params.Checkpoint(cursor, cursor == ""); err != nil {
+				return err
+			}
+		}
+
+		if cursor == "" {
+			return nil
+		}
+	}
+}
+
+// fetchScanPage fetches the vectors of a page, split in up to concurrency
+// chunks fetched in parallel.
+func (ic *IndexClient) fetchScanPage(ctx context.Context, namespace string, ids []string, concurrency int) (map[string]*Vector, error) {
+	vectors := make(map[string]*Vector, len(ids))
+	if len(ids) == 0 {
+		return vectors, nil
+	}
+
+	chunkSize := (len(ids) + concurrency - 1) / concurrency
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	for start := 0; start < len(ids); start += chunkSize {
+		end := start + chunkSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+
+		wg.Add(1)
+		go func(chunk []string) {
+			defer wg.Done()
+
+			resp, err := ic.FetchVectors(ctx, FetchVectorsParams{IDs: chunk, Namespace: namespace})
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+					cancel()
+				}
+				return
+			}
+
+			for id, v := range resp.Vectors {
+				vectors[id] = v
+			}
+		}(ids[start:end])
+	}
+
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, fmt.Errorf("failed to fetch vectors: %w", firstErr)
+	}
+
+	return vectors, nil
+}
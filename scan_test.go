@@ -0,0 +1,144 @@
+package pinecone
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndexClient_Scan(t *testing.T) {
+	index, server := newFakeIndexServer(t)
+	defer server.Close()
+
+	ic := newTestIndexClient(server.URL)
+
+	vectors := make([]*Vector, 0, 25)
+	for i := 0; i < 25; i++ {
+		vectors = append(vectors, &Vector{ID: fmt.Sprintf("doc%02d", i), Values: []float32{float32(i), 1}, Metadata: map[string]any{"i": float64(i)}})
+	}
+	vectors = append(vectors, &Vector{ID: "other", Values: []float32{1, 1}})
+
+	_, err := ic.UpsertVectors(context.Background(), UpsertVectorsParams{Vectors: vectors, Namespace: "ns"})
+	require.NoError(t, err)
+	require.Equal(t, 26, index.count("ns"))
+
+	t.Run("yields every vector in order", func(t *testing.T) {
+		assert := assert.New(t)
+		require := require.New(t)
+
+		var ids []string
+		var checkpoints []string
+		var done []bool
+		err := ic.Scan(context.Background(), ScanParams{
+			Namespace:     "ns",
+			Prefix:        "doc",
+			IncludeValues: true,
+			PageSize:      10,
+			Concurrency:   3,
+			Checkpoint: func(cursor string, isDone bool) error {
+				checkpoints = append(checkpoints, cursor)
+				done = append(done, isDone)
+				return nil
+			},
+		}, func(v *Vector) error {
+			ids = append(ids, v.ID)
+			assert.Len(v.Values, 2)
+			assert.NotNil(v.Metadata)
+			return nil
+		})
+		require.NoError(err)
+		require.Len(ids, 25)
+		assert.Equal("doc00", ids[0])
+		assert.Equal("doc24", ids[24])
+		assert.Equal([]string{"doc09", "doc19", ""}, checkpoints)
+		assert.Equal([]bool{false, false, true}, done)
+	})
+
+	t.Run("omits values", func(t *testing.T) {
+		require := require.New(t)
+
+		err := ic.Scan(context.Background(), ScanParams{Namespace: "ns"}, func(v *Vector) error {
+			require.Nil(v.Values)
+			return nil
+		})
+		require.NoError(err)
+	})
+
+	t.Run("resumes from a cursor", func(t *testing.T) {
+		assert := assert.New(t)
+		require := require.New(t)
+
+		errStop := errors.New("stop")
+		var cursor string
+		var seen int
+		err := ic.Scan(context.Background(), ScanParams{
+			Namespace:  "ns",
+			Prefix:     "doc",
+			PageSize:   10,
+			Checkpoint: func(c string, done bool) error { cursor = c; return nil },
+		}, func(v *Vector) error {
+			seen++
+			if seen == 15 {
+				return errStop
+			}
+			return nil
+		})
+		require.ErrorIs(err, errStop)
+		assert.Equal("doc09", cursor)
+
+		var ids []string
+		err = ic.Scan(context.Background(), ScanParams{Namespace: "ns", Prefix: "doc", PageSize: 10, Cursor: cursor}, func(v *Vector) error {
+			ids = append(ids, v.ID)
+			return nil
+		})
+		require.NoError(err)
+		require.Len(ids, 15)
+		assert.Equal("doc10", ids[0])
+	})
+
+	t.Run("resumes after completion", func(t *testing.T) {
+		assert := assert.New(t)
+		require := require.New(t)
+
+		var cursor string
+		var done bool
+		scan := func() int {
+			if done {
+				return 0
+			}
+
+			var seen int
+			err := ic.Scan(context.Background(), ScanParams{
+				Namespace:  "ns",
+				Prefix:     "doc",
+				PageSize:   10,
+				Cursor:     cursor,
+				Checkpoint: func(c string, d bool) error { cursor, done = c, d; return nil },
+			}, func(v *Vector) error {
+				seen++
+				return nil
+			})
+			require.NoError(err)
+
+			return seen
+		}
+
+		assert.Equal(25, scan())
+		assert.True(done)
+		assert.Empty(cursor)
+		assert.Zero(scan())
+
+		// the empty cursor of a complete scan starts it over
+		done = false
+		assert.Equal(25, scan())
+	})
+
+	t.Run("validates params", func(t *testing.T) {
+		err := ic.Scan(context.Background(), ScanParams{PageSize: 101}, func(v *Vector) error { return nil })
+		require.ErrorIs(t, err, ErrInvalidParams)
+	})
+}
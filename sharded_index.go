@@ -0,0 +1,391 @@
+package pinecone
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+const defaultVirtualNodes = 128
+
+// ShardedIndex spreads one logical dataset across several Pinecone indexes.
+// Writes and fetches are routed to a single shard by consistent hashing of
+// the vector ID, queries are scattered to every shard and gathered into a
+// merged top-K.
+//
+// The position of each shard is part of the hash ring, so the order of the
+// shards must stay the same for the lifetime of the dataset.
+type ShardedIndex struct {
+	shards []*IndexClient
+	metric CreateIndexMetric
+	ring   []ringNode
+}
+
+type ringNode struct {
+	hash  uint32
+	shard int
+}
+
+// NewShardedIndex creates a new sharded index over the given index clients.
+// WithMetric and WithVirtualNodes are the options taken into account.
+// Without WithMetric, the metric used to merge the matches of the shards is
+// learned from the first shard, see IndexClient.Spec.
+func NewShardedIndex(shards []*IndexClient, opts ...CallOptions) (*ShardedIndex, error) {
+	if len(shards) < 1 {
+		return nil, fmt.Errorf("%w: at least one shard is required", ErrInvalidParams)
+	}
+	for i, shard := range shards {
+		if shard == nil {
+			return nil, fmt.Errorf("%w: shard %d is nil", ErrInvalidParams, i)
+		}
+	}
+
+	appliedOptions := applyCallOptions(opts, options{vnodes: defaultVirtualNodes})
+	if appliedOptions.vnodes < 1 {
+		return nil, fmt.Errorf("%w: virtual nodes must be greater than 0", ErrInvalidParams)
+	}
+
+	ring := make([]ringNode, 0, len(shards)*appliedOptions.vnodes)
+	for i := range shards {
+		for v := 0; v < appliedOptions.vnodes; v++ {
+			ring = append(ring, ringNode{
+				hash:  hashString(strconv.Itoa(i) + "#" + strconv.Itoa(v)),
+				shard: i,
+			})
+		}
+	}
+
+	sort.Slice(ring, func(i, j int) bool {
+		if ring[i].hash == ring[j].hash {
+			return ring[i].shard < ring[j].shard
+		}
+
+		return ring[i].hash < ring[j].hash
+	})
+
+	return &ShardedIndex{
+		shards: shards,
+		metric: appliedOptions.metric,
+		ring:   ring,
+	}, nil
+}
+
+// Shards returns the index clients of the shards.
+func (si *ShardedIndex) Shards() []*IndexClient {
+	return si.shards
+}
+
+// ShardFor returns the position of the shard that owns the given vector ID.
+func (si *ShardedIndex) ShardFor(id string) int {
+	h := hashString(id)
+	i := sort.Search(len(si.ring), func(i int) bool {
+		return si.ring[i].hash >= h
+	})
+	if i == len(si.ring) {
+		i = 0
+	}
+
+	return si.ring[i].shard
+}
+
+// UpsertVectors routes each vector to the shard that owns its ID and
+// upserts them concurrently. The upserted counts of all shards are summed.
+func (si *ShardedIndex) UpsertVectors(ctx context.Context, params UpsertVectorsParams) (*UpsertVectorsResponse, error) {
+	if err := validateUpsertVectorsParams(params); err != nil {
+		return nil, err
+	}
+
+	vectorsByShard := make(map[int][]*Vector)
+	for _, v := range params.Vectors {
+		shard := si.ShardFor(v.ID)
+		vectorsByShard[shard] = append(vectorsByShard[shard], v)
+	}
+
+	var mu sync.Mutex
+	respBody := &UpsertVectorsResponse{}
+
+	err := si.forEachShard(ctx, keysOf(vectorsByShard), func(ctx context.Context, shard int) error {
+		resp, err := si.shards[shard].UpsertVectors(ctx, UpsertVectorsParams{
+			Vectors:   vectorsByShard[shard],
+			Namespace: params.Namespace,
+		})
+		if err != nil {
+			return err
+		}
+
+		mu.Lock()
+		respBody.UpsertedCount += resp.UpsertedCount
+		mu.Unlock()
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return respBody, nil
+}
+
+// FetchVectors routes each ID to the shard that owns it and fetches them
+// concurrently. The vectors of all shards are merged into one response.
+func (si *ShardedIndex) FetchVectors(ctx context.Context, params FetchVectorsParams) (*FetchVectorsResponse, error) {
+	if err := validateFetchVectorsParams(params); err != nil {
+		return nil, err
+	}
+
+	idsByShard := si.groupIDs(params.IDs)
+
+	var mu sync.Mutex
+	respBody := &FetchVectorsResponse{
+		Vectors:   make(map[string]*Vector),
+		Namespace: params.Namespace,
+	}
+
+	err := si.forEachShard(ctx, keysOf(idsByShard), func(ctx context.Context, shard int) error {
+		resp, err := si.shards[shard].FetchVectors(ctx, FetchVectorsParams{
+			IDs:       idsByShard[shard],
+			Namespace: params.Namespace,
+		})
+		if err != nil {
+			return err
+		}
+
+		mu.Lock()
+		for id, v := range resp.Vectors {
+			respBody.Vectors[id] = v
+		}
+		mu.Unlock()
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return respBody, nil
+}
+
+// UpdateVector routes the update to the shard that owns the vector ID.
+func (si *ShardedIndex) UpdateVector(ctx context.Context, params UpdateVectorParams) error {
+	if err := validateUpdateVectorParams(params); err != nil {
+		return err
+	}
+
+	return si.shards[si.ShardFor(params.ID)].UpdateVector(ctx, params)
+}
+
+// DeleteVectors routes each ID to the shard that owns it. Deletes by filter
+// or with DeleteAll are sent to every shard.
+func (si *ShardedIndex) DeleteVectors(ctx context.Context, params DeleteVectorsParams) error {
+	if err := validateDeleteVectorsParams(params); err != nil {
+		return err
+	}
+
+	if len(params.IDs) == 0 {
+		return si.forEachShard(ctx, si.allShards(), func(ctx context.Context, shard int) error {
+			return si.shards[shard].DeleteVectors(ctx, params)
+		})
+	}
+
+	idsByShard := si.groupIDs(params.IDs)
+
+	return si.forEachShard(ctx, keysOf(idsByShard), func(ctx context.Context, shard int) error {
+		shardParams := params
+		shardParams.IDs = idsByShard[shard]
+
+		return si.shards[shard].DeleteVectors(ctx, shardParams)
+	})
+}
+
+// Query scatters the query to every shard and gathers the matches into a
+// merged top-K ordered by the index metric. When querying by ID, the vector
+// is fetched from the shard that owns it first, and its values are used to
+// query every shard.
+func (si *ShardedIndex) Query(ctx context.Context, params QueryParams) (*QueryResponse, error) {
+	if err := validateQueryParams(params); err != nil {
+		return nil, err
+	}
+
+	metric := si.metric
+	if metric == "" {
+		var err error
+		metric, err = si.shards[0].metric(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to learn the index metric: %w", err)
+		}
+	}
+
+	if params.ID != "" {
+		resp, err := si.shards[si.ShardFor(params.ID)].FetchVectors(ctx, FetchVectorsParams{
+			IDs:       []string{params.ID},
+			Namespace: params.Namespace,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		v, ok := resp.Vectors[params.ID]
+		if !ok || v == nil {
+			return nil, fmt.Errorf("%w: vector %q not found", ErrInvalidParams, params.ID)
+		}
+
+		params.ID = ""
+		params.Vector = v.Values
+		if params.SparseVector == nil {
+			params.SparseVector = v.SparseValues
+		}
+	}
+
+	var mu sync.Mutex
+	respBody := &QueryResponse{
+		Matches:   make([]*QueryVector, 0),
+		Namespace: params.Namespace,
+	}
+
+	err := si.forEachShard(ctx, si.allShards(), func(ctx context.Context, shard int) error {
+		resp, err := si.shards[shard].Query(ctx, params)
+		if err != nil {
+			return err
+		}
+
+		mu.Lock()
+		for _, match := range resp.Matches {
+			if match != nil {
+				respBody.Matches = append(respBody.Matches, match)
+			}
+		}
+		mu.Unlock()
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(respBody.Matches, func(i, j int) bool {
+		a, b := respBody.Matches[i], respBody.Matches[j]
+		if a.Score == b.Score {
+			return a.ID < b.ID
+		}
+
+		return scoreLess(metric, b.Score, a.Score)
+	})
+
+	if int64(len(respBody.Matches)) > params.TopK {
+		respBody.Matches = respBody.Matches[:params.TopK]
+	}
+
+	return respBody, nil
+}
+
+// DescribeIndexStats aggregates the index stats of every shard. Vector
+// counts are summed, and the index fullness is the one of the fullest shard.
+func (si *ShardedIndex) DescribeIndexStats(ctx context.Context, params DescribeIndexStatsParams) (*DescribeIndexStatsResponse, error) {
+	var mu sync.Mutex
+	respBody := &DescribeIndexStatsResponse{
+		Namespaces: make(map[string]*VectorCount),
+	}
+
+	err := si.forEachShard(ctx, si.allShards(), func(ctx context.Context, shard int) error {
+		resp, err := si.shards[shard].DescribeIndexStats(ctx, params)
+		if err != nil {
+			return err
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		if respBody.Dimensions == 0 {
+			respBody.Dimensions = resp.Dimensions
+		}
+		if resp.IndexFullness > respBody.IndexFullness {
+			respBody.IndexFullness = resp.IndexFullness
+		}
+		respBody.TotalVectorCount += resp.TotalVectorCount
+		for namespace, count := range resp.Namespaces {
+			if count == nil {
+				continue
+			}
+			if _, ok := respBody.Namespaces[namespace]; !ok {
+				respBody.Namespaces[namespace] = &VectorCount{}
+			}
+			respBody.Namespaces[namespace].VectorCount += count.VectorCount
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return respBody, nil
+}
+
+// groupIDs groups the given IDs by the shard that owns them.
+func (si *ShardedIndex) groupIDs(ids []string) map[int][]string {
+	idsByShard := make(map[int][]string)
+	for _, id := range ids {
+		shard := si.ShardFor(id)
+		idsByShard[shard] = append(idsByShard[shard], id)
+	}
+
+	return idsByShard
+}
+
+func (si *ShardedIndex) allShards() []int {
+	shards := make([]int, len(si.shards))
+	for i := range si.shards {
+		shards[i] = i
+	}
+
+	return shards
+}
+
+// forEachShard calls fn for each of the given shards concurrently. The
+// context passed to fn is canceled as soon as one of the calls fails, and
+// the errors of all failed shards are joined.
+func (si *ShardedIndex) forEachShard(ctx context.Context, shards []int, fn func(ctx context.Context, shard int) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	errs := make([]error, len(shards))
+
+	var wg sync.WaitGroup
+	for i, shard := range shards {
+		wg.Add(1)
+		go func(i, shard int) {
+			defer wg.Done()
+
+			if err := fn(ctx, shard); err != nil {
+				errs[i] = fmt.Errorf("shard %d: %w", shard, err)
+				cancel()
+			}
+		}(i, shard)
+	}
+
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+func keysOf[T any](m map[int]T) []int {
+	keys := make([]int, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Ints(keys)
+
+	return keys
+}
+
+func hashString(s string) uint32 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(s))
+
+	return h.Sum32()
+}
@@ -0,0 +1,303 @@
+package pinecone
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeIndex is a minimal in-memory stand-in for a Pinecone index, serving
+// the vector endpoints over a local test server.
+type fakeIndex struct {
+	mu      sync.Mutex
+	vectors map[string]map[string]*Vector
+}
+
+func newFakeIndexServer(t *testing.T) (*fakeIndex, *httptest.Server) {
+	index := &fakeIndex{vectors: make(map[string]map[string]*Vector)}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/vectors/upsert", func(w http.ResponseWriter, r *http.Request) {
+		var params UpsertVectorsParams
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&params))
+
+		index.mu.Lock()
+		defer index.mu.Unlock()
+
+		if index.vectors[params.Namespace] == nil {
+			index.vectors[params.Namespace] = make(map[string]*Vector)
+		}
+		for _, v := range params.Vectors {
+			index.vectors[params.Namespace][v.ID] = v
+		}
+
+		_ = json.NewEncoder(w).Encode(UpsertVectorsResponse{UpsertedCount: len(params.Vectors)})
+	})
+	mux.HandleFunc("/vectors/fetch", func(w http.ResponseWriter, r *http.Request) {
+		namespace := r.URL.Query().Get("namespace")
+
+		index.mu.Lock()
+		defer index.mu.Unlock()
+
+		respBody := FetchVectorsResponse{Vectors: make(map[string]*Vector), Namespace: namespace}
+		for _, id := range r.URL.Query()["ids"] {
+			if v, ok := index.vectors[namespace][id]; ok {
+				respBody.Vectors[id] = v
+			}
+		}
+
+		_ = json.NewEncoder(w).Encode(respBody)
+	})
+	mux.HandleFunc("/vectors/list", func(w http.ResponseWriter, r *http.Request) {
+		namespace := r.URL.Query().Get("namespace")
+		prefix := r.URL.Query().Get("prefix")
+		token := r.URL.Query().Get("paginationToken")
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+		index.mu.Lock()
+		defer index.mu.Unlock()
+
+		ids := make([]string, 0)
+		for id := range index.vectors[namespace] {
+			if strings.HasPrefix(id, prefix) && id > token {
+				ids = append(ids, id)
+			}
+		}
+		sort.Strings(ids)
+
+		respBody := listVectorIDsResponseBody{Namespace: namespace}
+		if limit > 0 && len(ids) > limit {
+			ids = ids[:limit]
+			respBody.Pagination = &struct {
+				Next string `json:"next"`
+			}{Next: ids[limit-1]}
+		}
+		for _, id := range ids {
+			respBody.Vectors = append(respBody.Vectors, struct {
+				ID string `json:"id"`
+			}{ID: id})
+		}
+
+		_ = json.NewEncoder(w).Encode(respBody)
+	})
+	mux.HandleFunc("/vectors/update", func(w http.ResponseWriter, r *http.Request) {
+		var params UpdateVectorParams
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&params))
+
+		index.mu.Lock()
+		defer index.mu.Unlock()
+
+		v, ok := index.vectors[params.Namespace][params.ID]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if params.Values != nil {
+			v.Values = params.Values
+		}
+
+		_, _ = w.Write([]byte("{}"))
+	})
+	mux.HandleFunc("/vectors/delete", func(w http.ResponseWriter, r *http.Request) {
+		var params DeleteVectorsParams
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&params))
+
+		index.mu.Lock()
+		defer index.mu.Unlock()
+
+		if params.DeleteAll {
+			delete(index.vectors, params.Namespace)
+		}
+		for _, id := range params.IDs {
+			delete(index.vectors[params.Namespace], id)
+		}
+
+		_, _ = w.Write([]byte("{}"))
+	})
+	mux.HandleFunc("/query", func(w http.ResponseWriter, r *http.Request) {
+		var params QueryParams
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&params))
+
+		index.mu.Lock()
+		defer index.mu.Unlock()
+
+		matches := make([]*QueryVector, 0)
+		for _, v := range index.vectors[params.Namespace] {
+			var score float32
+			for i := range v.Values {
+				if i < len(params.Vector) {
+					score += v.Values[i] * params.Vector[i]
+				}
+			}
+			matches = append(matches, &QueryVector{Vector: Vector{ID: v.ID}, Score: score})
+		}
+		sort.Slice(matches, func(i, j int) bool { return matches[i].Score > matches[j].Score })
+		if int64(len(matches)) > params.TopK {
+			matches = matches[:params.TopK]
+		}
+
+		_ = json.NewEncoder(w).Encode(QueryResponse{Matches: matches, Namespace: params.Namespace})
+	})
+	mux.HandleFunc("/describe_index_stats", func(w http.ResponseWriter, r *http.Request) {
+		index.mu.Lock()
+		defer index.mu.Unlock()
+
+		respBody := DescribeIndexStatsResponse{Namespaces: make(map[string]*VectorCount), Dimensions: 2}
+		for namespace, vectors := range index.vectors {
+			respBody.Namespaces[namespace] = &VectorCount{VectorCount: int64(len(vectors))}
+			respBody.TotalVectorCount += int64(len(vectors))
+		}
+
+		_ = json.NewEncoder(w).Encode(respBody)
+	})
+
+	return index, httptest.NewServer(mux)
+}
+
+func (f *fakeIndex) count(namespace string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return len(f.vectors[namespace])
+}
+
+func TestShardedIndex(t *testing.T) {
+	require := require.New(t)
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	fakes := make([]*fakeIndex, 3)
+	shards := make([]*IndexClient, 3)
+	for i := range shards {
+		fake, server := newFakeIndexServer(t)
+		defer server.Close()
+
+		fakes[i] = fake
+		shards[i] = newTestIndexClient(server.URL)
+	}
+
+	si, err := NewShardedIndex(shards, WithMetric(CreateIndexMetricDotProduct))
+	require.NoError(err)
+
+	vectors := make([]*Vector, 0, 90)
+	for i := 0; i < 90; i++ {
+		vectors = append(vectors, &Vector{ID: fmt.Sprintf("vec-%d", i), Values: []float32{float32(i), 1}})
+	}
+
+	t.Run("UpsertVectors", func(t *testing.T) {
+		resp, err := si.UpsertVectors(ctx, UpsertVectorsParams{Vectors: vectors, Namespace: "ns"})
+		require.NoError(err)
+		assert.Equal(90, resp.UpsertedCount)
+
+		for i, fake := range fakes {
+			assert.NotZero(fake.count("ns"), "shard %d received no vectors", i)
+		}
+		for _, v := range vectors {
+			_, ok := fakes[si.ShardFor(v.ID)].vectors["ns"][v.ID]
+			assert.True(ok, "vector %s is not on its shard", v.ID)
+		}
+	})
+
+	t.Run("FetchVectors", func(t *testing.T) {
+		resp, err := si.FetchVectors(ctx, FetchVectorsParams{IDs: []string{"vec-1", "vec-2", "vec-3", "missing"}, Namespace: "ns"})
+		require.NoError(err)
+		assert.Len(resp.Vectors, 3)
+		assert.Equal([]float32{2, 1}, resp.Vectors["vec-2"].Values)
+	})
+
+	t.Run("UpdateVector", func(t *testing.T) {
+		err := si.UpdateVector(ctx, UpdateVectorParams{ID: "vec-5", Values: []float32{500, 1}, Namespace: "ns"})
+		require.NoError(err)
+
+		resp, err := si.FetchVectors(ctx, FetchVectorsParams{IDs: []string{"vec-5"}, Namespace: "ns"})
+		require.NoError(err)
+		assert.Equal([]float32{500, 1}, resp.Vectors["vec-5"].Values)
+	})
+
+	t.Run("Query", func(t *testing.T) {
+		resp, err := si.Query(ctx, QueryParams{Vector: []float32{1, 0}, TopK: 3, Namespace: "ns"})
+		require.NoError(err)
+		require.Len(resp.Matches, 3)
+		assert.Equal("vec-5", resp.Matches[0].ID)
+		assert.Equal("vec-89", resp.Matches[1].ID)
+		assert.Equal("vec-88", resp.Matches[2].ID)
+
+		resp, err = si.Query(ctx, QueryParams{ID: "vec-10", TopK: 1, Namespace: "ns"})
+		require.NoError(err)
+		require.Len(resp.Matches, 1)
+		assert.Equal("vec-5", resp.Matches[0].ID)
+	})
+
+	t.Run("DescribeIndexStats", func(t *testing.T) {
+		resp, err := si.DescribeIndexStats(ctx, DescribeIndexStatsParams{})
+		require.NoError(err)
+		assert.Equal(int64(90), resp.TotalVectorCount)
+		assert.Equal(int64(90), resp.Namespaces["ns"].VectorCount)
+		assert.Equal(int64(2), resp.Dimensions)
+	})
+
+	t.Run("DeleteVectors", func(t *testing.T) {
+		err := si.DeleteVectors(ctx, DeleteVectorsParams{IDs: []string{"vec-1", "vec-2"}, Namespace: "ns"})
+		require.NoError(err)
+
+		resp, err := si.DescribeIndexStats(ctx, DescribeIndexStatsParams{})
+		require.NoError(err)
+		assert.Equal(int64(88), resp.TotalVectorCount)
+
+		err = si.DeleteVectors(ctx, DeleteVectorsParams{DeleteAll: true, Namespace: "ns"})
+		require.NoError(err)
+
+		resp, err = si.DescribeIndexStats(ctx, DescribeIndexStatsParams{})
+		require.NoError(err)
+		assert.Zero(resp.TotalVectorCount)
+	})
+
+	t.Run("ConsistentRouting", func(t *testing.T) {
+		again, err := NewShardedIndex(shards)
+		require.NoError(err)
+		for _, v := range vectors {
+			assert.Equal(si.ShardFor(v.ID), again.ShardFor(v.ID))
+		}
+	})
+
+	t.Run("InvalidParams", func(t *testing.T) {
+		_, err := NewShardedIndex(nil)
+		assert.ErrorIs(err, ErrInvalidParams)
+	})
+}
+
+func TestShardedIndex_IndexMetric(t *testing.T) {
+	// every shard returns one match, scored by euclidean distance
+	shards := make([]*IndexClient, 2)
+	for i, score := range []float32{0.5, 0.1} {
+		i, score := i, score
+		mux := http.NewServeMux()
+		mux.HandleFunc("/databases/euclidean-index", func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewEncoder(w).Encode(DescribeIndexResponse{Database: Database{Name: "euclidean-index", Metric: "euclidean", Dimension: 2}})
+		})
+		mux.HandleFunc("/query", func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewEncoder(w).Encode(QueryResponse{Matches: []*QueryVector{{Vector: Vector{ID: fmt.Sprintf("shard-%d", i)}, Score: score}}})
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		shards[i] = newTestIndexClient(server.URL, WithIndexName("euclidean-index"))
+	}
+
+	si, err := NewShardedIndex(shards)
+	require.NoError(t, err)
+
+	resp, err := si.Query(context.Background(), QueryParams{Vector: []float32{1, 0}, TopK: 2})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"shard-1", "shard-0"}, []string{resp.Matches[0].ID, resp.Matches[1].ID})
+}
@@ -0,0 +1,432 @@
+package pinecone
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// ErrUnsupportedMetadataType is returned when a metadata struct has a field
+// of a type Pinecone cannot store as metadata.
+var ErrUnsupportedMetadataType = errors.New("unsupported metadata type")
+
+// TypedVector represents a vector whose metadata is mapped to the struct M.
+type TypedVector[M any] struct {
+	ID           string
+	Values       []float32
+	SparseValues *SparseVector
+	Metadata     M
+}
+
+// TypedMatch represents a scored vector whose metadata is mapped to the struct M.
+type TypedMatch[M any] struct {
+	TypedVector[M]
+	Score float32
+}
+
+// UpsertTypedVectorsParams represents the parameters for a typed upsert vectors request.
+type UpsertTypedVectorsParams[M any] struct {
+	Vectors   []*TypedVector[M]
+	Namespace string
+}
+
+// TypedFetchVectorsResponse represents the response from a typed fetch vectors request.
+type TypedFetchVectorsResponse[M any] struct {
+	Vectors   map[string]*TypedVector[M]
+	Namespace string
+}
+
+// TypedIndex wraps an IndexClient to map vector metadata to and from the
+// struct M. Fields are mapped by their `pinecone:"name"` tag, or by their
+// name when untagged; `pinecone:"-"` skips a field and `omitempty` omits
+// zero values. Fields must be strings, numbers, booleans, lists of strings,
+// or pointers to those, which are the metadata types Pinecone supports.
+type TypedIndex[M any] struct {
+	ic *IndexClient
+}
+
+// NewTypedIndex creates a typed wrapper around the index client. It fails if
+// M is not a struct, or has fields of types Pinecone does not support.
+func NewTypedIndex[M any](ic *IndexClient) (*TypedIndex[M], error) {
+	if ic == nil {
+		return nil, fmt.Errorf("%w: index client is required", ErrInvalidParams)
+	}
+
+	var zero M
+	if _, err := metadataFieldsOf(reflect.TypeOf(zero)); err != nil {
+		return nil, err
+	}
+
+	return &TypedIndex[M]{ic: ic}, nil
+}
+
+// UpsertVectors marshals the metadata of the vectors and upserts them.
+func (ti *TypedIndex[M]) UpsertVectors(ctx context.Context, params UpsertTypedVectorsParams[M]) (*UpsertVectorsResponse, error) {
+	vectors := make([]*Vector, len(params.Vectors))
+	for i, v := range params.Vectors {
+		if v == nil {
+			return nil, fmt.Errorf("%w: vector %d is nil", ErrInvalidParams, i)
+		}
+
+		metadata, err := MarshalMetadata(v.Metadata)
+		if err != nil {
+			return nil, fmt.Errorf("vector %q: %w", v.ID, err)
+		}
+
+		vectors[i] = &Vector{
+			ID:           v.ID,
+			Values:       v.Values,
+			SparseValues: v.SparseValues,
+			Metadata:     metadata,
+		}
+	}
+
+	return ti.ic.UpsertVectors(ctx, UpsertVectorsParams{
+		Vectors:   vectors,
+		Namespace: params.Namespace,
+	})
+}
+
+// FetchVectors fetches the vectors and unmarshals their metadata.
+func (ti *TypedIndex[M]) FetchVectors(ctx context.Context, params FetchVectorsParams) (*TypedFetchVectorsResponse[M], error) {
+	resp, err := ti.ic.FetchVectors(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody := &TypedFetchVectorsResponse[M]{
+		Vectors:   make(map[string]*TypedVector[M], len(resp.Vectors)),
+		Namespace: resp.Namespace,
+	}
+	for id, v := range resp.Vectors {
+		if v == nil {
+			continue
+		}
+
+		typed, err := newTypedVector[M](v)
+		if err != nil {
+			return nil, err
+		}
+
+		respBody.Vectors[id] = typed
+	}
+
+	return respBody, nil
+}
+
+// Query performs a query request with metadata included, and unmarshals the
+// metadata of the matches.
+func (ti *TypedIndex[M]) Query(ctx context.Context, params QueryParams) ([]TypedMatch[M], error) {
+	params.IncludeMetadata = true
+
+	resp, err := ti.ic.Query(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := make([]TypedMatch[M], 0, len(resp.Matches))
+	for _, match := range resp.Matches {
+		if match == nil {
+			continue
+		}
+
+		typed, err := newTypedVector[M](&match.Vector)
+		if err != nil {
+			return nil, err
+		}
+
+		matches = append(matches, TypedMatch[M]{
+			TypedVector: *typed,
+			Score:       match.Score,
+		})
+	}
+
+	return matches, nil
+}
+
+func newTypedVector[M any](v *Vector) (*TypedVector[M], error) {
+	typed := &TypedVector[M]{
+		ID:           v.ID,
+		Values:       v.Values,
+		SparseValues: v.SparseValues,
+	}
+	if err := UnmarshalMetadata(v.Metadata, &typed.Metadata); err != nil {
+		return nil, fmt.Errorf("vector %q: %w", v.ID, err)
+	}
+
+	return typed, nil
+}
+
+// MarshalMetadata converts a struct, or a pointer to a struct, into vector
+// metadata, following the mapping rules of TypedIndex. A nil value, or a nil
+// pointer, has no metadata.
+func MarshalMetadata(v any) (map[string]any, error) {
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() {
+		return nil, nil
+	}
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil, nil
+		}
+
+		rv = rv.Elem()
+	}
+
+	fields, err := metadataFieldsOf(rv.Type())
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := make(map[string]any, len(fields))
+	for _, field := range fields {
+		fv := rv.FieldByIndex(field.index)
+		if field.kind == reflect.Pointer {
+			if fv.IsNil() {
+				continue
+			}
+
+			fv = fv.Elem()
+		}
+		if field.omitEmpty && fv.IsZero() {
+			continue
+		}
+
+		switch fv.Kind() {
+		case reflect.String:
+			metadata[field.name] = fv.String()
+		case reflect.Bool:
+			metadata[field.name] = fv.Bool()
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			metadata[field.name] = fv.Int()
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			metadata[field.name] = fv.Uint()
+		case reflect.Float32, reflect.Float64:
+			metadata[field.name] = fv.Float()
+		case reflect.Slice:
+			list := make([]string, fv.Len())
+			for i := range list {
+				list[i] = fv.Index(i).String()
+			}
+
+			metadata[field.name] = list
+		}
+	}
+
+	return metadata, nil
+}
+
+// UnmarshalMetadata fills the struct pointed to by v from vector metadata,
+// following the mapping rules of TypedIndex. Numbers are converted to the
+// type of the field, and must fit into it.
+func UnmarshalMetadata(metadata map[string]any, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("%w: unmarshal target must be a non-nil pointer to a struct", ErrInvalidParams)
+	}
+
+	rv = rv.Elem()
+	fields, err := metadataFieldsOf(rv.Type())
+	if err != nil {
+		return err
+	}
+
+	for _, field := range fields {
+		value, ok := metadata[field.name]
+		if !ok || value == nil {
+			continue
+		}
+
+		fv := rv.FieldByIndex(field.index)
+		if field.kind == reflect.Pointer {
+			fv.Set(reflect.New(fv.Type().Elem()))
+			fv = fv.Elem()
+		}
+
+		if err := setMetadataValue(fv, value); err != nil {
+			return fmt.Errorf("%w: field %q: %s", ErrInvalidParams, field.name, err)
+		}
+	}
+
+	return nil
+}
+
+func setMetadataValue(fv reflect.Value, value any) error {
+	switch fv.Kind() {
+	case reflect.String:
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("cannot set %T into string", value)
+		}
+
+		fv.SetString(s)
+	case reflect.Bool:
+		b, ok := value.(bool)
+		if !ok {
+			return fmt.Errorf("cannot set %T into bool", value)
+		}
+
+		fv.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		f, ok := metadataNumber(value)
+		if !ok {
+			return fmt.Errorf("cannot set %T into %s", value, fv.Type())
+		}
+		if f != math.Trunc(f) || fv.OverflowInt(int64(f)) {
+			return fmt.Errorf("%v does not fit into %s", f, fv.Type())
+		}
+
+		fv.SetInt(int64(f))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		f, ok := metadataNumber(value)
+		if !ok {
+			return fmt.Errorf("cannot set %T into %s", value, fv.Type())
+		}
+		if f < 0 || f != math.Trunc(f) || fv.OverflowUint(uint64(f)) {
+			return fmt.Errorf("%v does not fit into %s", f, fv.Type())
+		}
+
+		fv.SetUint(uint64(f))
+	case reflect.Float32, reflect.Float64:
+		f, ok := metadataNumber(value)
+		if !ok {
+			return fmt.Errorf("cannot set %T into %s", value, fv.Type())
+		}
+
+		fv.SetFloat(f)
+	case reflect.Slice:
+		var list []string
+		switch l := value.(type) {
+		case []string:
+			list = l
+		case []any:
+			list = make([]string, len(l))
+			for i, item := range l {
+				s, ok := item.(string)
+				if !ok {
+					return fmt.Errorf("cannot set list item %T into string", item)
+				}
+
+				list[i] = s
+			}
+		default:
+			return fmt.Errorf("cannot set %T into %s", value, fv.Type())
+		}
+
+		slice := reflect.MakeSlice(fv.Type(), len(list), len(list))
+		for i, s := range list {
+			slice.Index(i).SetString(s)
+		}
+
+		fv.Set(slice)
+	}
+
+	return nil
+}
+
+// metadataNumber converts the numbers metadata may hold, float64 once
+// decoded from JSON, to float64.
+func metadataNumber(value any) (float64, bool) {
+	switch n := value.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	default:
+		return 0, false
+	}
+}
+
+type metadataField struct {
+	name      string
+	index     []int
+	kind      reflect.Kind
+	omitEmpty bool
+}
+
+var metadataFieldsCache sync.Map
+
+// metadataFieldsOf returns the metadata fields of a struct type, and fails
+// if the type is not a struct or has fields of unsupported types.
+func metadataFieldsOf(t reflect.Type) ([]metadataField, error) {
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%w: metadata must be a struct, got %v", ErrUnsupportedMetadataType, t)
+	}
+
+	if cached, ok := metadataFieldsCache.Load(t); ok {
+		return cached.([]metadataField), nil
+	}
+
+	fields := make([]metadataField, 0, t.NumField())
+	names := make(map[string]string, t.NumField())
+	for _, sf := range reflect.VisibleFields(t) {
+		if sf.Anonymous && sf.Type.Kind() == reflect.Pointer {
+			return nil, fmt.Errorf("%w: embedded field %s.%s must not be a pointer", ErrUnsupportedMetadataType, t.Name(), sf.Name)
+		}
+		if !sf.IsExported() || sf.Anonymous {
+			continue
+		}
+
+		tag := sf.Tag.Get("pinecone")
+		if tag == "-" {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = sf.Name
+		}
+
+		if !isSupportedMetadataType(sf.Type) {
+			return nil, fmt.Errorf("%w: field %s.%s of type %s, Pinecone only supports strings, numbers, booleans and lists of strings", ErrUnsupportedMetadataType, t.Name(), sf.Name, sf.Type)
+		}
+		if other, ok := names[name]; ok {
+			return nil, fmt.Errorf("%w: fields %s and %s of %s both map to %q", ErrUnsupportedMetadataType, other, sf.Name, t.Name(), name)
+		}
+
+		names[name] = sf.Name
+		fields = append(fields, metadataField{
+			name:      name,
+			index:     sf.Index,
+			kind:      sf.Type.Kind(),
+			omitEmpty: opts == "omitempty",
+		})
+	}
+
+	metadataFieldsCache.Store(t, fields)
+
+	return fields, nil
+}
+
+func isSupportedMetadataType(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+		if t.Kind() == reflect.Pointer {
+			return false
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	case reflect.Slice:
+		return t.Elem().Kind() == reflect.String
+	default:
+		return false
+	}
+}
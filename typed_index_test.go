@@ -0,0 +1,146 @@
+package pinecone
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testArticle struct {
+	Title    string   `pinecone:"title"`
+	Year     int      `pinecone:"year"`
+	Rating   float64  `pinecone:"rating,omitempty"`
+	Draft    bool     `pinecone:"draft"`
+	Tags     []string `pinecone:"tags"`
+	Views    *uint32  `pinecone:"views"`
+	Internal string   `pinecone:"-"`
+	Author   string
+}
+
+func TestMetadataMapping(t *testing.T) {
+	t.Run("Marshal", func(t *testing.T) {
+		views := uint32(42)
+		metadata, err := MarshalMetadata(testArticle{
+			Title:    "Vectors",
+			Year:     2024,
+			Tags:     []string{"ml", "db"},
+			Views:    &views,
+			Internal: "secret",
+			Author:   "someone",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{
+			"title":  "Vectors",
+			"year":   int64(2024),
+			"draft":  false,
+			"tags":   []string{"ml", "db"},
+			"views":  uint64(42),
+			"Author": "someone",
+		}, metadata)
+	})
+
+	t.Run("MarshalNil", func(t *testing.T) {
+		metadata, err := MarshalMetadata(nil)
+		require.NoError(t, err)
+		assert.Nil(t, metadata)
+
+		metadata, err = MarshalMetadata((*testArticle)(nil))
+		require.NoError(t, err)
+		assert.Nil(t, metadata)
+	})
+
+	t.Run("Unmarshal", func(t *testing.T) {
+		assert := assert.New(t)
+		require := require.New(t)
+
+		var metadata map[string]any
+		require.NoError(json.Unmarshal([]byte(`{"title":"Vectors","year":2024,"rating":4.5,"draft":true,"tags":["ml"],"views":7}`), &metadata))
+
+		var article testArticle
+		require.NoError(UnmarshalMetadata(metadata, &article))
+		assert.Equal("Vectors", article.Title)
+		assert.Equal(2024, article.Year)
+		assert.Equal(4.5, article.Rating)
+		assert.True(article.Draft)
+		assert.Equal([]string{"ml"}, article.Tags)
+		require.NotNil(article.Views)
+		assert.Equal(uint32(7), *article.Views)
+	})
+
+	t.Run("UnmarshalInvalid", func(t *testing.T) {
+		var article testArticle
+		err := UnmarshalMetadata(map[string]any{"year": 2024.5}, &article)
+		require.ErrorIs(t, err, ErrInvalidParams)
+		assert.Contains(t, err.Error(), `"year"`)
+
+		err = UnmarshalMetadata(map[string]any{"tags": []any{"a", 1.0}}, &article)
+		require.ErrorIs(t, err, ErrInvalidParams)
+	})
+
+	t.Run("UnsupportedTypes", func(t *testing.T) {
+		type nested struct {
+			Author struct{ Name string } `pinecone:"author"`
+		}
+		type numbers struct {
+			Scores []float64 `pinecone:"scores"`
+		}
+		type object struct {
+			Extra map[string]any `pinecone:"extra"`
+		}
+		type duplicate struct {
+			A string `pinecone:"name"`
+			B string `pinecone:"name"`
+		}
+
+		_, err := NewTypedIndex[nested](&IndexClient{})
+		assert.ErrorIs(t, err, ErrUnsupportedMetadataType)
+		_, err = NewTypedIndex[numbers](&IndexClient{})
+		assert.ErrorIs(t, err, ErrUnsupportedMetadataType)
+		_, err = NewTypedIndex[object](&IndexClient{})
+		assert.ErrorIs(t, err, ErrUnsupportedMetadataType)
+		_, err = NewTypedIndex[duplicate](&IndexClient{})
+		assert.ErrorIs(t, err, ErrUnsupportedMetadataType)
+		_, err = NewTypedIndex[string](&IndexClient{})
+		assert.ErrorIs(t, err, ErrUnsupportedMetadataType)
+	})
+}
+
+func TestTypedIndex(t *testing.T) {
+	_, server := newFakeIndexServer(t)
+	defer server.Close()
+
+	ti, err := NewTypedIndex[testArticle](newTestIndexClient(server.URL))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	t.Run("UpsertVectors", func(t *testing.T) {
+		resp, err := ti.UpsertVectors(ctx, UpsertTypedVectorsParams[testArticle]{
+			Vectors: []*TypedVector[testArticle]{
+				{ID: "a", Values: []float32{1, 0}, Metadata: testArticle{Title: "A", Year: 2023, Tags: []string{"x"}}},
+				{ID: "b", Values: []float32{0, 1}, Metadata: testArticle{Title: "B", Year: 2024}},
+			},
+			Namespace: "articles",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 2, resp.UpsertedCount)
+	})
+
+	t.Run("FetchVectors", func(t *testing.T) {
+		resp, err := ti.FetchVectors(ctx, FetchVectorsParams{IDs: []string{"a"}, Namespace: "articles"})
+		require.NoError(t, err)
+		require.Contains(t, resp.Vectors, "a")
+		assert.Equal(t, testArticle{Title: "A", Year: 2023, Tags: []string{"x"}}, resp.Vectors["a"].Metadata)
+	})
+
+	t.Run("Query", func(t *testing.T) {
+		matches, err := ti.Query(ctx, QueryParams{Vector: []float32{0, 1}, TopK: 1, Namespace: "articles"})
+		require.NoError(t, err)
+		require.Len(t, matches, 1)
+		assert.Equal(t, "b", matches[0].ID)
+		assert.Equal(t, float32(1), matches[0].Score)
+	})
+}
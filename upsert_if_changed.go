@@ -0,0 +1,239 @@
+package pinecone
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// ContentHashMetadataKey is the metadata key reserved by UpsertIfChanged for
+// the content hash of the vectors.
+const ContentHashMetadataKey = "content_hash"
+
+const defaultUpsertIfChangedBatchSize = 100
+
+// ContentHash returns the hex SHA-256 hash of the values, sparse values and
+// metadata of a vector, ignoring the ContentHashMetadataKey metadata field.
+// Metadata fields are hashed in key order, so the hash does not depend on
+// the order of a map.
+func ContentHash(vector *Vector) string {
+	content := struct {
+		Values       []float32      `json:"values,omitempty"`
+		SparseValues *SparseVector  `json:"sparseValues,omitempty"`
+		Metadata     map[string]any `json:"metadata,omitempty"`
+	}{
+		Values:       vector.Values,
+		SparseValues: vector.SparseValues,
+	}
+	if len(vector.Metadata) > 0 {
+		content.Metadata = make(map[string]any, len(vector.Metadata))
+		for key, value := range vector.Metadata {
+			if key != ContentHashMetadataKey {
+				content.Metadata[key] = value
+			}
+		}
+	}
+
+	// the content is validated metadata, values and sparse values, which
+	// always serialize
+	data, _ := json.Marshal(content)
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:])
+}
+
+// HashStore stores the content hashes of the vectors upserted by
+// UpsertIfChanged, e.g. in a local database, sparing the fetch of the stored
+// vectors.
+type HashStore interface {
+	// Get returns the hashes of the vectors of a namespace, omitting unknown
+	// IDs.
+	Get(ctx context.Context, namespace string, ids []string) (map[string]string, error)
+	// Set records the hashes of vectors upserted into a namespace.
+	Set(ctx context.Context, namespace string, hashes map[string]string) error
+}
+
+// MemoryHashStore is a HashStore held in memory, for the lifetime of a
+// process. It is safe for concurrent use.
+type MemoryHashStore struct {
+	mu     sync.Mutex
+	hashes map[string]map[string]string
+}
+
+// NewMemoryHashStore creates an empty MemoryHashStore.
+func NewMemoryHashStore() *MemoryHashStore {
+	return &MemoryHashStore{hashes: make(map[string]map[string]string)}
+}
+
+// Get returns the hashes of the vectors of a namespace, omitting unknown IDs.
+func (s *MemoryHashStore) Get(_ context.Context, namespace string, ids []string) (map[string]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	hashes := make(map[string]string, len(ids))
+	for _, id := range ids {
+		if hash, ok := s.hashes[namespace][id]; ok {
+			hashes[id] = hash
+		}
+	}
+
+	return hashes, nil
+}
+
+// Set records the hashes of vectors upserted into a namespace.
+func (s *MemoryHashStore) Set(_ context.Context, namespace string, hashes map[string]string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.hashes[namespace] == nil {
+		s.hashes[namespace] = make(map[string]string, len(hashes))
+	}
+	for id, hash := range hashes {
+		s.hashes[namespace][id] = hash
+	}
+
+	return nil
+}
+
+// UpsertIfChangedParams represents the parameters of UpsertIfChanged.
+type UpsertIfChangedParams struct {
+	Vectors   []*Vector
+	Namespace string
+	// Where the stored hashes are read from. When nil, the vectors are
+	// fetched with FetchVectors and their hashes read from their metadata.
+	HashStore HashStore
+	// The number of vectors compared and upserted per request. Defaults to
+	// 100.
+	BatchSize int
+}
+
+// UpsertIfChangedResponse represents the outcome of UpsertIfChanged.
+type UpsertIfChangedResponse struct {
+	// The number of vectors of which the stored hash matched, and which were
+	// not sent.
+	Skipped int `json:"skipped"`
+	// The number of vectors with no stored hash.
+	Inserted int `json:"inserted"`
+	// The number of vectors of which the stored hash differed.
+	Updated int `json:"updated"`
+}
+
+// UpsertIfChanged upserts only the vectors whose content changed, to avoid
+// spending write units on re-syncs sending mostly identical vectors. The
+// content hash of every vector, see ContentHash, is stored in its metadata
+// under ContentHashMetadataKey, replacing any value of that field, and
+// compared with the hash stored by a previous upsert.
+//
+// With a HashStore, vectors unknown to the store are counted as inserted,
+// even when the index holds them. The store is only updated once their
+// upsert succeeded. The vectors passed are not modified.
+func (ic *IndexClient) UpsertIfChanged(ctx context.Context, params UpsertIfChangedParams) (*UpsertIfChangedResponse, error) {
+	if err := validateUpsertVectorsParams(UpsertVectorsParams{Vectors: params.Vectors, Namespace: params.Namespace}); err != nil {
+		return nil, err
+	}
+	if params.BatchSize < 0 {
+		return nil, fmt.Errorf("%w: batch size must not be negative", ErrInvalidParams)
+	}
+	if params.BatchSize == 0 {
+		params.BatchSize = defaultUpsertIfChangedBatchSize
+	}
+
+	seen := make(map[string]struct{}, len(params.Vectors))
+	for _, v := range params.Vectors {
+		if _, ok := seen[v.ID]; ok {
+			return nil, fmt.Errorf("%w: vector %q is upserted twice", ErrInvalidParams, v.ID)
+		}
+		seen[v.ID] = struct{}{}
+	}
+
+	response := new(UpsertIfChangedResponse)
+	for start := 0; start < len(params.Vectors); start += params.BatchSize {
+		batch := params.Vectors[start:min(start+params.BatchSize, len(params.Vectors))]
+		if err := ic.upsertBatchIfChanged(ctx, params, batch, response); err != nil {
+			return response, err
+		}
+	}
+
+	return response, nil
+}
+
+func (ic *IndexClient) upsertBatchIfChanged(ctx context.Context, params UpsertIfChangedParams, batch []*Vector, response *UpsertIfChangedResponse) error {
+	ids := make([]string, 0, len(batch))
+	for _, v := range batch {
+		ids = append(ids, v.ID)
+	}
+
+	stored, err := ic.storedContentHashes(ctx, params, ids)
+	if err != nil {
+		return err
+	}
+
+	var (
+		changed  []*Vector
+		hashes   = make(map[string]string)
+		inserted int
+		updated  int
+	)
+	for _, v := range batch {
+		hash := ContentHash(v)
+
+		storedHash, ok := stored[v.ID]
+		switch {
+		case ok && storedHash == hash:
+			response.Skipped++
+			continue
+		case ok:
+			updated++
+		default:
+			inserted++
+		}
+
+		metadata := make(map[string]any, len(v.Metadata)+1)
+		for key, value := range v.Metadata {
+			metadata[key] = value
+		}
+		metadata[ContentHashMetadataKey] = hash
+
+		changed = append(changed, &Vector{ID: v.ID, Values: v.Values, SparseValues: v.SparseValues, Metadata: metadata})
+		hashes[v.ID] = hash
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+
+	if _, err := ic.UpsertVectors(ctx, UpsertVectorsParams{Vectors: changed, Namespace: params.Namespace}); err != nil {
+		return err
+	}
+	response.Inserted += inserted
+	response.Updated += updated
+
+	if params.HashStore != nil {
+		return params.HashStore.Set(ctx, params.Namespace, hashes)
+	}
+
+	return nil
+}
+
+// storedContentHashes returns the stored hashes of the vectors of a batch,
+// an empty hash standing for a stored vector without one.
+func (ic *IndexClient) storedContentHashes(ctx context.Context, params UpsertIfChangedParams, ids []string) (map[string]string, error) {
+	if params.HashStore != nil {
+		return params.HashStore.Get(ctx, params.Namespace, ids)
+	}
+
+	resp, err := ic.FetchVectors(ctx, FetchVectorsParams{IDs: ids, Namespace: params.Namespace})
+	if err != nil {
+		return nil, err
+	}
+
+	hashes := make(map[string]string, len(resp.Vectors))
+	for id, v := range resp.Vectors {
+		hash, _ := v.Metadata[ContentHashMetadataKey].(string)
+		hashes[id] = hash
+	}
+
+	return hashes, nil
+}
@@ -0,0 +1,187 @@
+package pinecone
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContentHash(t *testing.T) {
+	assert := assert.New(t)
+
+	v := &Vector{
+		ID:           "a",
+		Values:       []float32{1, 2},
+		SparseValues: &SparseVector{Indices: []int32{1}, Values: []float32{0.5}},
+		Metadata:     map[string]any{"genre": "drama", "year": 2020},
+	}
+	hash := ContentHash(v)
+	assert.Len(hash, 64)
+
+	// the ID and the stored hash are not part of the content
+	assert.Equal(hash, ContentHash(&Vector{ID: "b", Values: v.Values, SparseValues: v.SparseValues, Metadata: map[string]any{"year": 2020, "genre": "drama", ContentHashMetadataKey: "old"}}))
+
+	assert.NotEqual(hash, ContentHash(&Vector{ID: "a", Values: []float32{1, 3}, SparseValues: v.SparseValues, Metadata: v.Metadata}))
+	assert.NotEqual(hash, ContentHash(&Vector{ID: "a", Values: v.Values, Metadata: v.Metadata}))
+	assert.NotEqual(hash, ContentHash(&Vector{ID: "a", Values: v.Values, SparseValues: v.SparseValues, Metadata: map[string]any{"genre": "drama", "year": 2021}}))
+	assert.Equal(ContentHash(&Vector{Values: []float32{1}}), ContentHash(&Vector{Values: []float32{1}, Metadata: map[string]any{}}))
+}
+
+// requestCounter counts the requests sent to a fake index server by path.
+type requestCounter struct {
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+func (c *requestCounter) wrap(handler http.Handler) http.Handler {
+	c.counts = make(map[string]int)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c.mu.Lock()
+		c.counts[r.URL.Path]++
+		c.mu.Unlock()
+
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// take returns the number of requests sent to a path and resets it.
+func (c *requestCounter) take(path string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	count := c.counts[path]
+	delete(c.counts, path)
+
+	return count
+}
+
+func TestIndexClient_UpsertIfChanged(t *testing.T) {
+	t.Run("FetchVectors", func(t *testing.T) {
+		assert := assert.New(t)
+		require := require.New(t)
+
+		index, server := newFakeIndexServer(t)
+		defer server.Close()
+		var counter requestCounter
+		server.Config.Handler = counter.wrap(server.Config.Handler)
+
+		ic := newTestIndexClient(server.URL)
+		ctx := context.Background()
+
+		vectors := []*Vector{
+			{ID: "a", Values: []float32{1, 0}},
+			{ID: "b", Values: []float32{0, 1}, Metadata: map[string]any{"genre": "drama"}},
+			{ID: "c", Values: []float32{1, 1}},
+		}
+		resp, err := ic.UpsertIfChanged(ctx, UpsertIfChangedParams{Vectors: vectors, Namespace: "ns"})
+		require.NoError(err)
+		assert.Equal(&UpsertIfChangedResponse{Inserted: 3}, resp)
+		assert.Equal(1, counter.take("/vectors/upsert"))
+		assert.Equal(ContentHash(vectors[1]), index.vectors["ns"]["b"].Metadata[ContentHashMetadataKey])
+		assert.Equal("drama", index.vectors["ns"]["b"].Metadata["genre"])
+		assert.Nil(vectors[0].Metadata)
+		assert.NotContains(vectors[1].Metadata, ContentHashMetadataKey)
+
+		resp, err = ic.UpsertIfChanged(ctx, UpsertIfChangedParams{Vectors: vectors, Namespace: "ns"})
+		require.NoError(err)
+		assert.Equal(&UpsertIfChangedResponse{Skipped: 3}, resp)
+		assert.Zero(counter.take("/vectors/upsert"))
+
+		// a vector upserted without its hash is updated
+		_, err = ic.UpsertVectors(ctx, UpsertVectorsParams{Vectors: []*Vector{{ID: "c", Values: []float32{1, 1}}}, Namespace: "ns"})
+		require.NoError(err)
+		counter.take("/vectors/upsert")
+		counter.take("/vectors/fetch")
+
+		vectors[0] = &Vector{ID: "a", Values: []float32{2, 0}}
+		vectors = append(vectors, &Vector{ID: "d", Values: []float32{2, 2}})
+		resp, err = ic.UpsertIfChanged(ctx, UpsertIfChangedParams{Vectors: vectors, Namespace: "ns", BatchSize: 2})
+		require.NoError(err)
+		assert.Equal(&UpsertIfChangedResponse{Skipped: 1, Inserted: 1, Updated: 2}, resp)
+		assert.Equal(2, counter.take("/vectors/fetch"))
+		assert.Equal(2, counter.take("/vectors/upsert"))
+		assert.Equal([]float32{2, 0}, index.vectors["ns"]["a"].Values)
+		assert.Equal(ContentHash(vectors[2]), index.vectors["ns"]["c"].Metadata[ContentHashMetadataKey])
+	})
+
+	t.Run("HashStore", func(t *testing.T) {
+		assert := assert.New(t)
+		require := require.New(t)
+
+		index, server := newFakeIndexServer(t)
+		defer server.Close()
+		var counter requestCounter
+		server.Config.Handler = counter.wrap(server.Config.Handler)
+
+		ic := newTestIndexClient(server.URL)
+		ctx := context.Background()
+		store := NewMemoryHashStore()
+
+		vectors := []*Vector{{ID: "a", Values: []float32{1, 0}}, {ID: "b", Values: []float32{0, 1}}}
+		resp, err := ic.UpsertIfChanged(ctx, UpsertIfChangedParams{Vectors: vectors, Namespace: "ns", HashStore: store})
+		require.NoError(err)
+		assert.Equal(&UpsertIfChangedResponse{Inserted: 2}, resp)
+		assert.Equal(2, index.count("ns"))
+
+		hashes, err := store.Get(ctx, "ns", []string{"a", "b", "c"})
+		require.NoError(err)
+		assert.Equal(map[string]string{"a": ContentHash(vectors[0]), "b": ContentHash(vectors[1])}, hashes)
+
+		vectors[1] = &Vector{ID: "b", Values: []float32{0, 2}}
+		resp, err = ic.UpsertIfChanged(ctx, UpsertIfChangedParams{Vectors: vectors, Namespace: "ns", HashStore: store})
+		require.NoError(err)
+		assert.Equal(&UpsertIfChangedResponse{Skipped: 1, Updated: 1}, resp)
+
+		// the hashes of other namespaces are not shared
+		resp, err = ic.UpsertIfChanged(ctx, UpsertIfChangedParams{Vectors: vectors, Namespace: "other", HashStore: store})
+		require.NoError(err)
+		assert.Equal(&UpsertIfChangedResponse{Inserted: 2}, resp)
+
+		assert.Zero(counter.take("/vectors/fetch"))
+		assert.Equal(3, counter.take("/vectors/upsert"))
+	})
+
+	t.Run("Failed", func(t *testing.T) {
+		_, server := newFakeIndexServer(t)
+		defer server.Close()
+		handler := server.Config.Handler
+		server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/vectors/upsert" {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			handler.ServeHTTP(w, r)
+		})
+
+		ic := newTestIndexClient(server.URL)
+		ctx := context.Background()
+		store := NewMemoryHashStore()
+
+		_, err := ic.UpsertIfChanged(ctx, UpsertIfChangedParams{Vectors: []*Vector{{ID: "a", Values: []float32{1, 0}}}, HashStore: store})
+		require.ErrorIs(t, err, ErrRequestFailed)
+
+		// the store only records upserted vectors
+		hashes, err := store.Get(ctx, "", []string{"a"})
+		require.NoError(t, err)
+		assert.Empty(t, hashes)
+	})
+
+	t.Run("InvalidParams", func(t *testing.T) {
+		ic := newTestIndexClient("http://localhost")
+		ctx := context.Background()
+
+		_, err := ic.UpsertIfChanged(ctx, UpsertIfChangedParams{})
+		require.ErrorIs(t, err, ErrInvalidParams)
+
+		_, err = ic.UpsertIfChanged(ctx, UpsertIfChangedParams{Vectors: []*Vector{{ID: "a", Values: []float32{1}}, {ID: "a", Values: []float32{2}}}})
+		require.ErrorIs(t, err, ErrInvalidParams)
+
+		_, err = ic.UpsertIfChanged(ctx, UpsertIfChangedParams{Vectors: []*Vector{{ID: "a", Values: []float32{1}}}, BatchSize: -1})
+		require.ErrorIs(t, err, ErrInvalidParams)
+	})
+}
@@ -3,7 +3,6 @@ package pinecone
 import (
 	"bytes"
 	"context"
-	"fmt"
 )
 
 // Vector represents a struct with shared fields for vectors
@@ -59,7 +58,7 @@ func (ic *IndexClient) DescribeIndexStats(ctx context.Context, params DescribeIn
 		if err != nil {
 			return nil, err
 		}
-		return nil, fmt.Errorf("%w: %s, status code: %d", ErrRequestFailed, buffer.String(), resp.StatusCode)
+		return nil, &RequestError{StatusCode: resp.StatusCode, Body: buffer.String()}
 	}
 
 	return &respBody, nil
@@ -96,6 +95,25 @@ func (ic *IndexClient) Query(ctx context.Context, params QueryParams) (*QueryRes
 		return nil, err
 	}
 
+	vector, adjusted, err := ic.normalizeValues("query", params.Vector)
+	if err != nil {
+		return nil, err
+	}
+
+	params.Vector = vector
+
+	if err := ic.newValuesValidator().validate(ctx, "query", params.Vector, params.SparseVector); err != nil {
+		return nil, err
+	}
+
+	cacheKey, cacheable := ic.queryCacheKey(ctx, params)
+	if cacheable {
+		var cached QueryResponse
+		if ic.options.cache.get(ctx, cacheKey, &cached) {
+			return &cached, nil
+		}
+	}
+
 	var respBody QueryResponse
 	resp, err := ic.reqClient.
 		R().
@@ -115,7 +133,14 @@ func (ic *IndexClient) Query(ctx context.Context, params QueryParams) (*QueryRes
 		if err != nil {
 			return nil, err
 		}
-		return nil, fmt.Errorf("%w: %s, status code: %d", ErrRequestFailed, buffer.String(), resp.StatusCode)
+		return nil, &RequestError{StatusCode: resp.StatusCode, Body: buffer.String()}
+	}
+
+	if cacheable {
+		ic.options.cache.set(ctx, cacheKey, &respBody)
+	}
+	if adjusted {
+		ic.normalizationAdjusted.Add(1)
 	}
 
 	return &respBody, nil
@@ -137,6 +162,8 @@ func (ic *IndexClient) DeleteVectors(ctx context.Context, params DeleteVectorsPa
 		return err
 	}
 
+	defer ic.invalidateCache(ctx, params.Namespace)
+
 	resp, err := ic.reqClient.
 		R().
 		SetContentType("application/json").
@@ -154,7 +181,7 @@ func (ic *IndexClient) DeleteVectors(ctx context.Context, params DeleteVectorsPa
 		if err != nil {
 			return err
 		}
-		return fmt.Errorf("%w: %s, status code: %d", ErrRequestFailed, buffer.String(), resp.StatusCode)
+		return &RequestError{StatusCode: resp.StatusCode, Body: buffer.String()}
 	}
 
 	return nil
@@ -179,6 +206,14 @@ func (ic *IndexClient) FetchVectors(ctx context.Context, params FetchVectorsPara
 		return nil, err
 	}
 
+	cacheKey, cacheable := ic.fetchCacheKey(ctx, params)
+	if cacheable {
+		var cached FetchVectorsResponse
+		if ic.options.cache.get(ctx, cacheKey, &cached) {
+			return &cached, nil
+		}
+	}
+
 	pathParams := buildFetchVectorPathParams(params)
 	var respBody FetchVectorsResponse
 
@@ -199,7 +234,11 @@ func (ic *IndexClient) FetchVectors(ctx context.Context, params FetchVectorsPara
 		if err != nil {
 			return nil, err
 		}
-		return nil, fmt.Errorf("%w: %s, status code: %d", ErrRequestFailed, buffer.String(), resp.StatusCode)
+		return nil, &RequestError{StatusCode: resp.StatusCode, Body: buffer.String()}
+	}
+
+	if cacheable {
+		ic.options.cache.set(ctx, cacheKey, &respBody)
 	}
 
 	return &respBody, nil
@@ -221,6 +260,19 @@ func (ic *IndexClient) UpdateVector(ctx context.Context, params UpdateVectorPara
 		return err
 	}
 
+	values, adjusted, err := ic.normalizeValues(params.ID, params.Values)
+	if err != nil {
+		return err
+	}
+
+	params.Values = values
+
+	if err := ic.newValuesValidator().validate(ctx, params.ID, params.Values, params.SparseValues); err != nil {
+		return err
+	}
+
+	defer ic.invalidateCache(ctx, params.Namespace)
+
 	resp, err := ic.reqClient.
 		R().
 		SetContentType("application/json").
@@ -238,7 +290,11 @@ func (ic *IndexClient) UpdateVector(ctx context.Context, params UpdateVectorPara
 		if err != nil {
 			return err
 		}
-		return fmt.Errorf("%w: %s, status code: %d", ErrRequestFailed, buffer.String(), resp.StatusCode)
+		return &RequestError{StatusCode: resp.StatusCode, Body: buffer.String()}
+	}
+
+	if adjusted {
+		ic.normalizationAdjusted.Add(1)
 	}
 
 	return nil
@@ -263,6 +319,22 @@ func (ic *IndexClient) UpsertVectors(ctx context.Context, params UpsertVectorsPa
 		return nil, err
 	}
 
+	vectors, adjusted, err := ic.normalizeVectors(params.Vectors)
+	if err != nil {
+		return nil, err
+	}
+
+	params.Vectors = vectors
+
+	validator := ic.newValuesValidator()
+	for _, v := range params.Vectors {
+		if err := validator.validate(ctx, v.ID, v.Values, v.SparseValues); err != nil {
+			return nil, err
+		}
+	}
+
+	defer ic.invalidateCache(ctx, params.Namespace)
+
 	var respBody UpsertVectorsResponse
 	resp, err := ic.reqClient.
 		R().
@@ -282,8 +354,82 @@ func (ic *IndexClient) UpsertVectors(ctx context.Context, params UpsertVectorsPa
 		if err != nil {
 			return nil, err
 		}
-		return nil, fmt.Errorf("%w: %s, status code: %d", ErrRequestFailed, buffer.String(), resp.StatusCode)
+		return nil, &RequestError{StatusCode: resp.StatusCode, Body: buffer.String()}
 	}
 
+	ic.normalizationAdjusted.Add(adjusted)
+
 	return &respBody, nil
 }
+
+// ListVectorIDsParams represents the parameters for a list vector IDs request.
+// See https://docs.pinecone.io/reference/api/data-plane/list for more information.
+type ListVectorIDsParams struct {
+	Namespace string
+	// Only the IDs starting with the prefix are listed.
+	Prefix string
+	// The number of IDs to return per page, at most 100. Defaults to 100.
+	Limit int
+	// The token of the page to list, as returned by the previous page.
+	PaginationToken string
+}
+
+// ListVectorIDsResponse represents the response from a list vector IDs request.
+type ListVectorIDsResponse struct {
+	IDs       []string
+	Namespace string
+	// The token of the next page, empty on the last page.
+	NextPaginationToken string
+}
+
+type listVectorIDsResponseBody struct {
+	Vectors []struct {
+		ID string `json:"id"`
+	} `json:"vectors"`
+	Pagination *struct {
+		Next string `json:"next"`
+	} `json:"pagination"`
+	Namespace string `json:"namespace"`
+}
+
+// ListVectorIDs lists a page of the vector IDs of a namespace, optionally
+// restricted to the IDs starting with a prefix.
+// See https://docs.pinecone.io/reference/api/data-plane/list for more information.
+func (ic *IndexClient) ListVectorIDs(ctx context.Context, params ListVectorIDsParams) (*ListVectorIDsResponse, error) {
+	if err := validateListVectorIDsParams(params); err != nil {
+		return nil, err
+	}
+
+	var respBody listVectorIDsResponseBody
+	resp, err := ic.reqClient.
+		R().
+		SetContentType("application/json").
+		SetSuccessResult(&respBody).
+		SetContext(ctx).
+		Get("/vectors/list?" + buildListVectorIDsPathParams(params))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !resp.IsSuccessState() {
+		buffer := new(bytes.Buffer)
+		_, err := buffer.ReadFrom(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		return nil, &RequestError{StatusCode: resp.StatusCode, Body: buffer.String()}
+	}
+
+	ids := make([]string, 0, len(respBody.Vectors))
+	for _, v := range respBody.Vectors {
+		ids = append(ids, v.ID)
+	}
+
+	listResp := &ListVectorIDsResponse{IDs: ids, Namespace: respBody.Namespace}
+	if respBody.Pagination != nil {
+		listResp.NextPaginationToken = respBody.Pagination.Next
+	}
+
+	return listResp, nil
+}
@@ -1,8 +1,11 @@
 package pinecone
 
 import (
-	"github.com/stretchr/testify/require"
+	"math"
+	"strings"
 	"testing"
+
+	"github.com/stretchr/testify/require"
 )
 
 func TestValidators(t *testing.T) {
@@ -290,4 +293,145 @@ func TestValidators(t *testing.T) {
 			require.NoError(t, err)
 		})
 	})
+
+	t.Run("validate vector limits", func(t *testing.T) {
+		t.Run("should return error if vector id is empty", func(t *testing.T) {
+			params := UpsertVectorsParams{
+				Vectors: []*Vector{{ID: ""}},
+			}
+			err := validateUpsertVectorsParams(params)
+			require.Error(t, err)
+			if err.Error() != "invalid params: vector id is required" {
+				t.Errorf("expected: invalid params: vector id is required, got: %s", err.Error())
+			}
+		})
+
+		t.Run("should return error if vector id is too long", func(t *testing.T) {
+			params := UpsertVectorsParams{
+				Vectors: []*Vector{{ID: strings.Repeat("a", MaxVectorIDLength+1)}},
+			}
+			err := validateUpsertVectorsParams(params)
+			require.ErrorIs(t, err, ErrInvalidParams)
+			require.Contains(t, err.Error(), "id is longer than 512 characters")
+		})
+
+		t.Run("should return error if vector id is not printable ASCII", func(t *testing.T) {
+			params := UpsertVectorsParams{
+				Vectors: []*Vector{{ID: "caf\u00e9"}},
+			}
+			err := validateUpsertVectorsParams(params)
+			require.ErrorIs(t, err, ErrInvalidParams)
+			require.Contains(t, err.Error(), "printable ASCII")
+		})
+
+		t.Run("should return error if sparse values has too many non-zeros", func(t *testing.T) {
+			params := UpsertVectorsParams{
+				Vectors: []*Vector{
+					{
+						ID: "id",
+						SparseValues: &SparseVector{
+							Values:  make([]float32, MaxSparseNonZeros+1),
+							Indices: make([]int32, MaxSparseNonZeros+1),
+						},
+					},
+				},
+			}
+			err := validateUpsertVectorsParams(params)
+			require.ErrorIs(t, err, ErrInvalidParams)
+			require.Contains(t, err.Error(), `vector "id": sparse values has 1001 non-zeros`)
+		})
+	})
+
+	t.Run("validate metadata", func(t *testing.T) {
+		t.Run("should not return error if metadata types are supported", func(t *testing.T) {
+			params := UpsertVectorsParams{
+				Vectors: []*Vector{
+					{
+						ID: "id",
+						Metadata: map[string]any{
+							"string": "value",
+							"int":    1,
+							"float":  1.5,
+							"bool":   true,
+							"list":   []string{"a", "b"},
+							"any":    []any{"a", "b"},
+						},
+					},
+				},
+			}
+			err := validateUpsertVectorsParams(params)
+			require.NoError(t, err)
+		})
+
+		t.Run("should return error if metadata contains nested objects", func(t *testing.T) {
+			params := UpsertVectorsParams{
+				Vectors: []*Vector{
+					{ID: "id", Metadata: map[string]any{"nested": map[string]any{"a": 1}}},
+				},
+			}
+			err := validateUpsertVectorsParams(params)
+			require.ErrorIs(t, err, ErrInvalidParams)
+			require.Contains(t, err.Error(), `vector "id": metadata field "nested" has unsupported type map[string]interface {}`)
+		})
+
+		t.Run("should return error if metadata contains nulls", func(t *testing.T) {
+			params := UpsertVectorsParams{
+				Vectors: []*Vector{
+					{ID: "id", Metadata: map[string]any{"empty": nil}},
+				},
+			}
+			err := validateUpsertVectorsParams(params)
+			require.Error(t, err)
+			if err.Error() != `invalid params: vector "id": metadata field "empty" must not be null` {
+				t.Errorf(`expected: invalid params: vector "id": metadata field "empty" must not be null, got: %s`, err.Error())
+			}
+		})
+
+		t.Run("should return error if metadata contains lists of non-strings", func(t *testing.T) {
+			params := UpsertVectorsParams{
+				Vectors: []*Vector{
+					{ID: "id", Metadata: map[string]any{"scores": []any{"a", 1.0}}},
+				},
+			}
+			err := validateUpsertVectorsParams(params)
+			require.ErrorIs(t, err, ErrInvalidParams)
+			require.Contains(t, err.Error(), `metadata field "scores" must be a list of strings, item 1 is float64`)
+
+			params.Vectors[0].Metadata = map[string]any{"scores": []float64{1}}
+			err = validateUpsertVectorsParams(params)
+			require.ErrorIs(t, err, ErrInvalidParams)
+		})
+
+		t.Run("should return error if metadata contains non-finite numbers", func(t *testing.T) {
+			params := UpsertVectorsParams{
+				Vectors: []*Vector{
+					{ID: "id", Metadata: map[string]any{"score": math.NaN()}},
+				},
+			}
+			err := validateUpsertVectorsParams(params)
+			require.ErrorIs(t, err, ErrInvalidParams)
+			require.Contains(t, err.Error(), `metadata field "score" must be a finite number`)
+		})
+
+		t.Run("should return error if metadata exceeds the size limit", func(t *testing.T) {
+			params := UpsertVectorsParams{
+				Vectors: []*Vector{
+					{ID: "id", Metadata: map[string]any{"text": strings.Repeat("a", MaxMetadataSize)}},
+				},
+			}
+			err := validateUpsertVectorsParams(params)
+			require.ErrorIs(t, err, ErrInvalidParams)
+			require.Contains(t, err.Error(), `vector "id": metadata is 40971 bytes, the maximum is 40960`)
+		})
+
+		t.Run("should return error if update metadata is invalid", func(t *testing.T) {
+			params := UpdateVectorParams{
+				ID:          "id",
+				SetMetadata: map[string]any{"nested": []map[string]any{}},
+			}
+			err := validateUpdateVectorParams(params)
+			require.ErrorIs(t, err, ErrInvalidParams)
+			require.Contains(t, err.Error(), `vector "id": metadata field "nested"`)
+		})
+	})
 }
@@ -0,0 +1,599 @@
+// Package wal puts a durable write-ahead log in front of the writes of an
+// index, so that upserts, updates and deletes survive a crash of the process
+// or an outage of Pinecone.
+//
+// Every write is appended to a segment file in the log directory before it is
+// sent, and acknowledged in the log once the server accepted it. Opening a log
+// replays the writes a previous process left unacknowledged, and segments
+// whose writes are all acknowledged are removed as the log grows.
+//
+// Writes are sent one at a time, in the order they were logged, so that a
+// replayed write never overwrites a later one. A write may be sent twice when
+// the process crashes between its request and its acknowledgement.
+package wal
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/nekomeowww/go-pinecone"
+)
+
+const (
+	defaultSegmentSize  = 64 * 1024 * 1024
+	defaultSyncInterval = time.Second
+
+	segmentExt = ".wal"
+	headerSize = 8
+	// the maximum size of a record, as a bound when reading a torn header
+	maxRecordSize = 256 * 1024 * 1024
+)
+
+var (
+	// ErrPending is returned, along with the error of the request, when a
+	// write was logged but not acknowledged by the server. The write is kept
+	// and sent again by the next write, by Replay, or when the log is opened.
+	ErrPending = errors.New("write logged but not acknowledged")
+	// ErrCorrupt is returned when opening a log with an invalid record
+	// before its last segment. Invalid records at the end of the last
+	// segment are the result of a crash while appending, and are discarded.
+	ErrCorrupt = errors.New("corrupt write-ahead log")
+	// ErrClosed is returned when writing to a closed log.
+	ErrClosed = errors.New("write-ahead log closed")
+)
+
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+// SyncPolicy represents when the segment files are flushed to disk.
+type SyncPolicy int
+
+const (
+	// SyncAlways flushes a segment after every write is appended, before it
+	// is sent. Logged writes survive a crash of the machine.
+	SyncAlways SyncPolicy = iota
+	// SyncInterval flushes the active segment periodically. The writes of
+	// the last interval may be lost when the machine crashes.
+	SyncInterval
+	// SyncNever leaves flushing to the operating system. Logged writes
+	// survive a crash of the process only.
+	SyncNever
+)
+
+// Options represents the options of a log.
+type Options struct {
+	// The directory of the segment files, created if missing. A directory
+	// must be used by a single log at a time.
+	Dir string
+	// When the segment files are flushed to disk. Defaults to SyncAlways.
+	Sync SyncPolicy
+	// The flush interval of SyncInterval. Defaults to 1 second.
+	SyncInterval time.Duration
+	// A new segment is started once the active one reaches this many bytes.
+	// Defaults to 64 MiB.
+	SegmentSize int64
+}
+
+type operation string
+
+const (
+	operationUpsert operation = "upsert"
+	operationUpdate operation = "update"
+	operationDelete operation = "delete"
+	// acknowledges every write up to the sequence number of the record
+	operationAck operation = "ack"
+)
+
+type entry struct {
+	Seq    uint64                        `json:"seq"`
+	Op     operation                     `json:"op"`
+	Upsert *pinecone.UpsertVectorsParams `json:"upsert,omitempty"`
+	Update *pinecone.UpdateVectorParams  `json:"update,omitempty"`
+	Delete *pinecone.DeleteVectorsParams `json:"delete,omitempty"`
+
+	// the outcome of the write, set once it is acknowledged
+	upserted int
+	err      error
+}
+
+type segment struct {
+	path string
+	size int64
+	// the sequence number of the last write of the segment, 0 when it has
+	// none
+	last uint64
+}
+
+// Log is a write-ahead log in front of the writes of an index client. It is
+// safe for concurrent use.
+type Log struct {
+	ic   *pinecone.IndexClient
+	opts Options
+
+	// serializes the requests, held while sending
+	sendMu sync.Mutex
+
+	mu       sync.Mutex
+	segments []*segment
+	active   *os.File
+	nextSeq  uint64
+	acked    uint64
+	pending  []*entry
+	dirty    bool
+	closed   bool
+	stopSync chan struct{}
+	syncDone chan struct{}
+}
+
+// Open opens the log of a directory, then replays the writes left
+// unacknowledged by a previous process. When the replay fails, the log is
+// closed and the error returned, the writes being kept for the next Open.
+func Open(ctx context.Context, ic *pinecone.IndexClient, opts Options) (*Log, error) {
+	if opts.Dir == "" {
+		return nil, fmt.Errorf("%w: dir is required", pinecone.ErrInvalidParams)
+	}
+	if opts.SegmentSize <= 0 {
+		opts.SegmentSize = defaultSegmentSize
+	}
+	if opts.SyncInterval <= 0 {
+		opts.SyncInterval = defaultSyncInterval
+	}
+
+	if err := os.MkdirAll(opts.Dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	l := &Log{ic: ic, opts: opts}
+	if err := l.load(); err != nil {
+		if l.active != nil {
+			l.active.Close()
+		}
+		return nil, err
+	}
+
+	if opts.Sync == SyncInterval {
+		l.stopSync = make(chan struct{})
+		l.syncDone = make(chan struct{})
+		go l.syncPeriodically()
+	}
+
+	if err := l.Replay(ctx); err != nil {
+		l.Close()
+		return nil, err
+	}
+
+	return l, nil
+}
+
+// load reads the segments of the directory, and opens the last one for
+// appending.
+func (l *Log) load() error {
+	matches, err := filepath.Glob(filepath.Join(l.opts.Dir, "*"+segmentExt))
+	if err != nil {
+		return err
+	}
+	sort.Strings(matches)
+
+	var entries []*entry
+	for i, path := range matches {
+		seg, segEntries, err := readSegment(path, i == len(matches)-1)
+		if err != nil {
+			return err
+		}
+
+		for _, e := range segEntries {
+			if e.Op == operationAck {
+				l.acked = max(l.acked, e.Seq)
+				continue
+			}
+
+			entries = append(entries, e)
+		}
+		l.segments = append(l.segments, seg)
+	}
+
+	for _, e := range entries {
+		l.nextSeq = max(l.nextSeq, e.Seq)
+		if e.Seq > l.acked {
+			l.pending = append(l.pending, e)
+		}
+	}
+	l.nextSeq = max(l.nextSeq, l.acked) + 1
+
+	if len(l.segments) == 0 {
+		return l.rotate()
+	}
+
+	last := l.segments[len(l.segments)-1]
+	l.active, err = os.OpenFile(last.path, os.O_WRONLY|os.O_APPEND, 0)
+
+	return err
+}
+
+// readSegment reads the records of a segment. An invalid record of the last
+// segment is the tail torn by a crash, and is truncated with what follows.
+func readSegment(path string, last bool) (*segment, []*entry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+
+	seg := &segment{path: path}
+	var entries []*entry
+
+	r := bufio.NewReader(f)
+	for {
+		e, n, err := readRecord(r)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			if !last {
+				return nil, nil, fmt.Errorf("%w: %s at offset %d: %v", ErrCorrupt, filepath.Base(path), seg.size, err)
+			}
+			if err := os.Truncate(path, seg.size); err != nil {
+				return nil, nil, err
+			}
+
+			break
+		}
+
+		seg.size += n
+		if e.Op != operationAck {
+			seg.last = e.Seq
+		}
+		entries = append(entries, e)
+	}
+
+	return seg, entries, nil
+}
+
+// readRecord reads a record, made of the length and CRC-32C checksum of its
+// payload followed by the payload, and returns it with its size.
+func readRecord(r io.Reader) (*entry, int64, error) {
+	var header [headerSize]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, 0, err
+	}
+
+	length := binary.LittleEndian.Uint32(header[0:4])
+	checksum := binary.LittleEndian.Uint32(header[4:8])
+	if length > maxRecordSize {
+		return nil, 0, fmt.Errorf("record of %d bytes", length)
+	}
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, 0, err
+	}
+	if crc32.Checksum(payload, crcTable) != checksum {
+		return nil, 0, errors.New("checksum mismatch")
+	}
+
+	var e entry
+	if err := json.Unmarshal(payload, &e); err != nil {
+		return nil, 0, err
+	}
+
+	return &e, headerSize + int64(length), nil
+}
+
+func encodeRecord(e *entry) ([]byte, error) {
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+
+	record := make([]byte, headerSize+len(payload))
+	binary.LittleEndian.PutUint32(record[0:4], uint32(len(payload)))
+	binary.LittleEndian.PutUint32(record[4:8], crc32.Checksum(payload, crcTable))
+	copy(record[headerSize:], payload)
+
+	return record, nil
+}
+
+// UpsertVectors logs an upsert, then sends it once the writes logged before
+// it are acknowledged.
+func (l *Log) UpsertVectors(ctx context.Context, params pinecone.UpsertVectorsParams) (*pinecone.UpsertVectorsResponse, error) {
+	e, err := l.write(ctx, &entry{Op: operationUpsert, Upsert: &params})
+	if err != nil {
+		return nil, err
+	}
+
+	return &pinecone.UpsertVectorsResponse{UpsertedCount: e.upserted}, nil
+}
+
+// UpdateVector logs an update, then sends it once the writes logged before it
+// are acknowledged.
+func (l *Log) UpdateVector(ctx context.Context, params pinecone.UpdateVectorParams) error {
+	_, err := l.write(ctx, &entry{Op: operationUpdate, Update: &params})
+	return err
+}
+
+// DeleteVectors logs a delete, then sends it once the writes logged before it
+// are acknowledged.
+func (l *Log) DeleteVectors(ctx context.Context, params pinecone.DeleteVectorsParams) error {
+	_, err := l.write(ctx, &entry{Op: operationDelete, Delete: &params})
+	return err
+}
+
+// write appends a write to the log, then sends the pending writes up to it.
+// Sending stops at the first failure, returned with ErrPending.
+func (l *Log) write(ctx context.Context, e *entry) (*entry, error) {
+	if err := l.append(e); err != nil {
+		return nil, err
+	}
+
+	if err := l.send(ctx, e.Seq); err != nil {
+		return nil, err
+	}
+
+	return e, e.err
+}
+
+// Replay sends every pending write, in order. It stops at the first failure,
+// returned with ErrPending.
+func (l *Log) Replay(ctx context.Context) error {
+	l.mu.Lock()
+	var last uint64
+	if len(l.pending) > 0 {
+		last = l.pending[len(l.pending)-1].Seq
+	}
+	l.mu.Unlock()
+
+	return l.send(ctx, last)
+}
+
+// Pending returns the number of writes logged but not acknowledged.
+func (l *Log) Pending() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return len(l.pending)
+}
+
+func (l *Log) append(e *entry) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.closed {
+		return ErrClosed
+	}
+
+	// a segment holds at least one write, so that the next segment is named
+	// after a later sequence number
+	if seg := l.segments[len(l.segments)-1]; seg.size >= l.opts.SegmentSize && seg.last != 0 {
+		if err := l.rotate(); err != nil {
+			return err
+		}
+	}
+
+	e.Seq = l.nextSeq
+	if err := l.appendRecord(e, l.opts.Sync == SyncAlways); err != nil {
+		return err
+	}
+
+	l.nextSeq++
+	l.segments[len(l.segments)-1].last = e.Seq
+	l.pending = append(l.pending, e)
+
+	return nil
+}
+
+// appendRecord appends a record to the active segment, and flushes it when
+// sync is set.
+func (l *Log) appendRecord(e *entry, sync bool) error {
+	record, err := encodeRecord(e)
+	if err != nil {
+		return err
+	}
+
+	seg := l.segments[len(l.segments)-1]
+	if _, err := l.active.Write(record); err != nil {
+		// drop a partial record, so that the next ones stay readable
+		if truncateErr := l.active.Truncate(seg.size); truncateErr != nil {
+			return errors.Join(err, truncateErr)
+		}
+		return err
+	}
+	seg.size += int64(len(record))
+
+	if sync {
+		return l.active.Sync()
+	}
+
+	l.dirty = true
+
+	return nil
+}
+
+// rotate starts a new segment, named after the next sequence number, which
+// begins with the acknowledgement of the previous ones so that they can be
+// removed.
+func (l *Log) rotate() error {
+	if l.active != nil {
+		if err := l.active.Sync(); err != nil {
+			return err
+		}
+		if err := l.active.Close(); err != nil {
+			return err
+		}
+		l.active = nil
+	}
+
+	path := filepath.Join(l.opts.Dir, fmt.Sprintf("%020d%s", l.nextSeq, segmentExt))
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND|os.O_EXCL, 0o644)
+	if err != nil {
+		return err
+	}
+
+	l.active = f
+	l.segments = append(l.segments, &segment{path: path})
+	if err := l.appendRecord(&entry{Seq: l.acked, Op: operationAck}, true); err != nil {
+		return err
+	}
+
+	return l.syncDir()
+}
+
+func (l *Log) syncDir() error {
+	if l.opts.Sync == SyncNever {
+		return nil
+	}
+
+	dir, err := os.Open(l.opts.Dir)
+	if err != nil {
+		return err
+	}
+	defer dir.Close()
+
+	return dir.Sync()
+}
+
+// send sends the pending writes up to a sequence number, in order.
+func (l *Log) send(ctx context.Context, upTo uint64) error {
+	l.sendMu.Lock()
+	defer l.sendMu.Unlock()
+
+	for {
+		l.mu.Lock()
+		if len(l.pending) == 0 || l.pending[0].Seq > upTo {
+			l.mu.Unlock()
+			return nil
+		}
+		e := l.pending[0]
+		l.mu.Unlock()
+
+		err := l.sendEntry(ctx, e)
+		if err != nil && !errors.Is(err, pinecone.ErrInvalidParams) {
+			return fmt.Errorf("%w: %w", ErrPending, err)
+		}
+
+		// a write rejected as invalid would be rejected on every replay, so
+		// it is acknowledged and its error returned to its caller
+		e.err = err
+		if err := l.ack(e); err != nil {
+			return err
+		}
+	}
+}
+
+func (l *Log) sendEntry(ctx context.Context, e *entry) error {
+	switch e.Op {
+	case operationUpsert:
+		resp, err := l.ic.UpsertVectors(ctx, *e.Upsert)
+		if err != nil {
+			return err
+		}
+
+		e.upserted = resp.UpsertedCount
+		return nil
+	case operationUpdate:
+		return l.ic.UpdateVector(ctx, *e.Update)
+	case operationDelete:
+		return l.ic.DeleteVectors(ctx, *e.Delete)
+	default:
+		return fmt.Errorf("%w: unknown operation %q", ErrCorrupt, e.Op)
+	}
+}
+
+// ack records the acknowledgement of the first pending write, then removes
+// the segments whose writes are all acknowledged. Acknowledgements are not
+// flushed, as losing one only sends its write again.
+func (l *Log) ack(e *entry) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.pending = l.pending[1:]
+	l.acked = e.Seq
+
+	if l.closed {
+		return ErrClosed
+	}
+	if err := l.appendRecord(&entry{Seq: e.Seq, Op: operationAck}, false); err != nil {
+		return err
+	}
+
+	return l.compact()
+}
+
+// compact removes the segments, other than the active one, whose writes are
+// all acknowledged.
+func (l *Log) compact() error {
+	removed := 0
+	for _, seg := range l.segments[:len(l.segments)-1] {
+		if seg.last > l.acked {
+			break
+		}
+		if err := os.Remove(seg.path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+
+		removed++
+	}
+	if removed == 0 {
+		return nil
+	}
+
+	l.segments = l.segments[removed:]
+
+	return l.syncDir()
+}
+
+func (l *Log) syncPeriodically() {
+	defer close(l.syncDone)
+
+	ticker := time.NewTicker(l.opts.SyncInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-l.stopSync:
+			return
+		case <-ticker.C:
+			l.mu.Lock()
+			if l.dirty && !l.closed {
+				if err := l.active.Sync(); err == nil {
+					l.dirty = false
+				}
+			}
+			l.mu.Unlock()
+		}
+	}
+}
+
+// Close flushes and closes the active segment. Pending writes are kept for
+// the next Open.
+func (l *Log) Close() error {
+	l.mu.Lock()
+	if l.closed {
+		l.mu.Unlock()
+		return ErrClosed
+	}
+	l.closed = true
+	l.mu.Unlock()
+
+	if l.stopSync != nil {
+		close(l.stopSync)
+		<-l.syncDone
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return errors.Join(l.active.Sync(), l.active.Close())
+}
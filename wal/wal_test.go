@@ -0,0 +1,452 @@
+package wal
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/nekomeowww/go-pinecone"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeIndex records the writes it receives, in order.
+type fakeIndex struct {
+	mu       sync.Mutex
+	requests []string
+	// requests fail with 503 while set
+	down atomic.Bool
+}
+
+func newFakeIndex(t *testing.T) (*fakeIndex, *pinecone.IndexClient) {
+	index := &fakeIndex{}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/vectors/upsert", func(w http.ResponseWriter, r *http.Request) {
+		var params pinecone.UpsertVectorsParams
+		if !index.receive(w, r, &params) {
+			return
+		}
+
+		ids := make([]string, 0, len(params.Vectors))
+		for _, v := range params.Vectors {
+			ids = append(ids, v.ID)
+		}
+		index.record("upsert:" + strings.Join(ids, ","))
+
+		_ = json.NewEncoder(w).Encode(pinecone.UpsertVectorsResponse{UpsertedCount: len(params.Vectors)})
+	})
+	mux.HandleFunc("/vectors/update", func(w http.ResponseWriter, r *http.Request) {
+		var params pinecone.UpdateVectorParams
+		if !index.receive(w, r, &params) {
+			return
+		}
+
+		index.record("update:" + params.ID)
+		_, _ = w.Write([]byte("{}"))
+	})
+	mux.HandleFunc("/vectors/delete", func(w http.ResponseWriter, r *http.Request) {
+		var params pinecone.DeleteVectorsParams
+		if !index.receive(w, r, &params) {
+			return
+		}
+
+		index.record("delete:" + strings.Join(params.IDs, ","))
+		_, _ = w.Write([]byte("{}"))
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	ic, err := pinecone.NewIndexClient(pinecone.WithAPIKey("key"), pinecone.WithBaseURL(server.URL), pinecone.WithControllerBaseURL(server.URL))
+	require.NoError(t, err)
+
+	return index, ic
+}
+
+func (f *fakeIndex) receive(w http.ResponseWriter, r *http.Request, params any) bool {
+	if f.down.Load() {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return false
+	}
+	if err := json.NewDecoder(r.Body).Decode(params); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return false
+	}
+
+	return true
+}
+
+func (f *fakeIndex) record(request string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.requests = append(f.requests, request)
+}
+
+// take returns the recorded requests and forgets them.
+func (f *fakeIndex) take() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	requests := f.requests
+	f.requests = nil
+
+	return requests
+}
+
+func upsert(id string) pinecone.UpsertVectorsParams {
+	return pinecone.UpsertVectorsParams{Vectors: []*pinecone.Vector{{ID: id, Values: []float32{1, 1}}}}
+}
+
+// crash abandons a log without closing it cleanly, as a crashed process
+// would. Only the file descriptor is released.
+func crash(l *Log) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.closed = true
+	l.active.Close()
+}
+
+func segmentFiles(t *testing.T, dir string) []string {
+	matches, err := filepath.Glob(filepath.Join(dir, "*"+segmentExt))
+	require.NoError(t, err)
+
+	return matches
+}
+
+func TestLog_Write(t *testing.T) {
+	for name, policy := range map[string]SyncPolicy{"SyncAlways": SyncAlways, "SyncInterval": SyncInterval, "SyncNever": SyncNever} {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			index, ic := newFakeIndex(t)
+			dir := t.TempDir()
+			ctx := context.Background()
+
+			l, err := Open(ctx, ic, Options{Dir: dir, Sync: policy})
+			require.NoError(err)
+
+			resp, err := l.UpsertVectors(ctx, upsert("a"))
+			require.NoError(err)
+			assert.Equal(1, resp.UpsertedCount)
+			require.NoError(l.UpdateVector(ctx, pinecone.UpdateVectorParams{ID: "a", SetMetadata: map[string]any{"k": "v"}}))
+			require.NoError(l.DeleteVectors(ctx, pinecone.DeleteVectorsParams{IDs: []string{"a"}}))
+
+			assert.Equal([]string{"upsert:a", "update:a", "delete:a"}, index.take())
+			assert.Zero(l.Pending())
+			require.NoError(l.Close())
+			require.ErrorIs(l.Close(), ErrClosed)
+
+			_, err = l.UpsertVectors(ctx, upsert("b"))
+			require.ErrorIs(err, ErrClosed)
+
+			// every write was acknowledged, nothing is replayed
+			l, err = Open(ctx, ic, Options{Dir: dir, Sync: policy})
+			require.NoError(err)
+			assert.Empty(index.take())
+
+			_, err = l.UpsertVectors(ctx, upsert("b"))
+			require.NoError(err)
+			assert.Equal([]string{"upsert:b"}, index.take())
+			require.NoError(l.Close())
+		})
+	}
+}
+
+func TestLog_Outage(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	index, ic := newFakeIndex(t)
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	l, err := Open(ctx, ic, Options{Dir: dir})
+	require.NoError(err)
+
+	index.down.Store(true)
+	_, err = l.UpsertVectors(ctx, upsert("a"))
+	require.ErrorIs(err, ErrPending)
+	require.ErrorIs(err, pinecone.ErrRequestFailed)
+	require.ErrorIs(l.DeleteVectors(ctx, pinecone.DeleteVectorsParams{IDs: []string{"a"}}), ErrPending)
+	assert.Equal(2, l.Pending())
+
+	// the next write sends the pending ones first
+	index.down.Store(false)
+	_, err = l.UpsertVectors(ctx, upsert("b"))
+	require.NoError(err)
+	assert.Equal([]string{"upsert:a", "delete:a", "upsert:b"}, index.take())
+	assert.Zero(l.Pending())
+
+	index.down.Store(true)
+	require.ErrorIs(l.UpdateVector(ctx, pinecone.UpdateVectorParams{ID: "b", Values: []float32{2, 2}}), ErrPending)
+	_, err = l.UpsertVectors(ctx, upsert("c"))
+	require.ErrorIs(err, ErrPending)
+	crash(l)
+
+	// a process opening the log while Pinecone is down keeps the writes
+	_, err = Open(ctx, ic, Options{Dir: dir})
+	require.ErrorIs(err, ErrPending)
+
+	index.down.Store(false)
+	l, err = Open(ctx, ic, Options{Dir: dir})
+	require.NoError(err)
+	assert.Equal([]string{"update:b", "upsert:c"}, index.take())
+	assert.Zero(l.Pending())
+	require.NoError(l.Close())
+}
+
+func TestLog_Concurrent(t *testing.T) {
+	index, ic := newFakeIndex(t)
+	ctx := context.Background()
+
+	l, err := Open(ctx, ic, Options{Dir: t.TempDir(), SegmentSize: 512})
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 10; i++ {
+				_, err := l.UpsertVectors(ctx, upsert(fmt.Sprintf("%d-%d", g, i)))
+				assert.NoError(t, err)
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	assert.Len(t, index.take(), 80)
+	assert.Zero(t, l.Pending())
+	require.NoError(t, l.Close())
+}
+
+func TestLog_InvalidWrite(t *testing.T) {
+	index, ic := newFakeIndex(t)
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	l, err := Open(ctx, ic, Options{Dir: dir})
+	require.NoError(t, err)
+
+	// rejected writes are acknowledged, so that they do not block the log
+	_, err = l.UpsertVectors(ctx, upsert(""))
+	require.ErrorIs(t, err, pinecone.ErrInvalidParams)
+	require.NotErrorIs(t, err, ErrPending)
+	assert.Zero(t, l.Pending())
+
+	_, err = l.UpsertVectors(ctx, upsert("a"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"upsert:a"}, index.take())
+	crash(l)
+
+	l, err = Open(ctx, ic, Options{Dir: dir})
+	require.NoError(t, err)
+	assert.Empty(t, index.take())
+	require.NoError(t, l.Close())
+}
+
+func TestLog_Compaction(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	index, ic := newFakeIndex(t)
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	// every write starts a new segment
+	l, err := Open(ctx, ic, Options{Dir: dir, SegmentSize: 1})
+	require.NoError(err)
+
+	index.down.Store(true)
+	for i := 0; i < 5; i++ {
+		_, err := l.UpsertVectors(ctx, upsert(fmt.Sprint(i)))
+		require.ErrorIs(err, ErrPending)
+	}
+	assert.Len(segmentFiles(t, dir), 5)
+
+	index.down.Store(false)
+	require.NoError(l.Replay(ctx))
+	assert.Len(index.take(), 5)
+	assert.Len(segmentFiles(t, dir), 1)
+
+	for i := 0; i < 5; i++ {
+		_, err := l.UpsertVectors(ctx, upsert(fmt.Sprint(i)))
+		require.NoError(err)
+		assert.Len(segmentFiles(t, dir), 1)
+	}
+	assert.Len(index.take(), 5)
+	require.NoError(l.Close())
+
+	// the acknowledgements survive the removal of the segments
+	l, err = Open(ctx, ic, Options{Dir: dir, SegmentSize: 1})
+	require.NoError(err)
+	assert.Empty(index.take())
+	require.NoError(l.Close())
+}
+
+// record is a record of a segment, with the offset it ends at.
+type record struct {
+	entry *entry
+	end   int64
+}
+
+func readRecords(t *testing.T, data []byte) []record {
+	var records []record
+
+	r := bufio.NewReader(bytes.NewReader(data))
+	var offset int64
+	for {
+		e, n, err := readRecord(r)
+		if err != nil {
+			break
+		}
+
+		offset += n
+		records = append(records, record{entry: e, end: offset})
+	}
+	require.Equal(t, int64(len(data)), offset)
+
+	return records
+}
+
+// TestLog_Crash simulates a crash at every byte of a segment, with a write
+// torn at that byte, then checks that opening the log replays the writes
+// completely appended and not acknowledged, in order, and that the log stays
+// usable.
+func TestLog_Crash(t *testing.T) {
+	for name, down := range map[string]bool{"Unacknowledged": true, "Acknowledged": false} {
+		t.Run(name, func(t *testing.T) {
+			index, ic := newFakeIndex(t)
+			dir := t.TempDir()
+			ctx := context.Background()
+
+			l, err := Open(ctx, ic, Options{Dir: dir, Sync: SyncNever})
+			require.NoError(t, err)
+
+			index.down.Store(down)
+			_, _ = l.UpsertVectors(ctx, upsert("a"))
+			_ = l.UpdateVector(ctx, pinecone.UpdateVectorParams{ID: "a", Values: []float32{2, 2}})
+			_ = l.DeleteVectors(ctx, pinecone.DeleteVectorsParams{IDs: []string{"a"}})
+			_, _ = l.UpsertVectors(ctx, upsert("b"))
+			require.NoError(t, l.Close())
+			index.down.Store(false)
+			index.take()
+
+			files := segmentFiles(t, dir)
+			require.Len(t, files, 1)
+			data, err := os.ReadFile(files[0])
+			require.NoError(t, err)
+			records := readRecords(t, data)
+
+			for offset := 0; offset <= len(data); offset++ {
+				var acked uint64
+				for _, r := range records {
+					if r.end <= int64(offset) && r.entry.Op == operationAck {
+						acked = r.entry.Seq
+					}
+				}
+				var replayed []string
+				for _, r := range records {
+					if r.end <= int64(offset) && r.entry.Op != operationAck && r.entry.Seq > acked {
+						replayed = append(replayed, requestOf(r.entry))
+					}
+				}
+
+				crashed := t.TempDir()
+				// the torn write holds garbage past the crash
+				torn := append(append([]byte(nil), data[:offset]...), bytes.Repeat([]byte{0xff}, 3)...)
+				require.NoError(t, os.WriteFile(filepath.Join(crashed, filepath.Base(files[0])), torn, 0o644))
+
+				l, err := Open(ctx, ic, Options{Dir: crashed})
+				require.NoError(t, err, "offset %d", offset)
+				assert.Equal(t, replayed, index.take(), "offset %d", offset)
+
+				// the log continues after the last complete record
+				_, err = l.UpsertVectors(ctx, upsert("c"))
+				require.NoError(t, err, "offset %d", offset)
+				require.NoError(t, l.Close())
+
+				l, err = Open(ctx, ic, Options{Dir: crashed})
+				require.NoError(t, err, "offset %d", offset)
+				require.NoError(t, l.Close())
+				assert.Equal(t, []string{"upsert:c"}, index.take(), "offset %d", offset)
+			}
+		})
+	}
+}
+
+func requestOf(e *entry) string {
+	switch e.Op {
+	case operationUpsert:
+		return "upsert:" + e.Upsert.Vectors[0].ID
+	case operationUpdate:
+		return "update:" + e.Update.ID
+	default:
+		return "delete:" + strings.Join(e.Delete.IDs, ",")
+	}
+}
+
+func TestLog_Corrupt(t *testing.T) {
+	index, ic := newFakeIndex(t)
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	l, err := Open(ctx, ic, Options{Dir: dir, SegmentSize: 1})
+	require.NoError(t, err)
+
+	index.down.Store(true)
+	for i := 0; i < 3; i++ {
+		_, err := l.UpsertVectors(ctx, upsert(fmt.Sprint(i)))
+		require.ErrorIs(t, err, ErrPending)
+	}
+	require.NoError(t, l.Close())
+	index.down.Store(false)
+
+	files := segmentFiles(t, dir)
+	require.Len(t, files, 3)
+
+	flip := func(path string) {
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		data[len(data)-2] ^= 0xff
+		require.NoError(t, os.WriteFile(path, data, 0o644))
+	}
+
+	// a flipped bit in the last segment is a torn write, and is dropped
+	flip(files[2])
+	l, err = Open(ctx, ic, Options{Dir: dir})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"upsert:0", "upsert:1"}, index.take())
+	require.NoError(t, l.Close())
+
+	// an older one is not
+	index.down.Store(true)
+	l, err = Open(ctx, ic, Options{Dir: dir, SegmentSize: 1})
+	require.NoError(t, err)
+	for i := 3; i < 5; i++ {
+		_, err := l.UpsertVectors(ctx, upsert(fmt.Sprint(i)))
+		require.ErrorIs(t, err, ErrPending)
+	}
+	require.NoError(t, l.Close())
+	index.down.Store(false)
+
+	files = segmentFiles(t, dir)
+	require.Len(t, files, 2)
+	flip(files[0])
+	_, err = Open(ctx, ic, Options{Dir: dir})
+	require.ErrorIs(t, err, ErrCorrupt)
+}
@@ -0,0 +1,398 @@
+package pinecone
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	defaultWriteBufferMaxVectors  = 100
+	defaultWriteBufferMaxBytes    = 2 * 1024 * 1024
+	defaultWriteBufferMaxInterval = time.Second
+
+	defaultRetryMaxAttempts    = 3
+	defaultRetryInitialBackoff = 200 * time.Millisecond
+	defaultRetryMaxBackoff     = 5 * time.Second
+)
+
+// ErrWriteBufferClosed is returned when writing to a closed WriteBuffer.
+var ErrWriteBufferClosed = errors.New("write buffer closed")
+
+// RetryPolicy represents how failed requests are retried, with an
+// exponential backoff between attempts. Only requests that were rate
+// limited, failed with a server error, or failed to get a response are
+// retried, and never once their context is done.
+type RetryPolicy struct {
+	// The maximum number of attempts, including the first one. Defaults to 3,
+	// 1 disables retries.
+	MaxAttempts int
+	// The backoff after the first attempt, doubled after every attempt.
+	// Defaults to 200 milliseconds.
+	InitialBackoff time.Duration
+	// The maximum backoff. Defaults to 5 seconds.
+	MaxBackoff time.Duration
+}
+
+// do calls fn until it succeeds, fails with an error that is not retryable,
+// or the attempts are exhausted, and returns the number of attempts.
+func (p RetryPolicy) do(ctx context.Context, fn func() error) (int, error) {
+	maxAttempts := p.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = defaultRetryMaxAttempts
+	}
+	backoff := p.InitialBackoff
+	if backoff <= 0 {
+		backoff = defaultRetryInitialBackoff
+	}
+	maxBackoff := p.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = defaultRetryMaxBackoff
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= maxAttempts || !retryable(err) || ctx.Err() != nil {
+			return attempt, err
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return attempt, err
+		case <-timer.C:
+		}
+
+		backoff = min(2*backoff, maxBackoff)
+	}
+}
+
+// retryable reports whether a request failing with err may succeed when sent
+// again: when the Pinecone API responded with 429 or a 5xx status code, or
+// when the request failed in transport.
+func retryable(err error) bool {
+	var requestErr *RequestError
+	if errors.As(err, &requestErr) {
+		return requestErr.StatusCode == http.StatusTooManyRequests || requestErr.StatusCode >= http.StatusInternalServerError
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// WriteOperation is the operation of a batch of a WriteBuffer.
+type WriteOperation string
+
+const (
+	WriteOperationUpsert WriteOperation = "upsert"
+	WriteOperationDelete WriteOperation = "delete"
+)
+
+// WriteBatchResult represents the outcome of a batch sent by a WriteBuffer.
+type WriteBatchResult struct {
+	Namespace string
+	Operation WriteOperation
+	// The IDs of the vectors upserted or deleted by the batch.
+	IDs []string
+	// The number of requests sent, including retries.
+	Attempts int
+	// The error of the last attempt, nil when the batch succeeded.
+	Err error
+}
+
+// WriteBufferOptions represents the options of a WriteBuffer.
+type WriteBufferOptions struct {
+	// A namespace is flushed once it holds this many pending writes. Also the
+	// maximum number of vectors per request. Defaults to 100.
+	MaxVectors int
+	// A namespace is flushed once its pending upserts reach this many bytes
+	// of JSON. Also the maximum size of an upsert request. Defaults to 2 MiB,
+	// the limit of the Pinecone API.
+	MaxBytes int
+	// Every namespace with pending writes is flushed at this interval.
+	// Defaults to 1 second.
+	MaxInterval time.Duration
+	// The retry policy of the requests.
+	Retry RetryPolicy
+	// Called with the outcome of every batch, from the goroutine sending it.
+	OnBatch func(result WriteBatchResult)
+}
+
+// WriteBuffer batches single upserts and deletes into fewer requests. Writes
+// are coalesced per namespace, where the last write of an ID wins, and sent
+// once a namespace reaches the size or byte threshold, or at the flush
+// interval. The writes of a namespace are sent by one flush at a time, so
+// that they are applied in order.
+//
+// A WriteBuffer is safe for concurrent use. It must be closed to send the
+// remaining writes and release its goroutine.
+type WriteBuffer struct {
+	ic   *IndexClient
+	opts WriteBufferOptions
+
+	// the context of the requests, cancelled when closing times out
+	ctx    context.Context
+	cancel context.CancelFunc
+	stop   chan struct{}
+	wg     sync.WaitGroup
+
+	mu         sync.Mutex
+	namespaces map[string]*bufferedNamespace
+	closed     bool
+	closeErrs  []error
+}
+
+type bufferedNamespace struct {
+	name string
+	// pending writes by ID, a nil vector being a delete
+	pending  map[string]bufferedWrite
+	bytes    int
+	flushing bool
+	// whether a flush was requested while another one was in flight
+	due bool
+}
+
+type bufferedWrite struct {
+	vector *Vector
+	size   int
+}
+
+// NewWriteBuffer creates a WriteBuffer writing to the index of the client.
+func NewWriteBuffer(ic *IndexClient, opts WriteBufferOptions) *WriteBuffer {
+	if opts.MaxVectors <= 0 {
+		opts.MaxVectors = defaultWriteBufferMaxVectors
+	}
+	if opts.MaxBytes <= 0 {
+		opts.MaxBytes = defaultWriteBufferMaxBytes
+	}
+	if opts.MaxInterval <= 0 {
+		opts.MaxInterval = defaultWriteBufferMaxInterval
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	b := &WriteBuffer{
+		ic:         ic,
+		opts:       opts,
+		ctx:        ctx,
+		cancel:     cancel,
+		stop:       make(chan struct{}),
+		namespaces: make(map[string]*bufferedNamespace),
+	}
+
+	go b.tick()
+
+	return b
+}
+
+// Add buffers the upsert of a vector into a namespace, replacing any pending
+// write of the same ID. The vector must not be modified afterwards.
+func (b *WriteBuffer) Add(namespace string, vector *Vector) error {
+	if err := validateUpsertVectorsParams(UpsertVectorsParams{Vectors: []*Vector{vector}}); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(vector)
+	if err != nil {
+		return err
+	}
+
+	return b.write(namespace, vector.ID, bufferedWrite{vector: vector, size: len(data)})
+}
+
+// Delete buffers the deletion of a vector from a namespace, replacing any
+// pending write of the same ID.
+func (b *WriteBuffer) Delete(namespace, id string) error {
+	if err := validateVectorID(id); err != nil {
+		return err
+	}
+
+	return b.write(namespace, id, bufferedWrite{})
+}
+
+func (b *WriteBuffer) write(namespace, id string, w bufferedWrite) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return ErrWriteBufferClosed
+	}
+
+	ns, ok := b.namespaces[namespace]
+	if !ok {
+		ns = &bufferedNamespace{name: namespace, pending: make(map[string]bufferedWrite)}
+		b.namespaces[namespace] = ns
+	}
+
+	ns.bytes += w.size - ns.pending[id].size
+	ns.pending[id] = w
+
+	if b.full(ns) {
+		b.flushLocked(ns)
+	}
+
+	return nil
+}
+
+func (b *WriteBuffer) full(ns *bufferedNamespace) bool {
+	return len(ns.pending) >= b.opts.MaxVectors || ns.bytes >= b.opts.MaxBytes
+}
+
+// tick flushes every namespace at the flush interval, until the buffer is
+// closed.
+func (b *WriteBuffer) tick() {
+	ticker := time.NewTicker(b.opts.MaxInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-b.stop:
+			return
+		case <-ticker.C:
+			b.mu.Lock()
+			for _, ns := range b.namespaces {
+				b.flushLocked(ns)
+			}
+			b.mu.Unlock()
+		}
+	}
+}
+
+// flushLocked sends the pending writes of a namespace in the background, or
+// marks the namespace as due when a flush is already in flight.
+func (b *WriteBuffer) flushLocked(ns *bufferedNamespace) {
+	if len(ns.pending) == 0 {
+		return
+	}
+	if ns.flushing {
+		ns.due = true
+		return
+	}
+
+	pending := ns.pending
+	ns.pending = make(map[string]bufferedWrite)
+	ns.bytes = 0
+	ns.flushing = true
+	ns.due = false
+
+	b.wg.Add(1)
+	go b.send(ns, pending)
+}
+
+// send sends the writes of a flush in batches, then starts the next flush of
+// the namespace if it is due.
+func (b *WriteBuffer) send(ns *bufferedNamespace, pending map[string]bufferedWrite) {
+	defer b.wg.Done()
+
+	var (
+		vectors   []*Vector
+		vectorIDs []string
+		bytes     int
+		deletes   []string
+	)
+	flushUpserts := func() {
+		if len(vectors) > 0 {
+			b.sendBatch(ns.name, WriteOperationUpsert, vectorIDs, func() error {
+				_, err := b.ic.UpsertVectors(b.ctx, UpsertVectorsParams{Vectors: vectors, Namespace: ns.name})
+				return err
+			})
+		}
+		vectors, vectorIDs, bytes = nil, nil, 0
+	}
+
+	for id, w := range pending {
+		if w.vector == nil {
+			deletes = append(deletes, id)
+			continue
+		}
+
+		if len(vectors) > 0 && (len(vectors) >= b.opts.MaxVectors || bytes+w.size > b.opts.MaxBytes) {
+			flushUpserts()
+		}
+		vectors = append(vectors, w.vector)
+		vectorIDs = append(vectorIDs, id)
+		bytes += w.size
+	}
+	flushUpserts()
+
+	for start := 0; start < len(deletes); start += b.opts.MaxVectors {
+		ids := deletes[start:min(start+b.opts.MaxVectors, len(deletes))]
+		b.sendBatch(ns.name, WriteOperationDelete, ids, func() error {
+			return b.ic.DeleteVectors(b.ctx, DeleteVectorsParams{IDs: ids, Namespace: ns.name})
+		})
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ns.flushing = false
+	if ns.due || b.closed || b.full(ns) {
+		b.flushLocked(ns)
+	}
+}
+
+func (b *WriteBuffer) sendBatch(namespace string, operation WriteOperation, ids []string, fn func() error) {
+	attempts, err := b.opts.Retry.do(b.ctx, fn)
+
+	if err != nil {
+		b.mu.Lock()
+		if b.closed {
+			b.closeErrs = append(b.closeErrs, err)
+		}
+		b.mu.Unlock()
+	}
+
+	if b.opts.OnBatch != nil {
+		b.opts.OnBatch(WriteBatchResult{
+			Namespace: namespace,
+			Operation: operation,
+			IDs:       ids,
+			Attempts:  attempts,
+			Err:       err,
+		})
+	}
+}
+
+// Close stops accepting writes, and sends every pending write. When the
+// context is done first, the requests in flight are cancelled and the
+// remaining writes are dropped. Close returns the errors of the batches
+// failing while draining, the earlier ones being only reported to OnBatch.
+func (b *WriteBuffer) Close(ctx context.Context) error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return ErrWriteBufferClosed
+	}
+
+	b.closed = true
+	close(b.stop)
+	for _, ns := range b.namespaces {
+		b.flushLocked(ns)
+	}
+	b.mu.Unlock()
+
+	drained := make(chan struct{})
+	go func() {
+		b.wg.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+	case <-ctx.Done():
+		b.cancel()
+		<-drained
+		return ctx.Err()
+	}
+
+	b.cancel()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return errors.Join(b.closeErrs...)
+}
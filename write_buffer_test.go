@@ -0,0 +1,301 @@
+package pinecone
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// batchRecorder records the outcomes of the batches of a WriteBuffer.
+type batchRecorder struct {
+	mu      sync.Mutex
+	results []WriteBatchResult
+}
+
+func (r *batchRecorder) record(result WriteBatchResult) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.results = append(r.results, result)
+}
+
+func (r *batchRecorder) all() []WriteBatchResult {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]WriteBatchResult(nil), r.results...)
+}
+
+func TestWriteBuffer_Coalesce(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	index, server := newFakeIndexServer(t)
+	defer server.Close()
+
+	var recorder batchRecorder
+	b := NewWriteBuffer(newTestIndexClient(server.URL), WriteBufferOptions{MaxInterval: time.Hour, OnBatch: recorder.record})
+
+	require.NoError(b.Add("ns", &Vector{ID: "a", Values: []float32{1, 0}}))
+	require.NoError(b.Add("ns", &Vector{ID: "a", Values: []float32{0, 1}}))
+	require.NoError(b.Add("ns", &Vector{ID: "b", Values: []float32{1, 1}}))
+	require.NoError(b.Delete("ns", "b"))
+	require.NoError(b.Add("other", &Vector{ID: "a", Values: []float32{1, 1}}))
+	assert.Zero(index.count("ns"))
+
+	require.NoError(b.Close(context.Background()))
+	assert.Equal(1, index.count("ns"))
+	assert.Equal([]float32{0, 1}, index.vectors["ns"]["a"].Values)
+	assert.Equal(1, index.count("other"))
+
+	results := recorder.all()
+	require.Len(results, 3)
+	for _, result := range results {
+		assert.NoError(result.Err)
+		assert.Equal(1, result.Attempts)
+		assert.Len(result.IDs, 1)
+	}
+
+	require.ErrorIs(b.Add("ns", &Vector{ID: "c", Values: []float32{1, 1}}), ErrWriteBufferClosed)
+	require.ErrorIs(b.Delete("ns", "c"), ErrWriteBufferClosed)
+	require.ErrorIs(b.Close(context.Background()), ErrWriteBufferClosed)
+}
+
+func TestWriteBuffer_Thresholds(t *testing.T) {
+	t.Run("MaxVectors", func(t *testing.T) {
+		index, server := newFakeIndexServer(t)
+		defer server.Close()
+
+		var recorder batchRecorder
+		b := NewWriteBuffer(newTestIndexClient(server.URL), WriteBufferOptions{MaxVectors: 10, MaxInterval: time.Hour, OnBatch: recorder.record})
+
+		var wg sync.WaitGroup
+		for g := 0; g < 5; g++ {
+			wg.Add(1)
+			go func(g int) {
+				defer wg.Done()
+				for i := 0; i < 5; i++ {
+					assert.NoError(t, b.Add("ns", &Vector{ID: fmt.Sprintf("%d-%d", g, i), Values: []float32{1, 1}}))
+				}
+			}(g)
+		}
+		wg.Wait()
+
+		// the full batches are sent without waiting for the interval or Close
+		require.Eventually(t, func() bool { return index.count("ns") >= 20 }, time.Second, 5*time.Millisecond)
+
+		require.NoError(t, b.Close(context.Background()))
+		assert.Equal(t, 25, index.count("ns"))
+		for _, result := range recorder.all() {
+			assert.LessOrEqual(t, len(result.IDs), 10)
+		}
+	})
+
+	t.Run("MaxBytes", func(t *testing.T) {
+		index, server := newFakeIndexServer(t)
+		defer server.Close()
+
+		var recorder batchRecorder
+		b := NewWriteBuffer(newTestIndexClient(server.URL), WriteBufferOptions{MaxBytes: 200, MaxInterval: time.Hour, OnBatch: recorder.record})
+
+		for i := 0; i < 4; i++ {
+			require.NoError(t, b.Add("ns", &Vector{ID: fmt.Sprint(i), Values: []float32{1, 1}, Metadata: map[string]any{"text": "an example with some padding"}}))
+		}
+		require.Eventually(t, func() bool { return index.count("ns") >= 2 }, time.Second, 5*time.Millisecond)
+
+		require.NoError(t, b.Close(context.Background()))
+		assert.Equal(t, 4, index.count("ns"))
+		for _, result := range recorder.all() {
+			assert.LessOrEqual(t, len(result.IDs), 2)
+		}
+	})
+
+	t.Run("MaxInterval", func(t *testing.T) {
+		index, server := newFakeIndexServer(t)
+		defer server.Close()
+
+		b := NewWriteBuffer(newTestIndexClient(server.URL), WriteBufferOptions{MaxInterval: 10 * time.Millisecond})
+		defer b.Close(context.Background())
+
+		require.NoError(t, b.Add("ns", &Vector{ID: "a", Values: []float32{1, 1}}))
+		require.Eventually(t, func() bool { return index.count("ns") == 1 }, time.Second, 5*time.Millisecond)
+	})
+}
+
+func TestWriteBuffer_Ordering(t *testing.T) {
+	index, server := newFakeIndexServer(t)
+	defer server.Close()
+
+	// the first upsert is held until the delete of the same ID is buffered
+	release := make(chan struct{})
+	var upserts atomic.Int32
+	handler := server.Config.Handler
+	server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/vectors/upsert" && upserts.Add(1) == 1 {
+			<-release
+		}
+		handler.ServeHTTP(w, r)
+	})
+
+	b := NewWriteBuffer(newTestIndexClient(server.URL), WriteBufferOptions{MaxVectors: 1, MaxInterval: time.Hour})
+
+	require.NoError(t, b.Add("ns", &Vector{ID: "a", Values: []float32{1, 1}}))
+	require.NoError(t, b.Delete("ns", "a"))
+	close(release)
+
+	require.NoError(t, b.Close(context.Background()))
+	assert.Zero(t, index.count("ns"))
+}
+
+func TestWriteBuffer_Retry(t *testing.T) {
+	index, server := newFakeIndexServer(t)
+	defer server.Close()
+
+	var failures atomic.Int32
+	failures.Store(2)
+	handler := server.Config.Handler
+	server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failures.Add(-1) >= 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+
+	retry := RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}
+
+	t.Run("Recovered", func(t *testing.T) {
+		var recorder batchRecorder
+		b := NewWriteBuffer(newTestIndexClient(server.URL), WriteBufferOptions{MaxInterval: time.Hour, Retry: retry, OnBatch: recorder.record})
+
+		require.NoError(t, b.Add("ns", &Vector{ID: "a", Values: []float32{1, 1}}))
+		require.NoError(t, b.Close(context.Background()))
+
+		results := recorder.all()
+		require.Len(t, results, 1)
+		assert.NoError(t, results[0].Err)
+		assert.Equal(t, 3, results[0].Attempts)
+		assert.Equal(t, 1, index.count("ns"))
+	})
+
+	t.Run("Exhausted", func(t *testing.T) {
+		failures.Store(3)
+
+		var recorder batchRecorder
+		b := NewWriteBuffer(newTestIndexClient(server.URL), WriteBufferOptions{MaxInterval: time.Hour, Retry: retry, OnBatch: recorder.record})
+
+		require.NoError(t, b.Delete("ns", "a"))
+		require.ErrorIs(t, b.Close(context.Background()), ErrRequestFailed)
+
+		results := recorder.all()
+		require.Len(t, results, 1)
+		assert.ErrorIs(t, results[0].Err, ErrRequestFailed)
+		assert.Equal(t, WriteOperationDelete, results[0].Operation)
+		assert.Equal(t, 3, results[0].Attempts)
+		assert.Equal(t, 1, index.count("ns"))
+	})
+}
+
+func TestRetryPolicy(t *testing.T) {
+	retry := RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}
+
+	for _, tc := range []struct {
+		name       string
+		statusCode int
+		attempts   int
+	}{
+		{name: "RateLimited", statusCode: http.StatusTooManyRequests, attempts: 3},
+		{name: "ServerError", statusCode: http.StatusBadGateway, attempts: 3},
+		{name: "ClientError", statusCode: http.StatusBadRequest, attempts: 1},
+		{name: "NotFound", statusCode: http.StatusNotFound, attempts: 1},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tc.statusCode)
+			}))
+			defer server.Close()
+
+			ic := newTestIndexClient(server.URL)
+			attempts, err := retry.do(context.Background(), func() error {
+				return ic.DeleteVectors(context.Background(), DeleteVectorsParams{IDs: []string{"a"}})
+			})
+
+			var requestErr *RequestError
+			require.ErrorAs(t, err, &requestErr)
+			assert.Equal(t, tc.statusCode, requestErr.StatusCode)
+			assert.ErrorIs(t, err, ErrRequestFailed)
+			assert.Equal(t, tc.attempts, attempts)
+		})
+	}
+
+	t.Run("TransportError", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		server.Close()
+
+		ic := newTestIndexClient(server.URL)
+		attempts, err := retry.do(context.Background(), func() error {
+			return ic.DeleteVectors(context.Background(), DeleteVectorsParams{IDs: []string{"a"}})
+		})
+		require.Error(t, err)
+		assert.Equal(t, 3, attempts)
+	})
+
+	t.Run("InvalidParams", func(t *testing.T) {
+		attempts, err := retry.do(context.Background(), func() error {
+			return fmt.Errorf("%w: no IDs", ErrInvalidParams)
+		})
+		require.ErrorIs(t, err, ErrInvalidParams)
+		assert.Equal(t, 1, attempts)
+	})
+}
+
+func TestWriteBuffer_CloseTimeout(t *testing.T) {
+	_, server := newFakeIndexServer(t)
+	defer server.Close()
+
+	// the requests are held until the test ends
+	release := make(chan struct{})
+	defer close(release)
+	handler := server.Config.Handler
+	server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		handler.ServeHTTP(w, r)
+	})
+
+	var recorder batchRecorder
+	b := NewWriteBuffer(newTestIndexClient(server.URL), WriteBufferOptions{MaxInterval: time.Hour, OnBatch: recorder.record})
+	require.NoError(t, b.Add("ns", &Vector{ID: "a", Values: []float32{1, 1}}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	require.ErrorIs(t, b.Close(ctx), context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 2*time.Second)
+
+	results := recorder.all()
+	require.Len(t, results, 1)
+	assert.Error(t, results[0].Err)
+	assert.Equal(t, 1, results[0].Attempts)
+}
+
+func TestWriteBuffer_InvalidWrites(t *testing.T) {
+	_, server := newFakeIndexServer(t)
+	defer server.Close()
+
+	b := NewWriteBuffer(newTestIndexClient(server.URL), WriteBufferOptions{})
+	defer b.Close(context.Background())
+
+	require.ErrorIs(t, b.Add("ns", &Vector{Values: []float32{1, 1}}), ErrInvalidParams)
+	require.ErrorIs(t, b.Add("ns", nil), ErrInvalidParams)
+	require.ErrorIs(t, b.Delete("ns", ""), ErrInvalidParams)
+}